@@ -0,0 +1,136 @@
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+
+	"market/api/handler"
+	"market/config"
+	"market/pkg/blob"
+	"market/pkg/helper"
+	"market/pkg/logger"
+	"market/pkg/ratelimit"
+	"market/storage"
+)
+
+// NewApi registers the routes on r. API key requests count against limiter,
+// the one the gRPC server is given too, so a key has a single budget per
+// process whichever protocol it calls.
+func NewApi(r *gin.Engine, cfg *config.Config, strg storage.StorageI, blob blob.StorageI, limiter *ratelimit.Limiter, logger logger.LoggerI) {
+
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		helper.RegisterValidations(v)
+	}
+
+	handler := handler.NewHandler(cfg, strg, blob, limiter, logger)
+
+	r.Use(handler.RequestId)
+
+	r.GET("/openapi.json", serveSpec)
+	r.GET("/docs", serveDocs)
+
+	r.POST("/auth/login", handler.Login)
+	r.POST("/auth/refresh", handler.RefreshToken)
+
+	authorized := r.Group("", handler.Authorize, handler.Permit)
+
+	authorized.POST("/auth/logout", handler.Logout)
+	authorized.GET("/auth/me", handler.Me)
+
+	authorized.POST("/user", handler.Idempotent, handler.CreateUser)
+	authorized.GET("/user/:id", handler.GetByIdUser)
+	authorized.GET("/user", handler.GetListUser)
+	authorized.PUT("/user/:id", handler.UpdateUser)
+	authorized.DELETE("/user/:id", handler.DeleteUser)
+
+	authorized.POST("/api_key", handler.Idempotent, handler.CreateApiKey)
+	authorized.GET("/api_key/:id", handler.GetByIdApiKey)
+	authorized.GET("/api_key", handler.GetListApiKey)
+	authorized.POST("/api_key/:id/rotate", handler.RotateApiKey)
+	authorized.DELETE("/api_key/:id", handler.DeleteApiKey)
+
+	authorized.GET("/audit_log", handler.GetListAuditLog)
+
+	authorized.POST("/branch", handler.Idempotent, handler.CreateBranch)
+	authorized.GET("/branch/:id", handler.GetByIdBranch)
+	authorized.GET("/branch", handler.GetListBranch)
+	authorized.PUT("/branch/:id", handler.UpdateBranch)
+	authorized.DELETE("/branch/:id", handler.DeleteBranch)
+	authorized.POST("/branch/:id/restore", handler.RestoreBranch)
+
+	authorized.POST("/category", handler.Idempotent, handler.CreateCategory)
+	authorized.GET("/category/:id", handler.GetByIdCategory)
+	authorized.GET("/category", handler.GetListCategory)
+	authorized.PUT("/category/:id", handler.UpdateCategory)
+	authorized.DELETE("/category/:id", handler.DeleteCategory)
+	authorized.POST("/category/:id/restore", handler.RestoreCategory)
+
+	authorized.POST("/product", handler.Idempotent, handler.CreateProduct)
+	authorized.POST("/product/import", handler.Idempotent, handler.ImportProduct)
+	authorized.GET("/product/:id", handler.GetByIdProduct)
+	authorized.GET("/product", handler.GetListProduct)
+	authorized.PUT("/product/:id", handler.UpdateProduct)
+	authorized.PATCH("/product/:id", handler.PatchProduct)
+	authorized.DELETE("/product/:id", handler.DeleteProduct)
+	authorized.POST("/product/:id/restore", handler.RestoreProduct)
+
+	authorized.POST("/product/:id/barcode", handler.Idempotent, handler.CreateProductBarcode)
+	authorized.GET("/product/:id/barcode", handler.GetListProductBarcode)
+	authorized.DELETE("/product_barcode/:id", handler.DeleteProductBarcode)
+	authorized.POST("/product/:id/image", handler.Idempotent, handler.UploadProductImage)
+	authorized.PUT("/product/:id/image/order", handler.ReorderProductImage)
+	authorized.GET("/product_image/:id/file", handler.GetProductImageFile)
+	authorized.GET("/product_image/:id/thumbnail", handler.GetProductImageThumbnail)
+	authorized.DELETE("/product_image/:id", handler.DeleteProductImage)
+
+	authorized.GET("/barcode/:barcode", handler.GetByBarcodeProduct)
+	authorized.POST("/barcode/generate", handler.GenerateBarcode)
+	authorized.GET("/barcode/:barcode/image", handler.GetBarcodeImage)
+
+	authorized.POST("/label", handler.PrintLabels)
+
+	authorized.POST("/storage_coming", handler.Idempotent, handler.CreateStorageComing)
+	authorized.POST("/storage_coming/import", handler.Idempotent, handler.ImportInvoice)
+	authorized.GET("/storage_coming/:id", handler.GetByIdStorageComing)
+	authorized.GET("/storage_coming", handler.GetListStorageComing)
+	authorized.PUT("/storage_coming/:id", handler.Idempotent, handler.UpdateStorageComing)
+	authorized.DELETE("/storage_coming/:id", handler.DeleteStorageComing)
+	authorized.POST("/storage_coming/:id/restore", handler.RestoreStorageComing)
+	authorized.POST("/storage_coming/:id/start", handler.Idempotent, handler.StartStorageComing)
+	authorized.POST("/storage_coming/:id/receive", handler.Idempotent, handler.ReceiveStorageComing)
+	authorized.POST("/storage_coming/:id/finish", handler.Idempotent, handler.FinishStorageComing)
+	authorized.POST("/storage_coming/:id/cancel", handler.Idempotent, handler.CancelStorageComing)
+
+	authorized.POST("/storage_coming_product", handler.Idempotent, handler.CreateStorageComingProduct)
+	authorized.GET("/storage_coming_product/:id", handler.GetByIdStorageComingProduct)
+	authorized.GET("/storage_coming_product", handler.GetListStorageComingProduct)
+	authorized.PUT("/storage_coming_product/:id", handler.UpdateStorageComingProduct)
+	authorized.DELETE("/storage_coming_product/:id", handler.DeleteStorageComingProduct)
+	authorized.POST("/storage_coming_product/:id/restore", handler.RestoreStorageComingProduct)
+
+	authorized.GET("/remaining/:id", handler.GetByIdRemaining)
+	authorized.GET("/remaining", handler.GetListRemaining)
+	authorized.GET("/remaining/:id/batch", handler.GetListRemainingBatch)
+	authorized.POST("/remaining/consume", handler.Idempotent, handler.ConsumeRemaining)
+
+	authorized.GET("/serial_number/:id", handler.GetByIdSerialNumber)
+	authorized.GET("/serial_number", handler.GetListSerialNumber)
+	authorized.POST("/serial_number/:id/move", handler.MoveSerialNumber)
+
+	authorized.POST("/stock_level", handler.Idempotent, handler.CreateStockLevel)
+	authorized.GET("/stock_level/:id", handler.GetByIdStockLevel)
+	authorized.GET("/stock_level", handler.GetListStockLevel)
+	authorized.DELETE("/stock_level/:id", handler.DeleteStockLevel)
+
+	authorized.GET("/export/:entity", handler.Export)
+
+	authorized.GET("/report/inventory_valuation", handler.InventoryValuationReport)
+	authorized.GET("/report/goods_receipt", handler.GoodsReceiptReport)
+	authorized.GET("/report/reorder", handler.ReorderReport)
+	authorized.GET("/report/expiring", handler.ExpiringReport)
+
+	for _, drift := range CheckSpec(r) {
+		logger.Warn("openapi: " + drift)
+	}
+}
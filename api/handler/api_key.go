@@ -0,0 +1,221 @@
+package handler
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v4"
+
+	"market/api/models"
+	"market/pkg/audit"
+	"market/pkg/helper"
+	"market/pkg/logger"
+	"market/pkg/rbac"
+	"market/pkg/security"
+)
+
+// apiKeyPrefix marks the keys of this service, so a leaked one is easy to
+// recognize by secret scanners.
+const apiKeyPrefix = "mk_"
+
+func (h *Handler) CreateApiKey(c *gin.Context) {
+
+	var createApiKey models.CreateApiKey
+
+	err := c.ShouldBindJSON(&createApiKey)
+	if err != nil {
+		h.handleError(c, "create api key", helper.ValidationError(err))
+		return
+	}
+
+	for _, scope := range createApiKey.Scopes {
+		if !rbac.ValidScope(scope) {
+			h.handlerResponse(c, "create api key", http.StatusBadRequest, "invalid scope "+scope+", expected any of "+strings.Join(rbac.Scopes(), ", "))
+			return
+		}
+	}
+
+	if createApiKey.RateLimit < 0 {
+		h.handlerResponse(c, "create api key", http.StatusBadRequest, "rate_limit must not be negative")
+		return
+	}
+
+	key, err := newApiKey()
+	if err != nil {
+		h.handleError(c, "create api key", err)
+		return
+	}
+
+	createApiKey.Prefix = key[:len(apiKeyPrefix)+8]
+	createApiKey.KeyHash = security.HashToken(key)
+	createApiKey.CreatedBy = c.GetString(contextUserId)
+
+	id, err := h.strg.ApiKey().Create(c.Request.Context(), &createApiKey)
+	if err != nil {
+		h.handleError(c, "storage.api_key.create", err)
+		return
+	}
+
+	resp, err := h.strg.ApiKey().GetByID(c.Request.Context(), &models.ApiKeyPrimaryKey{Id: id})
+	if err != nil {
+		h.handleError(c, "storage.api_key.getById", err)
+		return
+	}
+
+	h.apiKeySecretResponse(c, "create api key", http.StatusCreated, &models.ApiKeySecret{ApiKey: resp, Key: key})
+}
+
+func (h *Handler) GetByIdApiKey(c *gin.Context) {
+
+	var id = c.Param("id")
+
+	resp, err := h.strg.ApiKey().GetByID(c.Request.Context(), &models.ApiKeyPrimaryKey{Id: id})
+	if err != nil {
+		h.handleError(c, "storage.api_key.getById", err)
+		return
+	}
+
+	h.handlerResponse(c, "get by id api key", http.StatusOK, resp)
+}
+
+func (h *Handler) GetListApiKey(c *gin.Context) {
+
+	offset, err := h.getOffsetQuery(c.Query("offset"))
+	if err != nil {
+		h.handlerResponse(c, "get list api key", http.StatusBadRequest, "invalid offset")
+		return
+	}
+
+	limit, err := h.getLimitQuery(c.Query("limit"))
+	if err != nil {
+		h.handlerResponse(c, "get list api key", http.StatusBadRequest, "invalid limit")
+		return
+	}
+
+	revoked, err := h.getBoolQuery(c.Query("revoked"))
+	if err != nil {
+		h.handlerResponse(c, "get list api key", http.StatusBadRequest, "invalid revoked")
+		return
+	}
+
+	resp, err := h.strg.ApiKey().GetList(c.Request.Context(), &models.ApiKeyGetListRequest{
+		Offset:  offset,
+		Limit:   limit,
+		Search:  c.Query("search"),
+		Revoked: revoked,
+	})
+	if err != nil {
+		h.handleError(c, "storage.api_key.getList", err)
+		return
+	}
+
+	h.handlerResponse(c, "get list api key", http.StatusOK, resp)
+}
+
+// RotateApiKey issues a new secret for the key, keeping its name, scopes
+// and limits. The old secret stops working at once.
+func (h *Handler) RotateApiKey(c *gin.Context) {
+
+	var id = c.Param("id")
+
+	key, err := newApiKey()
+	if err != nil {
+		h.handleError(c, "rotate api key", err)
+		return
+	}
+
+	_, err = h.strg.ApiKey().Rotate(c.Request.Context(), &models.RotateApiKey{
+		Id:      id,
+		Prefix:  key[:len(apiKeyPrefix)+8],
+		KeyHash: security.HashToken(key),
+	})
+	if err != nil {
+		h.handleError(c, "storage.api_key.rotate", err)
+		return
+	}
+
+	resp, err := h.strg.ApiKey().GetByID(c.Request.Context(), &models.ApiKeyPrimaryKey{Id: id})
+	if err != nil {
+		h.handleError(c, "storage.api_key.getById", err)
+		return
+	}
+
+	h.apiKeySecretResponse(c, "rotate api key", http.StatusOK, &models.ApiKeySecret{ApiKey: resp, Key: key})
+}
+
+// DeleteApiKey revokes the key.
+func (h *Handler) DeleteApiKey(c *gin.Context) {
+
+	var id = c.Param("id")
+
+	_, err := h.strg.ApiKey().Revoke(c.Request.Context(), &models.ApiKeyPrimaryKey{Id: id})
+	if err != nil {
+		h.handleError(c, "storage.api_key.revoke", err)
+		return
+	}
+
+	h.handlerResponse(c, "delete api key", http.StatusNoContent, nil)
+}
+
+func (h *Handler) authorizeApiKey(c *gin.Context, key string) {
+
+	apiKey, err := h.strg.ApiKey().GetByHash(c.Request.Context(), &models.ApiKeyHash{KeyHash: security.HashToken(key)})
+	if errors.Is(err, pgx.ErrNoRows) {
+		h.handlerResponse(c, "authorize", http.StatusUnauthorized, "invalid api key")
+		c.Abort()
+		return
+	} else if err != nil {
+		h.handleError(c, "storage.api_key.getByHash", err)
+		c.Abort()
+		return
+	}
+
+	limit := apiKey.RateLimit
+	if limit <= 0 {
+		limit = h.cfg.ApiKeyRateLimit
+	}
+
+	allowed, retryAfter := h.limiter.Allow(apiKey.Id, limit)
+	if !allowed {
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+		h.handlerResponse(c, "authorize", http.StatusTooManyRequests, "rate limit of "+strconv.Itoa(limit)+" requests per minute exceeded")
+		c.Abort()
+		return
+	}
+
+	err = h.strg.ApiKey().Touch(c.Request.Context(), &models.ApiKeyPrimaryKey{Id: apiKey.Id})
+	if err != nil {
+		h.log.Error("storage.api_key.touch", logger.Error(err))
+	}
+
+	c.Set(contextApiKey, apiKey)
+	c.Request = c.Request.WithContext(audit.WithActor(c.Request.Context(), audit.Actor{Type: audit.ActorApiKey, Id: apiKey.Id}))
+
+	c.Next()
+}
+
+// apiKeySecretResponse answers without logging, the body holds the secret.
+func (h *Handler) apiKeySecretResponse(c *gin.Context, path string, code int, data *models.ApiKeySecret) {
+
+	h.log.Info(path, logger.String("id", data.Id), logger.String("prefix", data.Prefix))
+
+	c.JSON(code, Response{
+		Status:      code,
+		Description: path,
+		Data:        data,
+	})
+}
+
+func newApiKey() (string, error) {
+
+	token, err := security.NewToken(32)
+	if err != nil {
+		return "", err
+	}
+
+	return apiKeyPrefix + token, nil
+}
@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"market/api/models"
+	"market/pkg/audit"
+)
+
+// RequestId tags the request with the X-Request-Id header of the client, or
+// a new one, and echoes it back so changes in the audit log can be traced
+// to the request that made them.
+func (h *Handler) RequestId(c *gin.Context) {
+
+	requestId := c.GetHeader("X-Request-Id")
+	if requestId == "" || len(requestId) > 64 {
+		requestId = uuid.New().String()
+	}
+
+	c.Header("X-Request-Id", requestId)
+	c.Request = c.Request.WithContext(audit.WithRequestId(c.Request.Context(), requestId))
+
+	c.Next()
+}
+
+func (h *Handler) GetListAuditLog(c *gin.Context) {
+
+	offset, err := h.getOffsetQuery(c.Query("offset"))
+	if err != nil {
+		h.handlerResponse(c, "get list audit log", http.StatusBadRequest, "invalid offset")
+		return
+	}
+
+	limit, err := h.getLimitQuery(c.Query("limit"))
+	if err != nil {
+		h.handlerResponse(c, "get list audit log", http.StatusBadRequest, "invalid limit")
+		return
+	}
+
+	from, err := h.getReportTimeQuery(c.Query("from"), false)
+	if err != nil {
+		h.handlerResponse(c, "get list audit log", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	to, err := h.getReportTimeQuery(c.Query("to"), true)
+	if err != nil {
+		h.handlerResponse(c, "get list audit log", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	resp, err := h.strg.AuditLog().GetList(c.Request.Context(), &models.AuditLogGetListRequest{
+		Offset:   offset,
+		Limit:    limit,
+		Entity:   c.Query("entity"),
+		EntityId: c.Query("entity_id"),
+		ActorId:  c.Query("actor_id"),
+		Action:   c.Query("action"),
+		From:     from,
+		To:       to,
+	})
+	if err != nil {
+		h.handleError(c, "storage.audit_log.getList", err)
+		return
+	}
+
+	h.handlerResponse(c, "get list audit log", http.StatusOK, resp)
+}
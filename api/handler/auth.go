@@ -0,0 +1,210 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+
+	"market/api/models"
+	"market/pkg/audit"
+	"market/pkg/helper"
+	"market/pkg/jwt"
+	"market/pkg/security"
+	"market/storage"
+)
+
+const (
+	contextUserId    = "user_id"
+	contextSessionId = "session_id"
+	contextUser      = "user"
+	contextApiKey    = "api_key"
+)
+
+func (h *Handler) Login(c *gin.Context) {
+
+	var login models.Login
+
+	err := c.ShouldBindJSON(&login)
+	if err != nil {
+		h.handleError(c, "login", helper.ValidationError(err))
+		return
+	}
+
+	user, err := h.strg.User().GetByLogin(c.Request.Context(), &models.UserLogin{Login: login.Login})
+	if errors.Is(err, pgx.ErrNoRows) {
+		h.handlerResponse(c, "login", http.StatusUnauthorized, "invalid login or password")
+		return
+	} else if err != nil {
+		h.handleError(c, "storage.user.getByLogin", err)
+		return
+	}
+
+	if !security.ComparePassword(user.PasswordHash, login.Password) || !user.Active {
+		h.handlerResponse(c, "login", http.StatusUnauthorized, "invalid login or password")
+		return
+	}
+
+	refreshToken, err := security.NewToken(32)
+	if err != nil {
+		h.handleError(c, "login", err)
+		return
+	}
+
+	familyId := uuid.New().String()
+
+	_, err = h.strg.Session().Create(c.Request.Context(), &models.CreateSession{
+		UserId:    user.Id,
+		FamilyId:  familyId,
+		TokenHash: security.HashToken(refreshToken),
+		TTL:       h.cfg.RefreshTokenTTL * 3600,
+	})
+	if err != nil {
+		h.handleError(c, "storage.session.create", err)
+		return
+	}
+
+	h.tokenResponse(c, "login", user.Id, familyId, refreshToken)
+}
+
+func (h *Handler) RefreshToken(c *gin.Context) {
+
+	var refresh models.RefreshToken
+
+	err := c.ShouldBindJSON(&refresh)
+	if err != nil {
+		h.handleError(c, "refresh token", helper.ValidationError(err))
+		return
+	}
+
+	refreshToken, err := security.NewToken(32)
+	if err != nil {
+		h.handleError(c, "refresh token", err)
+		return
+	}
+
+	session, err := h.strg.Session().Rotate(c.Request.Context(), &models.RotateSession{
+		TokenHash:    security.HashToken(refresh.RefreshToken),
+		NewTokenHash: security.HashToken(refreshToken),
+		TTL:          h.cfg.RefreshTokenTTL * 3600,
+	})
+	if errors.Is(err, storage.ErrInvalidToken) {
+		h.handlerResponse(c, "storage.session.rotate", http.StatusUnauthorized, err.Error())
+		return
+	} else if err != nil {
+		h.handleError(c, "storage.session.rotate", err)
+		return
+	}
+
+	h.tokenResponse(c, "refresh token", session.UserId, session.FamilyId, refreshToken)
+}
+
+// Logout revokes the session of the access token, every refresh token
+// rotated from the same login stops working with it.
+func (h *Handler) Logout(c *gin.Context) {
+
+	err := h.strg.Session().RevokeFamily(c.Request.Context(), c.GetString(contextSessionId))
+	if err != nil {
+		h.handleError(c, "storage.session.revokeFamily", err)
+		return
+	}
+
+	h.handlerResponse(c, "logout", http.StatusNoContent, nil)
+}
+
+func (h *Handler) Me(c *gin.Context) {
+
+	resp, err := h.strg.User().GetByID(c.Request.Context(), &models.UserPrimaryKey{Id: c.GetString(contextUserId)})
+	if err != nil {
+		h.handleError(c, "storage.user.getById", err)
+		return
+	}
+
+	h.handlerResponse(c, "me", http.StatusOK, resp)
+}
+
+// Authorize rejects requests without a valid access token of a session that
+// was not logged out, or a valid API key in the X-API-Key header.
+func (h *Handler) Authorize(c *gin.Context) {
+
+	if key := c.GetHeader("X-API-Key"); key != "" {
+		h.authorizeApiKey(c, key)
+		return
+	}
+
+	token := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+	if token == "" {
+		h.handlerResponse(c, "authorize", http.StatusUnauthorized, "missing bearer token")
+		c.Abort()
+		return
+	}
+
+	claims, err := jwt.Parse(token, []byte(h.cfg.JWTSecret))
+	if err != nil {
+		h.handlerResponse(c, "authorize", http.StatusUnauthorized, err.Error())
+		c.Abort()
+		return
+	}
+
+	active, err := h.strg.Session().Active(c.Request.Context(), claims.SessionId)
+	if err != nil {
+		h.handleError(c, "storage.session.active", err)
+		c.Abort()
+		return
+	}
+
+	if !active {
+		h.handlerResponse(c, "authorize", http.StatusUnauthorized, storage.ErrInvalidToken.Error())
+		c.Abort()
+		return
+	}
+
+	user, err := h.strg.User().GetByID(c.Request.Context(), &models.UserPrimaryKey{Id: claims.Subject})
+	if err != nil {
+		h.handlerResponse(c, "storage.user.getById", http.StatusUnauthorized, err.Error())
+		c.Abort()
+		return
+	}
+
+	c.Set(contextUserId, claims.Subject)
+	c.Set(contextSessionId, claims.SessionId)
+	c.Set(contextUser, user)
+	c.Request = c.Request.WithContext(audit.WithActor(c.Request.Context(), audit.Actor{Type: audit.ActorUser, Id: user.Id}))
+
+	c.Next()
+}
+
+func (h *Handler) tokenResponse(c *gin.Context, path, userId, familyId, refreshToken string) {
+
+	var (
+		now = time.Now()
+		ttl = time.Duration(h.cfg.AccessTokenTTL) * time.Minute
+	)
+
+	accessToken, err := jwt.Sign(&jwt.Claims{
+		Subject:   userId,
+		SessionId: familyId,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(ttl).Unix(),
+	}, []byte(h.cfg.JWTSecret))
+	if err != nil {
+		h.handleError(c, path, err)
+		return
+	}
+
+	// Tokens are not logged.
+	c.JSON(http.StatusOK, Response{
+		Status:      http.StatusOK,
+		Description: path,
+		Data: &models.Token{
+			AccessToken:  accessToken,
+			RefreshToken: refreshToken,
+			TokenType:    "Bearer",
+			ExpiresIn:    int(ttl.Seconds()),
+		},
+	})
+}
@@ -0,0 +1,174 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"market/api/models"
+	"market/pkg/helper"
+	"market/storage"
+)
+
+func (h *Handler) CreateBranch(c *gin.Context) {
+
+	var createBranch models.CreateBranch
+
+	err := c.ShouldBindJSON(&createBranch)
+	if err != nil {
+		h.handleError(c, "create branch", helper.ValidationError(err))
+		return
+	}
+
+	id, err := h.strg.Branch().Create(c.Request.Context(), &createBranch)
+	if err != nil {
+		h.handleError(c, "storage.branch.create", err)
+		return
+	}
+
+	resp, err := h.strg.Branch().GetByID(c.Request.Context(), &models.BranchPrimaryKey{Id: id})
+	if err != nil {
+		h.handleError(c, "storage.branch.getById", err)
+		return
+	}
+
+	h.handlerResponse(c, "create branch", http.StatusCreated, resp)
+}
+
+func (h *Handler) GetByIdBranch(c *gin.Context) {
+
+	var id = c.Param("id")
+
+	includeDeleted, ok := h.getIncludeDeletedQuery(c, "get by id branch")
+	if !ok {
+		return
+	}
+
+	resp, err := h.strg.Branch().GetByID(c.Request.Context(), &models.BranchPrimaryKey{Id: id, IncludeDeleted: includeDeleted})
+	if err != nil {
+		h.handleError(c, "storage.branch.getById", err)
+		return
+	}
+
+	if !h.inBranchScope(c, resp.Id) {
+		h.outOfBranchScope(c, "get by id branch")
+		return
+	}
+
+	h.setETag(c, resp.Version)
+
+	h.handlerResponse(c, "get by id branch", http.StatusOK, resp)
+}
+
+func (h *Handler) GetListBranch(c *gin.Context) {
+
+	offset, err := h.getOffsetQuery(c.Query("offset"))
+	if err != nil {
+		h.handlerResponse(c, "get list branch", http.StatusBadRequest, "invalid offset")
+		return
+	}
+
+	limit, err := h.getLimitQuery(c.Query("limit"))
+	if err != nil {
+		h.handlerResponse(c, "get list branch", http.StatusBadRequest, "invalid limit")
+		return
+	}
+
+	includeDeleted, ok := h.getIncludeDeletedQuery(c, "get list branch")
+	if !ok {
+		return
+	}
+
+	resp, err := h.strg.Branch().GetList(c.Request.Context(), &models.BranchGetListRequest{
+		Offset:         offset,
+		Limit:          limit,
+		Search:         c.Query("search"),
+		BranchIds:      h.branchScope(c),
+		IncludeDeleted: includeDeleted,
+	})
+	if err != nil {
+		h.handleError(c, "storage.branch.getList", err)
+		return
+	}
+
+	h.handlerResponse(c, "get list branch", http.StatusOK, resp)
+}
+
+func (h *Handler) UpdateBranch(c *gin.Context) {
+
+	var updateBranch models.UpdateBranch
+
+	err := c.ShouldBindJSON(&updateBranch)
+	if err != nil {
+		h.handleError(c, "update branch", helper.ValidationError(err))
+		return
+	}
+
+	updateBranch.Id = c.Param("id")
+
+	version, ok := h.getIfMatchVersion(c, "update branch", updateBranch.Version)
+	if !ok {
+		return
+	}
+
+	updateBranch.Version = version
+
+	_, err = h.strg.Branch().Update(c.Request.Context(), &updateBranch)
+	if errors.Is(err, storage.ErrVersionConflict) {
+		current, err := h.strg.Branch().GetByID(c.Request.Context(), &models.BranchPrimaryKey{Id: updateBranch.Id})
+		if err != nil {
+			h.handleError(c, "storage.branch.getById", err)
+			return
+		}
+
+		h.setETag(c, current.Version)
+		h.handlerResponse(c, "storage.branch.update", http.StatusConflict, current)
+		return
+	} else if err != nil {
+		h.handleError(c, "storage.branch.update", err)
+		return
+	}
+
+	resp, err := h.strg.Branch().GetByID(c.Request.Context(), &models.BranchPrimaryKey{Id: updateBranch.Id})
+	if err != nil {
+		h.handleError(c, "storage.branch.getById", err)
+		return
+	}
+
+	h.setETag(c, resp.Version)
+
+	h.handlerResponse(c, "update branch", http.StatusAccepted, resp)
+}
+
+func (h *Handler) DeleteBranch(c *gin.Context) {
+
+	var id = c.Param("id")
+
+	err := h.strg.Branch().Delete(c.Request.Context(), &models.BranchPrimaryKey{Id: id})
+	if err != nil {
+		h.handleError(c, "storage.branch.delete", err)
+		return
+	}
+
+	h.handlerResponse(c, "delete branch", http.StatusNoContent, nil)
+}
+
+func (h *Handler) RestoreBranch(c *gin.Context) {
+
+	var id = c.Param("id")
+
+	_, err := h.strg.Branch().Restore(c.Request.Context(), &models.BranchPrimaryKey{Id: id})
+	if err != nil {
+		h.handleError(c, "storage.branch.restore", err)
+		return
+	}
+
+	resp, err := h.strg.Branch().GetByID(c.Request.Context(), &models.BranchPrimaryKey{Id: id})
+	if err != nil {
+		h.handleError(c, "storage.branch.getById", err)
+		return
+	}
+
+	h.handlerResponse(c, "restore branch", http.StatusOK, resp)
+}
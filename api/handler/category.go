@@ -0,0 +1,168 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"market/api/models"
+	"market/pkg/helper"
+	"market/storage"
+)
+
+func (h *Handler) CreateCategory(c *gin.Context) {
+
+	var createCategory models.CreateCategory
+
+	err := c.ShouldBindJSON(&createCategory)
+	if err != nil {
+		h.handleError(c, "create category", helper.ValidationError(err))
+		return
+	}
+
+	id, err := h.strg.Category().Create(c.Request.Context(), &createCategory)
+	if err != nil {
+		h.handleError(c, "storage.category.create", err)
+		return
+	}
+
+	resp, err := h.strg.Category().GetByID(c.Request.Context(), &models.CategoryPrimaryKey{Id: id})
+	if err != nil {
+		h.handleError(c, "storage.category.getById", err)
+		return
+	}
+
+	h.handlerResponse(c, "create category", http.StatusCreated, resp)
+}
+
+func (h *Handler) GetByIdCategory(c *gin.Context) {
+
+	var id = c.Param("id")
+
+	includeDeleted, ok := h.getIncludeDeletedQuery(c, "get by id category")
+	if !ok {
+		return
+	}
+
+	resp, err := h.strg.Category().GetByID(c.Request.Context(), &models.CategoryPrimaryKey{Id: id, IncludeDeleted: includeDeleted})
+	if err != nil {
+		h.handleError(c, "storage.category.getById", err)
+		return
+	}
+
+	h.setETag(c, resp.Version)
+
+	h.handlerResponse(c, "get by id category", http.StatusOK, resp)
+}
+
+func (h *Handler) GetListCategory(c *gin.Context) {
+
+	offset, err := h.getOffsetQuery(c.Query("offset"))
+	if err != nil {
+		h.handlerResponse(c, "get list category", http.StatusBadRequest, "invalid offset")
+		return
+	}
+
+	limit, err := h.getLimitQuery(c.Query("limit"))
+	if err != nil {
+		h.handlerResponse(c, "get list category", http.StatusBadRequest, "invalid limit")
+		return
+	}
+
+	includeDeleted, ok := h.getIncludeDeletedQuery(c, "get list category")
+	if !ok {
+		return
+	}
+
+	resp, err := h.strg.Category().GetList(c.Request.Context(), &models.CategoryGetListRequest{
+		Offset:         offset,
+		Limit:          limit,
+		Search:         c.Query("search"),
+		IncludeDeleted: includeDeleted,
+	})
+	if err != nil {
+		h.handleError(c, "storage.category.getList", err)
+		return
+	}
+
+	h.handlerResponse(c, "get list category", http.StatusOK, resp)
+}
+
+func (h *Handler) UpdateCategory(c *gin.Context) {
+
+	var updateCategory models.UpdateCategory
+
+	err := c.ShouldBindJSON(&updateCategory)
+	if err != nil {
+		h.handleError(c, "update category", helper.ValidationError(err))
+		return
+	}
+
+	updateCategory.Id = c.Param("id")
+
+	version, ok := h.getIfMatchVersion(c, "update category", updateCategory.Version)
+	if !ok {
+		return
+	}
+
+	updateCategory.Version = version
+
+	_, err = h.strg.Category().Update(c.Request.Context(), &updateCategory)
+	if errors.Is(err, storage.ErrVersionConflict) {
+		current, err := h.strg.Category().GetByID(c.Request.Context(), &models.CategoryPrimaryKey{Id: updateCategory.Id})
+		if err != nil {
+			h.handleError(c, "storage.category.getById", err)
+			return
+		}
+
+		h.setETag(c, current.Version)
+		h.handlerResponse(c, "storage.category.update", http.StatusConflict, current)
+		return
+	} else if err != nil {
+		h.handleError(c, "storage.category.update", err)
+		return
+	}
+
+	resp, err := h.strg.Category().GetByID(c.Request.Context(), &models.CategoryPrimaryKey{Id: updateCategory.Id})
+	if err != nil {
+		h.handleError(c, "storage.category.getById", err)
+		return
+	}
+
+	h.setETag(c, resp.Version)
+
+	h.handlerResponse(c, "update category", http.StatusAccepted, resp)
+}
+
+func (h *Handler) DeleteCategory(c *gin.Context) {
+
+	var id = c.Param("id")
+
+	err := h.strg.Category().Delete(c.Request.Context(), &models.CategoryPrimaryKey{Id: id})
+	if err != nil {
+		h.handleError(c, "storage.category.delete", err)
+		return
+	}
+
+	h.handlerResponse(c, "delete category", http.StatusNoContent, nil)
+}
+
+func (h *Handler) RestoreCategory(c *gin.Context) {
+
+	var id = c.Param("id")
+
+	_, err := h.strg.Category().Restore(c.Request.Context(), &models.CategoryPrimaryKey{Id: id})
+	if err != nil {
+		h.handleError(c, "storage.category.restore", err)
+		return
+	}
+
+	resp, err := h.strg.Category().GetByID(c.Request.Context(), &models.CategoryPrimaryKey{Id: id})
+	if err != nil {
+		h.handleError(c, "storage.category.getById", err)
+		return
+	}
+
+	h.handlerResponse(c, "restore category", http.StatusOK, resp)
+}
@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"market/export"
+	"market/pkg/logger"
+)
+
+// Export streams the whole filtered list of an entity as a file download.
+// Every query parameter except format is passed on as a list filter.
+func (h *Handler) Export(c *gin.Context) {
+
+	var (
+		entity = c.Param("entity")
+		format = c.DefaultQuery("format", export.FormatCSV)
+	)
+
+	err := export.Check(entity, format)
+	if err != nil {
+		h.handlerResponse(c, "export", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	filters := export.Filters{}
+	for key, values := range c.Request.URL.Query() {
+		if key != "format" && len(values) > 0 {
+			filters[key] = values[0]
+		}
+	}
+
+	filename := fmt.Sprintf("%s_%s.%s", entity, time.Now().Format("20060102_150405"), format)
+
+	c.Header("Content-Type", export.ContentType(format))
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	c.Status(http.StatusOK)
+
+	err = export.Run(c.Request.Context(), h.strg, entity, format, filters, h.branchScope(c), c.Writer)
+	if err != nil {
+		// The status line is already sent, the client gets a truncated file.
+		h.log.Error("export", logger.String("entity", entity), logger.Error(err))
+		return
+	}
+
+	h.log.Info("export", logger.String("entity", entity), logger.String("format", format))
+}
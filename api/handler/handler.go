@@ -0,0 +1,156 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"market/config"
+	"market/pkg/apperr"
+	"market/pkg/audit"
+	"market/pkg/blob"
+	"market/pkg/logger"
+	"market/pkg/ratelimit"
+	"market/storage"
+)
+
+type Handler struct {
+	cfg  *config.Config
+	log  logger.LoggerI
+	strg storage.StorageI
+	blob blob.StorageI
+
+	limiter *ratelimit.Limiter
+}
+
+type Response struct {
+	Status      int            `json:"status"`
+	Description string         `json:"description"`
+	Data        interface{}    `json:"data"`
+	Error       *ErrorResponse `json:"error,omitempty"`
+}
+
+// ErrorResponse is set on every response with an error status. Code is
+// meant for clients to act on, Message for people.
+type ErrorResponse struct {
+	Code    string              `json:"code"`
+	Message string              `json:"message"`
+	Fields  []apperr.FieldError `json:"fields,omitempty"`
+}
+
+func NewHandler(cfg *config.Config, strg storage.StorageI, blob blob.StorageI, limiter *ratelimit.Limiter, logger logger.LoggerI) *Handler {
+	return &Handler{
+		cfg:  cfg,
+		log:  logger,
+		strg: strg,
+		blob: blob,
+
+		limiter: limiter,
+	}
+}
+
+func (h *Handler) handlerResponse(c *gin.Context, path string, code int, message interface{}) {
+	response := Response{
+		Status:      code,
+		Description: path,
+		Data:        message,
+	}
+
+	if code >= 400 {
+		response.Error = &ErrorResponse{
+			Code:    strings.ReplaceAll(strings.ToLower(http.StatusText(code)), " ", "_"),
+			Message: http.StatusText(code),
+		}
+
+		switch message := message.(type) {
+		case string:
+			response.Error.Message = message
+		case error:
+			response.Data = message.Error()
+			response.Error.Message = message.Error()
+
+			if appErr, ok := apperr.As(message); ok {
+				response.Error.Code = appErr.Code
+				response.Error.Fields = appErr.Fields
+
+				// Wrapping adds detail to the message, the fields are
+				// listed apart.
+				if message == error(appErr) {
+					response.Error.Message = appErr.Message
+				}
+			}
+		}
+	}
+
+	switch {
+	case code < 300:
+		h.log.Info(path, logger.Any("info", response))
+	case code >= 400:
+		h.log.Error(path, logger.Any("error", response))
+	}
+
+	c.JSON(code, response)
+}
+
+// handleError responds with the status that fits a typed error, and 500 for
+// any other. The message of an untyped error may carry SQL or driver details,
+// so it is only logged, with the request id the client gets back.
+func (h *Handler) handleError(c *gin.Context, path string, err error) {
+
+	appErr, ok := apperr.As(err)
+	if !ok {
+		h.log.Error(path, logger.Error(err), logger.String("request_id", audit.RequestId(c.Request.Context())))
+		h.handlerResponse(c, path, http.StatusInternalServerError, "internal error")
+		return
+	}
+
+	code := http.StatusInternalServerError
+
+	switch appErr.Kind {
+	case apperr.KindNotFound:
+		code = http.StatusNotFound
+	case apperr.KindConflict, apperr.KindInvalidStateTransition:
+		code = http.StatusConflict
+	case apperr.KindValidation:
+		code = http.StatusBadRequest
+	case apperr.KindForbidden:
+		code = http.StatusForbidden
+	}
+
+	h.handlerResponse(c, path, code, err)
+}
+
+func (h *Handler) getOffsetQuery(offset string) (int, error) {
+
+	if len(offset) <= 0 {
+		return h.cfg.DefaultOffset, nil
+	}
+
+	return strconv.Atoi(offset)
+}
+
+func (h *Handler) getLimitQuery(limit string) (int, error) {
+
+	if len(limit) <= 0 {
+		return h.cfg.DefaultLimit, nil
+	}
+
+	return strconv.Atoi(limit)
+}
+
+func (h *Handler) getBoolQuery(value string) (bool, error) {
+
+	if len(value) <= 0 {
+		return false, nil
+	}
+
+	ok, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, errors.New("invalid boolean query param: " + value)
+	}
+
+	return ok, nil
+}
@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v4"
+
+	"market/config"
+	"market/pkg/apperr"
+	"market/pkg/logger"
+	"market/storage"
+)
+
+func TestHandleError(t *testing.T) {
+
+	gin.SetMode(gin.TestMode)
+
+	h := NewHandler(&config.Config{}, nil, nil, nil, logger.NewLogger("test", logger.LevelError))
+
+	field := apperr.FieldError{Field: "name", Message: "is required"}
+
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantCode   string
+		wantFields []apperr.FieldError
+		wantHidden string
+	}{
+		{name: "not found", err: apperr.NotFound("product not found"), wantStatus: http.StatusNotFound, wantCode: apperr.CodeNotFound},
+		{name: "wrapped not found", err: apperr.Wrap(apperr.NotFound("not found"), pgx.ErrNoRows), wantStatus: http.StatusNotFound, wantCode: apperr.CodeNotFound},
+		{name: "not found in a chain", err: fmt.Errorf("get product: %w", apperr.NotFound("not found")), wantStatus: http.StatusNotFound, wantCode: apperr.CodeNotFound},
+		{name: "conflict", err: apperr.Conflict("barcode is already in use"), wantStatus: http.StatusConflict, wantCode: apperr.CodeConflict},
+		{name: "version conflict", err: storage.ErrVersionConflict, wantStatus: http.StatusConflict, wantCode: "version_conflict"},
+		{name: "invalid state transition", err: apperr.InvalidStateTransition("draft", "finished"), wantStatus: http.StatusConflict, wantCode: apperr.CodeInvalidStateTransition},
+		{name: "validation", err: apperr.Validation("invalid request", field), wantStatus: http.StatusBadRequest, wantCode: apperr.CodeValidation, wantFields: []apperr.FieldError{field}},
+		{name: "forbidden", err: apperr.Forbidden("out of branch scope"), wantStatus: http.StatusForbidden, wantCode: apperr.CodeForbidden},
+		{name: "untyped", err: errors.New(`relation "product" does not exist`), wantStatus: http.StatusInternalServerError, wantCode: "internal_server_error", wantHidden: "relation"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+
+			h.handleError(c, "test", tt.err)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+
+			var resp struct {
+				Status int            `json:"status"`
+				Error  *ErrorResponse `json:"error"`
+			}
+
+			err := json.Unmarshal(w.Body.Bytes(), &resp)
+			if err != nil {
+				t.Fatalf("response %s: %v", w.Body.String(), err)
+			}
+
+			if resp.Status != tt.wantStatus {
+				t.Errorf("body status = %d, want %d", resp.Status, tt.wantStatus)
+			}
+
+			if resp.Error == nil {
+				t.Fatalf("response %s has no error", w.Body.String())
+			}
+
+			if resp.Error.Code != tt.wantCode {
+				t.Errorf("error code = %q, want %q", resp.Error.Code, tt.wantCode)
+			}
+
+			if tt.wantHidden != "" && strings.Contains(w.Body.String(), tt.wantHidden) {
+				t.Errorf("response %s leaks %q", w.Body.String(), tt.wantHidden)
+			}
+
+			if !reflect.DeepEqual(resp.Error.Fields, tt.wantFields) {
+				t.Errorf("error fields = %+v, want %+v", resp.Error.Fields, tt.wantFields)
+			}
+		})
+	}
+}
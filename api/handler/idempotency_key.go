@@ -0,0 +1,148 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"market/api/models"
+	"market/pkg/audit"
+	"market/pkg/logger"
+)
+
+// Idempotent makes a create or finish request safe to retry. The first
+// request with an Idempotency-Key header runs and its response is kept for
+// cfg.IdempotencyKeyTTL hours; retries with the same key and body get that
+// response again instead of running twice, and reusing the key for another
+// request is rejected. Keys are per caller.
+func (h *Handler) Idempotent(c *gin.Context) {
+
+	var key = c.GetHeader("Idempotency-Key")
+
+	if key == "" {
+		c.Next()
+		return
+	}
+
+	if len(key) > 255 {
+		h.handlerResponse(c, "idempotency key", http.StatusBadRequest, "invalid Idempotency-Key header, at most 255 characters")
+		c.Abort()
+		return
+	}
+
+	// Uploads are the largest bodies these routes take.
+	maxSize := h.cfg.ImportMaxSize
+	if h.cfg.ImageMaxSize > maxSize {
+		maxSize = h.cfg.ImageMaxSize
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxSize+1<<20))
+	if err != nil {
+		h.handlerResponse(c, "idempotency key", http.StatusRequestEntityTooLarge, err.Error())
+		c.Abort()
+		return
+	}
+
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+	hash := sha256.New()
+	hash.Write([]byte(c.Request.Method + " " + c.Request.URL.RequestURI() + "\n"))
+	hash.Write(body)
+
+	var (
+		requestHash = hex.EncodeToString(hash.Sum(nil))
+		actor       = audit.ActorFrom(c.Request.Context())
+		id          = models.IdempotencyKeyPrimaryKey{Key: key, ActorId: actor.Type + ":" + actor.Id}
+	)
+
+	stored, reserved, err := h.strg.IdempotencyKey().Reserve(c.Request.Context(), &models.CreateIdempotencyKey{
+		Key:         id.Key,
+		ActorId:     id.ActorId,
+		RequestHash: requestHash,
+		TTL:         h.cfg.IdempotencyKeyTTL,
+	})
+	if err != nil {
+		h.handleError(c, "storage.idempotency_key.reserve", err)
+		c.Abort()
+		return
+	}
+
+	if !reserved {
+		switch {
+		case stored.RequestHash != requestHash:
+			h.handlerResponse(c, "idempotency key", http.StatusUnprocessableEntity, "Idempotency-Key was already used for a different request")
+		case stored.ResponseCode == 0:
+			h.handlerResponse(c, "idempotency key", http.StatusConflict, "a request with this Idempotency-Key is still in progress")
+		default:
+			c.Header("Idempotent-Replayed", "true")
+			c.Data(stored.ResponseCode, stored.ResponseType, stored.ResponseBody)
+		}
+
+		c.Abort()
+		return
+	}
+
+	var (
+		saved bool
+
+		// The client may be gone by now, which is when the key matters most.
+		ctx = context.Background()
+	)
+
+	// The key would stay reserved until it expires when the handler panics,
+	// stops without a response or fails, or the response cannot be stored.
+	defer func() {
+		if saved {
+			return
+		}
+
+		err := h.strg.IdempotencyKey().Release(ctx, &id)
+		if err != nil {
+			h.log.Error("storage.idempotency_key.release", logger.Error(err))
+		}
+	}()
+
+	recorder := &responseRecorder{ResponseWriter: c.Writer}
+	c.Writer = recorder
+
+	c.Next()
+
+	if !c.Writer.Written() || c.Writer.Status() >= http.StatusInternalServerError {
+		return
+	}
+
+	err = h.strg.IdempotencyKey().Save(ctx, &models.SaveIdempotencyKey{
+		Key:          id.Key,
+		ActorId:      id.ActorId,
+		ResponseCode: c.Writer.Status(),
+		ResponseType: c.Writer.Header().Get("Content-Type"),
+		ResponseBody: recorder.body.Bytes(),
+	})
+	if err != nil {
+		h.log.Error("storage.idempotency_key.save", logger.Error(err))
+		return
+	}
+
+	saved = true
+}
+
+// responseRecorder keeps a copy of the response body as it is written.
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *responseRecorder) WriteString(data string) (int, error) {
+	w.body.WriteString(data)
+	return w.ResponseWriter.WriteString(data)
+}
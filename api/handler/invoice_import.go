@@ -0,0 +1,239 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v4"
+
+	"market/api/models"
+	"market/pkg/invoice"
+)
+
+var invoiceImportColumns = map[string][]string{
+	"barcode":  productImportColumns["barcode"],
+	"name":     productImportColumns["name"],
+	"price":    productImportColumns["price"],
+	"quantity": {"quantity", "qty", "soni", "miqdori", "количество"},
+}
+
+func (h *Handler) ImportInvoice(c *gin.Context) {
+
+	var (
+		branchId = c.PostForm("branch_id")
+		comingId = c.PostForm("coming_id")
+	)
+
+	if branchId == "" {
+		h.handlerResponse(c, "import invoice", http.StatusBadRequest, "branch_id is required")
+		return
+	}
+
+	if !h.inBranchScope(c, branchId) {
+		h.outOfBranchScope(c, "import invoice")
+		return
+	}
+
+	name, data, err := h.readUpload(c)
+	if err != nil {
+		h.handlerResponse(c, "import invoice", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	inv, err := readInvoice(name, data, c.PostForm("mapping"))
+	if err != nil {
+		h.handlerResponse(c, "import invoice", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if comingId == "" {
+		comingId = inv.Number
+	}
+
+	if comingId == "" {
+		h.handlerResponse(c, "import invoice", http.StatusBadRequest, "coming_id is required when the invoice has no number")
+		return
+	}
+
+	if len(inv.Lines) <= 0 {
+		h.handlerResponse(c, "import invoice", http.StatusBadRequest, "invoice has no lines")
+		return
+	}
+
+	var (
+		resp     = &models.InvoiceImportResponse{}
+		create   = &models.CreateStorageComingWithProducts{StorageComing: &models.CreateStorageComing{ComingId: comingId, BranchId: branchId}}
+		lineErrs []*models.ProductImportRowError
+	)
+
+	for _, line := range inv.Lines {
+		if line.Quantity <= 0 || line.Quantity != math.Trunc(line.Quantity) || line.Quantity > math.MaxInt32 {
+			lineErrs = append(lineErrs, &models.ProductImportRowError{Row: line.Row, Field: "quantity", Message: "quantity must be a positive whole number"})
+			continue
+		}
+
+		if line.Price < 0 || line.Price > math.MaxInt32 {
+			lineErrs = append(lineErrs, &models.ProductImportRowError{Row: line.Row, Field: "price", Message: "price must not be negative"})
+			continue
+		}
+
+		importLine := &models.InvoiceImportLine{
+			Row:      line.Row,
+			Barcode:  line.Barcode,
+			Name:     line.Name,
+			Quantity: int32(line.Quantity),
+			Price:    int32(math.Round(line.Price)),
+		}
+
+		var categoryId string
+
+		if line.Barcode != "" {
+			found, err := h.strg.Product().GetByBarcode(c.Request.Context(), &models.ProductBarcodeLookup{Barcode: line.Barcode})
+			if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+				h.handleError(c, "storage.product.getByBarcode", err)
+				return
+			}
+
+			if err == nil {
+				// A pack barcode stands for several units; receive single units.
+				if found.Quantity > 1 {
+					importLine.Quantity *= found.Quantity
+					importLine.Price = int32(math.Round(line.Price / float64(found.Quantity)))
+				}
+
+				importLine.Known = true
+				importLine.ProductId = found.Product.Id
+				importLine.CurrentPrice = found.Product.Price
+				importLine.PriceDifference = importLine.Price - found.Product.Price
+				categoryId = found.Product.CategoryId
+
+				if importLine.Name == "" {
+					importLine.Name = found.Product.Name
+				}
+			}
+		}
+
+		if !importLine.Known {
+			resp.Unknown++
+		} else if importLine.PriceDifference != 0 {
+			resp.PriceMismatches++
+		}
+
+		if importLine.Name == "" {
+			lineErrs = append(lineErrs, &models.ProductImportRowError{Row: line.Row, Field: "name", Message: "name is required for unknown items"})
+			continue
+		}
+
+		if errs := importRowErrors(line.Row, importLine); len(errs) > 0 {
+			lineErrs = append(lineErrs, errs...)
+			continue
+		}
+
+		resp.Lines = append(resp.Lines, importLine)
+
+		create.Products = append(create.Products, &models.CreateStorageComingProduct{
+			Name:       importLine.Name,
+			Quantity:   importLine.Quantity,
+			Price:      importLine.Price,
+			CategoryId: categoryId,
+			ProductId:  importLine.ProductId,
+			Barcode:    importLine.Barcode,
+		})
+	}
+
+	if len(lineErrs) > 0 {
+		h.handlerResponse(c, "import invoice", http.StatusBadRequest, lineErrs)
+		return
+	}
+
+	id, err := h.strg.StorageComing().CreateWithProducts(c.Request.Context(), create)
+	if err != nil {
+		h.handleError(c, "storage.storage_coming.createWithProducts", err)
+		return
+	}
+
+	resp.StorageComing, err = h.strg.StorageComing().GetByID(c.Request.Context(), &models.StorageComingPrimaryKey{Id: id})
+	if err != nil {
+		h.handleError(c, "storage.storage_coming.getById", err)
+		return
+	}
+
+	h.handlerResponse(c, "import invoice", http.StatusCreated, resp)
+}
+
+// readInvoice parses a UBL XML invoice or a CSV/XLSX table of lines.
+func readInvoice(name string, data []byte, mappingValue string) (*invoice.Invoice, error) {
+
+	if strings.ToLower(filepath.Ext(name)) == ".xml" {
+		return invoice.ParseUBL(bytes.NewReader(data))
+	}
+
+	rows, err := readTable(name, data)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rows) <= 1 {
+		return nil, errors.New("file has no data rows")
+	}
+
+	var mapping map[string]string
+
+	if mappingValue != "" {
+		err = json.Unmarshal([]byte(mappingValue), &mapping)
+		if err != nil {
+			return nil, errors.New("invalid mapping: " + err.Error())
+		}
+	}
+
+	columns, err := mapColumns(rows[0], mapping, invoiceImportColumns)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, field := range []string{"quantity", "price"} {
+		if _, ok := columns[field]; !ok {
+			return nil, errors.New("no column found for " + field)
+		}
+	}
+
+	var resp = &invoice.Invoice{}
+
+	for i, cells := range rows[1:] {
+		if isEmptyRow(cells) {
+			continue
+		}
+
+		line := &invoice.Line{
+			Row:     i + 2,
+			Barcode: cell(cells, columns, "barcode"),
+			Name:    cell(cells, columns, "name"),
+		}
+
+		line.Quantity, err = parseNumber(cell(cells, columns, "quantity"))
+		if err != nil {
+			return nil, fmt.Errorf("row %d: invalid quantity", line.Row)
+		}
+
+		line.Price, err = parseNumber(cell(cells, columns, "price"))
+		if err != nil {
+			return nil, fmt.Errorf("row %d: invalid price", line.Row)
+		}
+
+		resp.Lines = append(resp.Lines, line)
+	}
+
+	return resp, nil
+}
+
+func parseNumber(value string) (float64, error) {
+	return strconv.ParseFloat(strings.NewReplacer(" ", "", " ", "", ",", ".").Replace(value), 64)
+}
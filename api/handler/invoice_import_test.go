@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"market/pkg/invoice"
+)
+
+func TestReadInvoice(t *testing.T) {
+
+	data, err := os.ReadFile(filepath.Join("testdata", "invoice.csv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := readInvoice("invoice.csv", data, "")
+	if err != nil {
+		t.Fatalf("readInvoice() error = %v", err)
+	}
+
+	// The empty third row is skipped, rows keep their number in the file.
+	want := []*invoice.Line{
+		{Row: 2, Barcode: "4780001000017", Name: "Sut 1L", Quantity: 24, Price: 12500},
+		{Row: 4, Barcode: "4780001000024", Name: "Pishloq", Quantity: 2.5, Price: 90000},
+	}
+
+	if len(got.Lines) != len(want) {
+		t.Fatalf("got %d lines, want %d", len(got.Lines), len(want))
+	}
+
+	for i := range want {
+		if !reflect.DeepEqual(got.Lines[i], want[i]) {
+			t.Errorf("line %d = %+v, want %+v", i, *got.Lines[i], *want[i])
+		}
+	}
+}
+
+func TestReadInvoiceErrors(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		file    string
+		data    string
+		mapping string
+		wantErr string
+	}{
+		{name: "header only", file: "invoice.csv", data: "barcode,quantity,price\n", wantErr: "no data rows"},
+		{name: "no quantity column", file: "invoice.csv", data: "barcode,price\n478,100\n", wantErr: "no column found for quantity"},
+		{name: "mapping to a missing column", file: "invoice.csv", data: "code,qty,price\n478,1,100\n", mapping: `{"barcode":"ean13"}`, wantErr: "column ean13 not found"},
+		{name: "mapping not json", file: "invoice.csv", data: "barcode,qty,price\n478,1,100\n", mapping: "barcode=code", wantErr: "invalid mapping"},
+		{name: "invalid quantity", file: "invoice.csv", data: "barcode,qty,price\n478,many,100\n", wantErr: "row 2: invalid quantity"},
+		{name: "invalid price", file: "invoice.csv", data: "barcode,qty,price\n478,1,\n", wantErr: "row 2: invalid price"},
+		{name: "unsupported type", file: "invoice.pdf", data: "%PDF-1.4", wantErr: "unsupported file type"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			_, err := readInvoice(tt.file, []byte(tt.data), tt.mapping)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("readInvoice() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestReadInvoiceMapping(t *testing.T) {
+
+	got, err := readInvoice("invoice.csv", []byte("Kod,Soni,Narx\n478,3,1500\n"), `{"barcode":"kod"}`)
+	if err != nil {
+		t.Fatalf("readInvoice() error = %v", err)
+	}
+
+	want := &invoice.Line{Row: 2, Barcode: "478", Quantity: 3, Price: 1500}
+	if len(got.Lines) != 1 || !reflect.DeepEqual(got.Lines[0], want) {
+		t.Errorf("readInvoice() lines = %+v, want %+v", got.Lines, want)
+	}
+}
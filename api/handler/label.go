@@ -0,0 +1,213 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"market/api/models"
+	"market/pkg/apperr"
+	"market/pkg/helper"
+	"market/pkg/label"
+)
+
+// labelPageSize is how many rows are fetched per request when collecting
+// the products of a category or receipt.
+const labelPageSize = 100
+
+func (h *Handler) PrintLabels(c *gin.Context) {
+
+	var labelRequest models.LabelRequest
+
+	err := c.ShouldBindJSON(&labelRequest)
+	if err != nil {
+		h.handleError(c, "print labels", helper.ValidationError(err))
+		return
+	}
+
+	if labelRequest.Format == "" {
+		labelRequest.Format = label.FormatPDF
+	}
+
+	if labelRequest.StorageComingId != "" {
+		storageComing, err := h.strg.StorageComing().GetByID(c.Request.Context(), &models.StorageComingPrimaryKey{Id: labelRequest.StorageComingId})
+		if err != nil {
+			h.handleError(c, "storage.storage_coming.getById", err)
+			return
+		}
+
+		if !h.inBranchScope(c, storageComing.BranchId) {
+			h.outOfBranchScope(c, "print labels")
+			return
+		}
+
+		if storageComing.Status != models.StorageComingStatusFinished {
+			h.handlerResponse(c, "print labels", http.StatusBadRequest, "storage coming is not finished")
+			return
+		}
+	}
+
+	labels, err := h.collectLabels(c.Request.Context(), &labelRequest)
+	if err != nil {
+		h.handleError(c, "print labels", err)
+		return
+	}
+
+	if len(labels) <= 0 {
+		h.handlerResponse(c, "print labels", http.StatusBadRequest, "no products selected")
+		return
+	}
+
+	var buf bytes.Buffer
+
+	if labelRequest.Format == label.FormatZPL {
+		err = label.WriteZPL(&buf, labels)
+	} else {
+		err = label.WritePDF(&buf, labels)
+	}
+
+	if err != nil {
+		h.handlerResponse(c, "print labels", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.Header("Content-Disposition", "attachment; filename=labels."+labelRequest.Format)
+	c.Data(http.StatusOK, label.ContentType(labelRequest.Format), buf.Bytes())
+}
+
+// collectLabels resolves the selected products, categories and receipt lines
+// into labels. Receipt lines get one label per received unit unless an
+// explicit copy count is given. It stops once more than label.MaxLabels
+// would be printed.
+func (h *Handler) collectLabels(ctx context.Context, req *models.LabelRequest) ([]label.Label, error) {
+
+	var (
+		labels []label.Label
+		count  int
+	)
+
+	add := func(l label.Label) error {
+		count += label.Count(l)
+		if count > label.MaxLabels {
+			return apperr.Validation("too many labels, at most " + strconv.Itoa(label.MaxLabels) + " may be printed at once")
+		}
+
+		labels = append(labels, l)
+		return nil
+	}
+
+	for _, id := range req.ProductIds {
+		product, err := h.strg.Product().GetByID(ctx, &models.ProductPrimaryKey{Id: id})
+		if err != nil {
+			return nil, err
+		}
+
+		err = add(productLabel(product, req.Copies))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if req.CategoryId != "" {
+		for offset := 0; ; offset += labelPageSize {
+			products, err := h.strg.Product().GetList(ctx, &models.ProductGetListRequest{
+				Offset:     offset,
+				Limit:      labelPageSize,
+				CategoryId: req.CategoryId,
+			})
+			if err != nil {
+				return nil, err
+			}
+
+			for _, product := range products.Products {
+				err = add(productLabel(product, req.Copies))
+				if err != nil {
+					return nil, err
+				}
+			}
+
+			if offset+labelPageSize >= products.Count {
+				break
+			}
+		}
+	}
+
+	if req.StorageComingId != "" {
+		for offset := 0; ; offset += labelPageSize {
+			lines, err := h.strg.StorageComingProduct().GetList(ctx, &models.StorageComingProductGetListRequest{
+				Offset:          offset,
+				Limit:           labelPageSize,
+				StorageComingId: req.StorageComingId,
+			})
+			if err != nil {
+				return nil, err
+			}
+
+			for _, line := range lines.StorageComingProducts {
+				if line.ProductId == "" {
+					continue
+				}
+
+				product, err := h.strg.Product().GetByID(ctx, &models.ProductPrimaryKey{Id: line.ProductId})
+				if err != nil {
+					return nil, err
+				}
+
+				copies := req.Copies
+				if copies <= 0 {
+					copies = int(line.Quantity)
+				}
+
+				err = add(productLabel(product, copies))
+				if err != nil {
+					return nil, err
+				}
+			}
+
+			if offset+labelPageSize >= lines.Count {
+				break
+			}
+		}
+	}
+
+	return labels, nil
+}
+
+func productLabel(product *models.Product, copies int) label.Label {
+	return label.Label{
+		Name:    product.Name,
+		Price:   product.Price,
+		Barcode: product.Barcode,
+		Copies:  copies,
+	}
+}
+
+func (h *Handler) GetBarcodeImage(c *gin.Context) {
+
+	var (
+		code   = c.Param("barcode")
+		format = c.DefaultQuery("format", label.FormatSVG)
+		buf    bytes.Buffer
+		err    error
+	)
+
+	switch format {
+	case label.FormatSVG:
+		err = label.WriteSVG(&buf, code)
+	case label.FormatPNG:
+		err = label.WritePNG(&buf, code, 3)
+	default:
+		h.handlerResponse(c, "get barcode image", http.StatusBadRequest, "format must be svg or png")
+		return
+	}
+
+	if err != nil {
+		h.handlerResponse(c, "get barcode image", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.Data(http.StatusOK, label.ContentType(format), buf.Bytes())
+}
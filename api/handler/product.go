@@ -0,0 +1,251 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+
+	"market/api/models"
+	"market/pkg/helper"
+	"market/storage"
+)
+
+func (h *Handler) CreateProduct(c *gin.Context) {
+
+	var createProduct models.CreateProduct
+
+	err := c.ShouldBindJSON(&createProduct)
+	if err != nil {
+		h.handleError(c, "create product", helper.ValidationError(err))
+		return
+	}
+
+	id, err := h.strg.Product().Create(c.Request.Context(), &createProduct)
+	if err != nil {
+		h.handleError(c, "storage.product.create", err)
+		return
+	}
+
+	resp, err := h.strg.Product().GetByID(c.Request.Context(), &models.ProductPrimaryKey{Id: id})
+	if err != nil {
+		h.handleError(c, "storage.product.getById", err)
+		return
+	}
+
+	h.setProductImageURLs(resp)
+
+	h.handlerResponse(c, "create product", http.StatusCreated, resp)
+}
+
+func (h *Handler) GetByIdProduct(c *gin.Context) {
+
+	var id = c.Param("id")
+
+	includeDeleted, ok := h.getIncludeDeletedQuery(c, "get by id product")
+	if !ok {
+		return
+	}
+
+	resp, err := h.strg.Product().GetByID(c.Request.Context(), &models.ProductPrimaryKey{Id: id, IncludeDeleted: includeDeleted})
+	if err != nil {
+		h.handleError(c, "storage.product.getById", err)
+		return
+	}
+
+	h.setProductImageURLs(resp)
+
+	h.setETag(c, resp.Version)
+
+	h.handlerResponse(c, "get by id product", http.StatusOK, resp)
+}
+
+func (h *Handler) GetListProduct(c *gin.Context) {
+
+	offset, err := h.getOffsetQuery(c.Query("offset"))
+	if err != nil {
+		h.handlerResponse(c, "get list product", http.StatusBadRequest, "invalid offset")
+		return
+	}
+
+	limit, err := h.getLimitQuery(c.Query("limit"))
+	if err != nil {
+		h.handlerResponse(c, "get list product", http.StatusBadRequest, "invalid limit")
+		return
+	}
+
+	groupVariants, err := h.getBoolQuery(c.Query("group_variants"))
+	if err != nil {
+		h.handlerResponse(c, "get list product", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	includeDeleted, ok := h.getIncludeDeletedQuery(c, "get list product")
+	if !ok {
+		return
+	}
+
+	resp, err := h.strg.Product().GetList(c.Request.Context(), &models.ProductGetListRequest{
+		Offset:         offset,
+		Limit:          limit,
+		Search:         c.Query("search"),
+		CategoryId:     c.Query("category_id"),
+		ParentId:       c.Query("parent_id"),
+		GroupVariants:  groupVariants,
+		IncludeDeleted: includeDeleted,
+	})
+	if err != nil {
+		h.handleError(c, "storage.product.getList", err)
+		return
+	}
+
+	h.setProductImageURLs(resp.Products...)
+
+	h.handlerResponse(c, "get list product", http.StatusOK, resp)
+}
+
+func (h *Handler) UpdateProduct(c *gin.Context) {
+
+	var updateProduct models.UpdateProduct
+
+	err := c.ShouldBindJSON(&updateProduct)
+	if err != nil {
+		h.handleError(c, "update product", helper.ValidationError(err))
+		return
+	}
+
+	updateProduct.Id = c.Param("id")
+
+	version, ok := h.getIfMatchVersion(c, "update product", updateProduct.Version)
+	if !ok {
+		return
+	}
+
+	updateProduct.Version = version
+
+	_, err = h.strg.Product().Update(c.Request.Context(), &updateProduct)
+	if errors.Is(err, storage.ErrVersionConflict) {
+		current, err := h.strg.Product().GetByID(c.Request.Context(), &models.ProductPrimaryKey{Id: updateProduct.Id})
+		if err != nil {
+			h.handleError(c, "storage.product.getById", err)
+			return
+		}
+
+		h.setProductImageURLs(current)
+		h.setETag(c, current.Version)
+		h.handlerResponse(c, "storage.product.update", http.StatusConflict, current)
+		return
+	} else if err != nil {
+		h.handleError(c, "storage.product.update", err)
+		return
+	}
+
+	resp, err := h.strg.Product().GetByID(c.Request.Context(), &models.ProductPrimaryKey{Id: updateProduct.Id})
+	if err != nil {
+		h.handleError(c, "storage.product.getById", err)
+		return
+	}
+
+	h.setProductImageURLs(resp)
+
+	h.setETag(c, resp.Version)
+
+	h.handlerResponse(c, "update product", http.StatusAccepted, resp)
+}
+
+func (h *Handler) PatchProduct(c *gin.Context) {
+
+	var patchProduct models.PatchRequest
+
+	err := c.ShouldBindJSON(&patchProduct.Fields)
+	if err != nil {
+		h.handleError(c, "patch product", helper.ValidationError(err))
+		return
+	}
+
+	if value, ok := patchProduct.Fields["version"]; ok {
+		number, _ := value.(float64)
+		patchProduct.Version = int(number)
+		delete(patchProduct.Fields, "version")
+	}
+
+	engine, _ := binding.Validator.Engine().(*validator.Validate)
+
+	err = helper.ValidateProductPatch(engine, patchProduct.Fields)
+	if err != nil {
+		h.handleError(c, "patch product", err)
+		return
+	}
+
+	patchProduct.ID = c.Param("id")
+
+	version, ok := h.getIfMatchVersion(c, "patch product", patchProduct.Version)
+	if !ok {
+		return
+	}
+
+	patchProduct.Version = version
+
+	_, err = h.strg.Product().Patch(c.Request.Context(), &patchProduct)
+	if errors.Is(err, storage.ErrVersionConflict) {
+		current, err := h.strg.Product().GetByID(c.Request.Context(), &models.ProductPrimaryKey{Id: patchProduct.ID})
+		if err != nil {
+			h.handleError(c, "storage.product.getById", err)
+			return
+		}
+
+		h.setProductImageURLs(current)
+		h.setETag(c, current.Version)
+		h.handlerResponse(c, "storage.product.patch", http.StatusConflict, current)
+		return
+	} else if err != nil {
+		h.handleError(c, "storage.product.patch", err)
+		return
+	}
+
+	resp, err := h.strg.Product().GetByID(c.Request.Context(), &models.ProductPrimaryKey{Id: patchProduct.ID})
+	if err != nil {
+		h.handleError(c, "storage.product.getById", err)
+		return
+	}
+
+	h.setProductImageURLs(resp)
+
+	h.setETag(c, resp.Version)
+
+	h.handlerResponse(c, "patch product", http.StatusAccepted, resp)
+}
+
+func (h *Handler) DeleteProduct(c *gin.Context) {
+
+	var id = c.Param("id")
+
+	err := h.strg.Product().Delete(c.Request.Context(), &models.ProductPrimaryKey{Id: id})
+	if err != nil {
+		h.handleError(c, "storage.product.delete", err)
+		return
+	}
+
+	h.handlerResponse(c, "delete product", http.StatusNoContent, nil)
+}
+
+func (h *Handler) RestoreProduct(c *gin.Context) {
+
+	var id = c.Param("id")
+
+	_, err := h.strg.Product().Restore(c.Request.Context(), &models.ProductPrimaryKey{Id: id})
+	if err != nil {
+		h.handleError(c, "storage.product.restore", err)
+		return
+	}
+
+	resp, err := h.strg.Product().GetByID(c.Request.Context(), &models.ProductPrimaryKey{Id: id})
+	if err != nil {
+		h.handleError(c, "storage.product.getById", err)
+		return
+	}
+
+	h.handlerResponse(c, "restore product", http.StatusOK, resp)
+}
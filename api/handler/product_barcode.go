@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v4"
+
+	"market/api/models"
+	"market/pkg/barcode"
+	"market/pkg/helper"
+)
+
+func (h *Handler) CreateProductBarcode(c *gin.Context) {
+
+	var createProductBarcode models.CreateProductBarcode
+
+	err := c.ShouldBindJSON(&createProductBarcode)
+	if err != nil {
+		h.handleError(c, "create product barcode", helper.ValidationError(err))
+		return
+	}
+
+	createProductBarcode.ProductId = c.Param("id")
+
+	id, err := h.strg.ProductBarcode().Create(c.Request.Context(), &createProductBarcode)
+	if err != nil {
+		h.handleError(c, "storage.product_barcode.create", err)
+		return
+	}
+
+	resp, err := h.strg.ProductBarcode().GetByID(c.Request.Context(), &models.ProductBarcodePrimaryKey{Id: id})
+	if err != nil {
+		h.handleError(c, "storage.product_barcode.getById", err)
+		return
+	}
+
+	h.handlerResponse(c, "create product barcode", http.StatusCreated, resp)
+}
+
+func (h *Handler) GetListProductBarcode(c *gin.Context) {
+
+	resp, err := h.strg.ProductBarcode().GetList(c.Request.Context(), &models.ProductBarcodeGetListRequest{
+		ProductId: c.Param("id"),
+	})
+	if err != nil {
+		h.handleError(c, "storage.product_barcode.getList", err)
+		return
+	}
+
+	h.handlerResponse(c, "get list product barcode", http.StatusOK, resp)
+}
+
+func (h *Handler) DeleteProductBarcode(c *gin.Context) {
+
+	var id = c.Param("id")
+
+	err := h.strg.ProductBarcode().Delete(c.Request.Context(), &models.ProductBarcodePrimaryKey{Id: id})
+	if err != nil {
+		h.handleError(c, "storage.product_barcode.delete", err)
+		return
+	}
+
+	h.handlerResponse(c, "delete product barcode", http.StatusNoContent, nil)
+}
+
+func (h *Handler) GetByBarcodeProduct(c *gin.Context) {
+
+	var code = c.Param("barcode")
+
+	resp, err := h.strg.Product().GetByBarcode(c.Request.Context(), &models.ProductBarcodeLookup{Barcode: code})
+	if err == nil {
+		h.setProductImageURLs(resp.Product)
+		h.handlerResponse(c, "get product by barcode", http.StatusOK, resp)
+		return
+	}
+
+	if !errors.Is(err, pgx.ErrNoRows) {
+		h.handleError(c, "storage.product.getByBarcode", err)
+		return
+	}
+
+	// Scale labels carry weight or price inside the code, so resolve them by
+	// the base code the weighed item was registered with.
+	embedded, decodeErr := barcode.DecodeEmbedded(code, h.cfg.WeightBarcodePrefixes, h.cfg.PriceBarcodePrefixes)
+	if decodeErr != nil {
+		h.handlerResponse(c, "storage.product.getByBarcode", http.StatusNotFound, err.Error())
+		return
+	}
+
+	resp, err = h.strg.Product().GetByBarcode(c.Request.Context(), &models.ProductBarcodeLookup{Barcode: embedded.BaseCode})
+	if err != nil {
+		h.handlerResponse(c, "storage.product.getByBarcode", http.StatusNotFound, err.Error())
+		return
+	}
+
+	switch embedded.Kind {
+	case barcode.EmbeddedWeight:
+		resp.Weight = embedded.Value
+	case barcode.EmbeddedPrice:
+		resp.Price = embedded.Value
+		if resp.Product.Price > 0 {
+			resp.Weight = int32(int64(embedded.Value) * 1000 / int64(resp.Product.Price))
+		}
+	}
+
+	h.setProductImageURLs(resp.Product)
+
+	h.handlerResponse(c, "get product by barcode", http.StatusOK, resp)
+}
+
+func (h *Handler) GenerateBarcode(c *gin.Context) {
+
+	var generateBarcode models.GenerateBarcode
+
+	err := c.ShouldBindJSON(&generateBarcode)
+	if err != nil && !errors.Is(err, io.EOF) {
+		h.handleError(c, "generate barcode", helper.ValidationError(err))
+		return
+	}
+
+	generateBarcode.PrefixFrom = h.cfg.InternalBarcodePrefixFrom
+	generateBarcode.PrefixTo = h.cfg.InternalBarcodePrefixTo
+
+	if generateBarcode.Weighed {
+		if len(h.cfg.WeightBarcodePrefixes) <= 0 || h.cfg.WeightBarcodePrefixes[0] == "" {
+			h.handlerResponse(c, "generate barcode", http.StatusBadRequest, "no weight barcode prefix configured")
+			return
+		}
+
+		generateBarcode.WeightPrefix = h.cfg.WeightBarcodePrefixes[0]
+	}
+
+	code, err := h.strg.ProductBarcode().Generate(c.Request.Context(), &generateBarcode)
+	if err != nil {
+		h.handleError(c, "storage.product_barcode.generate", err)
+		return
+	}
+
+	h.handlerResponse(c, "generate barcode", http.StatusCreated, &models.GenerateBarcodeResponse{Barcode: code})
+}
@@ -0,0 +1,225 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"path"
+
+	"github.com/gin-gonic/gin"
+	uuid "github.com/google/uuid"
+
+	"market/api/models"
+	"market/pkg/blob"
+	"market/pkg/helper"
+	"market/pkg/thumbnail"
+)
+
+var imageExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+}
+
+func (h *Handler) UploadProductImage(c *gin.Context) {
+
+	var productId = c.Param("id")
+
+	_, err := h.strg.Product().GetByID(c.Request.Context(), &models.ProductPrimaryKey{Id: productId})
+	if err != nil {
+		h.handleError(c, "storage.product.getById", err)
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		h.handlerResponse(c, "upload product image", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if fileHeader.Size > h.cfg.ImageMaxSize {
+		h.handlerResponse(c, "upload product image", http.StatusRequestEntityTooLarge, "image is too large")
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		h.handlerResponse(c, "upload product image", http.StatusBadRequest, err.Error())
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		h.handlerResponse(c, "upload product image", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	contentType := http.DetectContentType(data)
+
+	extension, ok := imageExtensions[contentType]
+	if !ok {
+		h.handlerResponse(c, "upload product image", http.StatusUnsupportedMediaType, "unsupported image type: "+contentType)
+		return
+	}
+
+	thumb, err := thumbnail.Make(bytes.NewReader(data), h.cfg.ImageThumbnailSize)
+	if err != nil {
+		h.handlerResponse(c, "upload product image", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var (
+		name         = uuid.New().String()
+		fileKey      = path.Join("products", productId, name+extension)
+		thumbnailKey = path.Join("products", productId, name+"_thumb.jpg")
+	)
+
+	err = h.blob.Put(c.Request.Context(), fileKey, bytes.NewReader(data), int64(len(data)), contentType)
+	if err != nil {
+		h.handleError(c, "blob.put", err)
+		return
+	}
+
+	err = h.blob.Put(c.Request.Context(), thumbnailKey, bytes.NewReader(thumb), int64(len(thumb)), thumbnail.ContentType)
+	if err != nil {
+		h.handleError(c, "blob.put", err)
+		return
+	}
+
+	id, err := h.strg.ProductImage().Create(c.Request.Context(), &models.CreateProductImage{
+		ProductId:    productId,
+		FileKey:      fileKey,
+		ThumbnailKey: thumbnailKey,
+		ContentType:  contentType,
+		Size:         int64(len(data)),
+	})
+	if err != nil {
+		h.handleError(c, "storage.product_image.create", err)
+		return
+	}
+
+	resp, err := h.strg.ProductImage().GetByID(c.Request.Context(), &models.ProductImagePrimaryKey{Id: id})
+	if err != nil {
+		h.handleError(c, "storage.product_image.getById", err)
+		return
+	}
+
+	h.setImageURLs(resp)
+
+	h.handlerResponse(c, "upload product image", http.StatusCreated, resp)
+}
+
+func (h *Handler) GetProductImageFile(c *gin.Context) {
+	h.serveProductImage(c, false)
+}
+
+func (h *Handler) GetProductImageThumbnail(c *gin.Context) {
+	h.serveProductImage(c, true)
+}
+
+func (h *Handler) serveProductImage(c *gin.Context, thumb bool) {
+
+	image, err := h.strg.ProductImage().GetByID(c.Request.Context(), &models.ProductImagePrimaryKey{Id: c.Param("id")})
+	if err != nil {
+		h.handleError(c, "storage.product_image.getById", err)
+		return
+	}
+
+	var (
+		key         = image.FileKey
+		contentType = image.ContentType
+	)
+
+	if thumb {
+		key, contentType = image.ThumbnailKey, thumbnail.ContentType
+	}
+
+	reader, err := h.blob.Get(c.Request.Context(), key)
+	if errors.Is(err, blob.ErrNotFound) {
+		h.handlerResponse(c, "blob.get", http.StatusNotFound, err.Error())
+		return
+	} else if err != nil {
+		h.handleError(c, "blob.get", err)
+		return
+	}
+	defer reader.Close()
+
+	c.Header("Cache-Control", "public, max-age=86400")
+	c.DataFromReader(http.StatusOK, -1, contentType, reader, nil)
+}
+
+func (h *Handler) ReorderProductImage(c *gin.Context) {
+
+	var productImageOrder models.ProductImageOrder
+
+	err := c.ShouldBindJSON(&productImageOrder)
+	if err != nil {
+		h.handleError(c, "reorder product image", helper.ValidationError(err))
+		return
+	}
+
+	productImageOrder.ProductId = c.Param("id")
+
+	_, err = h.strg.ProductImage().Reorder(c.Request.Context(), &productImageOrder)
+	if err != nil {
+		h.handleError(c, "storage.product_image.reorder", err)
+		return
+	}
+
+	resp, err := h.strg.ProductImage().GetList(c.Request.Context(), &models.ProductImageGetListRequest{
+		ProductIds: []string{productImageOrder.ProductId},
+	})
+	if err != nil {
+		h.handleError(c, "storage.product_image.getList", err)
+		return
+	}
+
+	h.setImageURLs(resp.Images...)
+
+	h.handlerResponse(c, "reorder product image", http.StatusAccepted, resp)
+}
+
+func (h *Handler) DeleteProductImage(c *gin.Context) {
+
+	var id = c.Param("id")
+
+	image, err := h.strg.ProductImage().GetByID(c.Request.Context(), &models.ProductImagePrimaryKey{Id: id})
+	if err != nil {
+		h.handleError(c, "storage.product_image.getById", err)
+		return
+	}
+
+	err = h.strg.ProductImage().Delete(c.Request.Context(), &models.ProductImagePrimaryKey{Id: id})
+	if err != nil {
+		h.handleError(c, "storage.product_image.delete", err)
+		return
+	}
+
+	for _, key := range []string{image.FileKey, image.ThumbnailKey} {
+		err = h.blob.Delete(c.Request.Context(), key)
+		if err != nil {
+			h.log.Error("blob.delete " + key + ": " + err.Error())
+		}
+	}
+
+	h.handlerResponse(c, "delete product image", http.StatusNoContent, nil)
+}
+
+func (h *Handler) setImageURLs(images ...*models.ProductImage) {
+
+	for _, image := range images {
+		image.Url = h.cfg.PublicBaseURL + "/product_image/" + image.Id + "/file"
+		image.ThumbnailUrl = h.cfg.PublicBaseURL + "/product_image/" + image.Id + "/thumbnail"
+	}
+}
+
+// setProductImageURLs fills image URLs of products and their variants.
+func (h *Handler) setProductImageURLs(products ...*models.Product) {
+
+	for _, product := range products {
+		h.setImageURLs(product.Images...)
+		h.setProductImageURLs(product.Variants...)
+	}
+}
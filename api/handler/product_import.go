@@ -0,0 +1,371 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+
+	"market/api/models"
+	"market/pkg/apperr"
+	"market/pkg/barcode"
+	"market/pkg/helper"
+	"market/pkg/xlsx"
+)
+
+// productImportColumns lists the header names recognised for every field when
+// the request carries no explicit mapping.
+var productImportColumns = map[string][]string{
+	"name":     {"name", "nomi", "наименование", "название"},
+	"barcode":  {"barcode", "bracode", "shtrix kod", "штрихкод", "ean"},
+	"price":    {"price", "narx", "narxi", "цена"},
+	"category": {"category", "kategoriya", "категория"},
+}
+
+const categoryPathSeparator = "/"
+
+func (h *Handler) ImportProduct(c *gin.Context) {
+
+	name, data, err := h.readUpload(c)
+	if err != nil {
+		h.handlerResponse(c, "import product", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	rows, err := readTable(name, data)
+	if err != nil {
+		h.handlerResponse(c, "import product", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if len(rows) <= 1 {
+		h.handlerResponse(c, "import product", http.StatusBadRequest, "file has no data rows")
+		return
+	}
+
+	dryRun, err := h.getBoolQuery(c.PostForm("dry_run"))
+	if err != nil {
+		h.handlerResponse(c, "import product", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var mapping map[string]string
+
+	if value := c.PostForm("mapping"); value != "" {
+		err = json.Unmarshal([]byte(value), &mapping)
+		if err != nil {
+			h.handlerResponse(c, "import product", http.StatusBadRequest, "invalid mapping: "+err.Error())
+			return
+		}
+	}
+
+	columns, err := mapColumns(rows[0], mapping, productImportColumns)
+	if err != nil {
+		h.handlerResponse(c, "import product", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	for _, field := range []string{"name", "barcode", "price"} {
+		if _, ok := columns[field]; !ok {
+			h.handlerResponse(c, "import product", http.StatusBadRequest, "no column found for "+field)
+			return
+		}
+	}
+
+	importRows, rowErrors := parseProductImportRows(rows, columns)
+
+	if len(rowErrors) > 0 {
+		h.handlerResponse(c, "import product", http.StatusBadRequest, &models.ProductImportResponse{
+			DryRun: dryRun,
+			Total:  len(importRows) + len(rowErrors),
+			Errors: rowErrors,
+		})
+		return
+	}
+
+	resp, err := h.strg.Product().Import(c.Request.Context(), &models.ProductImport{
+		DryRun: dryRun,
+		Rows:   importRows,
+	})
+	if err != nil {
+		h.handleError(c, "storage.product.import", err)
+		return
+	}
+
+	if len(resp.Errors) > 0 {
+		h.handlerResponse(c, "import product", http.StatusBadRequest, resp)
+		return
+	}
+
+	if dryRun {
+		h.handlerResponse(c, "import product", http.StatusOK, resp)
+		return
+	}
+
+	h.handlerResponse(c, "import product", http.StatusCreated, resp)
+}
+
+// parseProductImportRows validates every data row and returns all problems
+// found instead of stopping at the first one.
+func parseProductImportRows(rows [][]string, columns map[string]int) ([]*models.ProductImportRow, []*models.ProductImportRowError) {
+
+	var (
+		importRows []*models.ProductImportRow
+		rowErrors  []*models.ProductImportRowError
+		seen       = map[string]int{}
+	)
+
+	for i, cells := range rows[1:] {
+		var (
+			line    = i + 2
+			row     = &models.ProductImportRow{Row: line}
+			invalid bool
+		)
+
+		if isEmptyRow(cells) {
+			continue
+		}
+
+		fail := func(field, message string) {
+			rowErrors = append(rowErrors, &models.ProductImportRowError{Row: line, Field: field, Message: message})
+			invalid = true
+		}
+
+		row.Name = cell(cells, columns, "name")
+		if row.Name == "" {
+			fail("name", "name is required")
+		} else if utf8.RuneCountInString(row.Name) > 55 {
+			fail("name", "name is longer than 55 characters")
+		}
+
+		row.Barcode = cell(cells, columns, "barcode")
+		if _, err := barcode.Validate(row.Barcode); err != nil {
+			fail("barcode", err.Error())
+		} else if first, ok := seen[row.Barcode]; ok {
+			fail("barcode", fmt.Sprintf("barcode %s already used in row %d", row.Barcode, first))
+		} else {
+			seen[row.Barcode] = line
+		}
+
+		price, err := parsePrice(cell(cells, columns, "price"))
+		if err != nil {
+			fail("price", err.Error())
+		}
+		row.Price = price
+
+		if path := cell(cells, columns, "category"); path != "" {
+			for _, title := range strings.Split(path, categoryPathSeparator) {
+				title = strings.TrimSpace(title)
+				if title == "" {
+					fail("category", "category path "+path+" has an empty title")
+					break
+				}
+
+				if utf8.RuneCountInString(title) > 50 {
+					fail("category", "category title "+title+" is longer than 50 characters")
+					break
+				}
+
+				row.CategoryPath = append(row.CategoryPath, title)
+			}
+		}
+
+		if invalid {
+			continue
+		}
+
+		if errs := importRowErrors(line, row); len(errs) > 0 {
+			rowErrors = append(rowErrors, errs...)
+			continue
+		}
+
+		importRows = append(importRows, row)
+	}
+
+	return importRows, rowErrors
+}
+
+// importRowErrors checks a parsed row of line against its binding rules and
+// lists every field that is wrong.
+func importRowErrors(line int, row interface{}) []*models.ProductImportRowError {
+
+	err := binding.Validator.ValidateStruct(row)
+	if err == nil {
+		return nil
+	}
+
+	appErr, _ := apperr.As(helper.ValidationError(err))
+
+	if len(appErr.Fields) <= 0 {
+		return []*models.ProductImportRowError{{Row: line, Message: appErr.Message}}
+	}
+
+	rowErrors := make([]*models.ProductImportRowError, 0, len(appErr.Fields))
+	for _, field := range appErr.Fields {
+		rowErrors = append(rowErrors, &models.ProductImportRowError{
+			Row:     line,
+			Field:   field.Field,
+			Message: field.Field + " " + field.Message,
+		})
+	}
+
+	return rowErrors
+}
+
+// readUpload returns the name and content of the uploaded "file".
+func (h *Handler) readUpload(c *gin.Context) (string, []byte, error) {
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		return "", nil, err
+	}
+
+	if fileHeader.Size > h.cfg.ImportMaxSize {
+		return "", nil, errors.New("file is too large")
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return "", nil, err
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return fileHeader.Filename, data, nil
+}
+
+// readTable parses CSV or XLSX content depending on the file extension.
+func readTable(name string, data []byte) ([][]string, error) {
+
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".xlsx":
+		return xlsx.ReadAll(bytes.NewReader(data), int64(len(data)))
+	case ".csv", ".txt":
+		return readCSV(data)
+	default:
+		return nil, errors.New("unsupported file type, expected .csv or .xlsx")
+	}
+}
+
+func readCSV(data []byte) ([][]string, error) {
+
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+
+	var (
+		firstLine = data
+		reader    = csv.NewReader(bytes.NewReader(data))
+	)
+
+	if i := bytes.IndexByte(data, '\n'); i >= 0 {
+		firstLine = data[:i]
+	}
+
+	// Spreadsheets in most locales here export with semicolons.
+	switch {
+	case bytes.Count(firstLine, []byte(";")) > bytes.Count(firstLine, []byte(",")):
+		reader.Comma = ';'
+	case bytes.Count(firstLine, []byte("\t")) > bytes.Count(firstLine, []byte(",")):
+		reader.Comma = '\t'
+	}
+
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	return reader.ReadAll()
+}
+
+// mapColumns finds the column of every field, by the explicit mapping first
+// and by the known header names otherwise.
+func mapColumns(header []string, mapping map[string]string, known map[string][]string) (map[string]int, error) {
+
+	var (
+		columns = map[string]int{}
+		index   = map[string]int{}
+	)
+
+	for i, title := range header {
+		index[strings.ToLower(strings.TrimSpace(title))] = i
+	}
+
+	for field, title := range mapping {
+		if _, ok := known[field]; !ok {
+			return nil, errors.New("unknown field in mapping: " + field)
+		}
+
+		i, ok := index[strings.ToLower(strings.TrimSpace(title))]
+		if !ok {
+			return nil, errors.New("column " + title + " not found in file")
+		}
+
+		columns[field] = i
+	}
+
+	for field, titles := range known {
+		if _, ok := columns[field]; ok {
+			continue
+		}
+
+		for _, title := range titles {
+			if i, ok := index[title]; ok {
+				columns[field] = i
+				break
+			}
+		}
+	}
+
+	return columns, nil
+}
+
+func cell(cells []string, columns map[string]int, field string) string {
+
+	i, ok := columns[field]
+	if !ok || i >= len(cells) {
+		return ""
+	}
+
+	return strings.TrimSpace(cells[i])
+}
+
+func isEmptyRow(cells []string) bool {
+
+	for _, value := range cells {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+
+	return true
+}
+
+// parsePrice accepts whole amounts written with spaces as thousands
+// separators and an optional zero fraction, e.g. "12 500" or "12500,00".
+func parsePrice(value string) (int32, error) {
+
+	if strings.TrimSpace(value) == "" {
+		return 0, errors.New("price is required")
+	}
+
+	price, err := parseNumber(value)
+	if err != nil {
+		return 0, errors.New("price " + value + " is not a number")
+	}
+
+	if price < 0 || price > float64(1<<31-1) || price != float64(int64(price)) {
+		return 0, errors.New("price " + value + " must be a non-negative whole amount")
+	}
+
+	return int32(price), nil
+}
@@ -0,0 +1,164 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"market/api/models"
+	"market/pkg/rbac"
+)
+
+// Permit checks the role of the caller against the entity of the route, its
+// first path segment, and the action implied by the HTTP method.
+// API keys are checked against their scopes instead.
+func (h *Handler) Permit(c *gin.Context) {
+
+	entity := strings.SplitN(strings.TrimPrefix(c.FullPath(), "/"), "/", 2)[0]
+
+	action := rbac.Write
+	switch c.Request.Method {
+	case http.MethodGet, http.MethodHead:
+		action = rbac.Read
+	case http.MethodDelete:
+		action = rbac.Delete
+	}
+
+	// Restoring undoes a delete and takes the same permission.
+	if strings.HasSuffix(c.FullPath(), "/restore") {
+		action = rbac.Delete
+	}
+
+	if apiKey := h.currentApiKey(c); apiKey != nil {
+		if !rbac.ScopeAllowed(apiKey.Scopes, entity, action) {
+			h.handlerResponse(c, "permit", http.StatusForbidden, "api key has no scope to "+c.Request.Method+" "+entity)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+		return
+	}
+
+	if entity == "auth" {
+		c.Next()
+		return
+	}
+
+	user := h.currentUser(c)
+	if user == nil || !rbac.Allowed(user.Role, entity, action) {
+		h.handlerResponse(c, "permit", http.StatusForbidden, "not allowed to "+c.Request.Method+" "+entity)
+		c.Abort()
+		return
+	}
+
+	c.Next()
+}
+
+func (h *Handler) currentUser(c *gin.Context) *models.User {
+	user, _ := c.Value(contextUser).(*models.User)
+	return user
+}
+
+func (h *Handler) currentApiKey(c *gin.Context) *models.ApiKey {
+	apiKey, _ := c.Value(contextApiKey).(*models.ApiKey)
+	return apiKey
+}
+
+// branchScope returns the branches the caller may work with, nil when the
+// caller is not restricted. A restricted caller without branches gets an
+// empty, non-nil slice so lists come back empty. API keys are limited by
+// their scopes only.
+func (h *Handler) branchScope(c *gin.Context) []string {
+
+	if h.currentApiKey(c) != nil {
+		return nil
+	}
+
+	user := h.currentUser(c)
+	if user == nil {
+		return []string{}
+	}
+
+	if rbac.AllBranches(user.Role) {
+		return nil
+	}
+
+	if user.BranchIds == nil {
+		return []string{}
+	}
+
+	return user.BranchIds
+}
+
+func (h *Handler) inBranchScope(c *gin.Context, branchId string) bool {
+
+	scope := h.branchScope(c)
+	if scope == nil {
+		return true
+	}
+
+	for _, id := range scope {
+		if id == branchId {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (h *Handler) outOfBranchScope(c *gin.Context, path string) {
+	h.handlerResponse(c, path, http.StatusForbidden, "branch is out of scope")
+}
+
+// storageComingInScope reports whether the storage coming, and so its lines,
+// belongs to a branch of the caller.
+func (h *Handler) storageComingInScope(c *gin.Context, id string) (bool, error) {
+
+	if h.branchScope(c) == nil {
+		return true, nil
+	}
+
+	storageComing, err := h.strg.StorageComing().GetByID(c.Request.Context(), &models.StorageComingPrimaryKey{Id: id, IncludeDeleted: true})
+	if err != nil {
+		return false, err
+	}
+
+	return h.inBranchScope(c, storageComing.BranchId), nil
+}
+
+func (h *Handler) storageComingProductInScope(c *gin.Context, id string) (bool, error) {
+
+	if h.branchScope(c) == nil {
+		return true, nil
+	}
+
+	storageComingProduct, err := h.strg.StorageComingProduct().GetByID(c.Request.Context(), &models.StorageComingProductPrimaryKey{Id: id, IncludeDeleted: true})
+	if err != nil {
+		return false, err
+	}
+
+	return h.storageComingInScope(c, storageComingProduct.StorageComingId)
+}
+
+// getIncludeDeletedQuery reads the include_deleted query param, which only
+// admins may set. It answers the request itself when it returns false.
+func (h *Handler) getIncludeDeletedQuery(c *gin.Context, path string) (bool, bool) {
+
+	includeDeleted, err := h.getBoolQuery(c.Query("include_deleted"))
+	if err != nil {
+		h.handlerResponse(c, path, http.StatusBadRequest, err.Error())
+		return false, false
+	}
+
+	if includeDeleted {
+		user := h.currentUser(c)
+		if user == nil || user.Role != rbac.RoleAdmin {
+			h.handlerResponse(c, path, http.StatusForbidden, "include_deleted is allowed to admins only")
+			return false, false
+		}
+	}
+
+	return includeDeleted, true
+}
@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"market/api/models"
+	"market/pkg/helper"
+)
+
+func (h *Handler) GetByIdRemaining(c *gin.Context) {
+
+	var id = c.Param("id")
+
+	resp, err := h.strg.Remaining().GetByID(c.Request.Context(), &models.RemainingPrimaryKey{Id: id})
+	if err != nil {
+		h.handleError(c, "storage.remaining.getById", err)
+		return
+	}
+
+	if !h.inBranchScope(c, resp.BranchId) {
+		h.outOfBranchScope(c, "get by id remaining")
+		return
+	}
+
+	h.handlerResponse(c, "get by id remaining", http.StatusOK, resp)
+}
+
+func (h *Handler) GetListRemaining(c *gin.Context) {
+
+	offset, err := h.getOffsetQuery(c.Query("offset"))
+	if err != nil {
+		h.handlerResponse(c, "get list remaining", http.StatusBadRequest, "invalid offset")
+		return
+	}
+
+	limit, err := h.getLimitQuery(c.Query("limit"))
+	if err != nil {
+		h.handlerResponse(c, "get list remaining", http.StatusBadRequest, "invalid limit")
+		return
+	}
+
+	resp, err := h.strg.Remaining().GetList(c.Request.Context(), &models.RemainingGetListRequest{
+		Offset:     offset,
+		Limit:      limit,
+		Search:     c.Query("search"),
+		BranchId:   c.Query("branch_id"),
+		CategoryId: c.Query("category_id"),
+		BranchIds:  h.branchScope(c),
+	})
+	if err != nil {
+		h.handleError(c, "storage.remaining.getList", err)
+		return
+	}
+
+	h.handlerResponse(c, "get list remaining", http.StatusOK, resp)
+}
+
+func (h *Handler) GetListRemainingBatch(c *gin.Context) {
+
+	var id = c.Param("id")
+
+	if h.branchScope(c) != nil {
+		remaining, err := h.strg.Remaining().GetByID(c.Request.Context(), &models.RemainingPrimaryKey{Id: id})
+		if err != nil {
+			h.handleError(c, "storage.remaining.getById", err)
+			return
+		}
+
+		if !h.inBranchScope(c, remaining.BranchId) {
+			h.outOfBranchScope(c, "get list remaining batch")
+			return
+		}
+	}
+
+	resp, err := h.strg.Remaining().GetBatchList(c.Request.Context(), &models.RemainingBatchGetListRequest{RemainingId: id})
+	if err != nil {
+		h.handleError(c, "storage.remaining.getBatchList", err)
+		return
+	}
+
+	h.handlerResponse(c, "get list remaining batch", http.StatusOK, resp)
+}
+
+func (h *Handler) ConsumeRemaining(c *gin.Context) {
+
+	var consumeRemaining models.ConsumeRemaining
+
+	err := c.ShouldBindJSON(&consumeRemaining)
+	if err != nil {
+		h.handleError(c, "consume remaining", helper.ValidationError(err))
+		return
+	}
+
+	if !h.inBranchScope(c, consumeRemaining.BranchId) {
+		h.outOfBranchScope(c, "consume remaining")
+		return
+	}
+
+	resp, err := h.strg.Remaining().Consume(c.Request.Context(), &consumeRemaining)
+	if err != nil {
+		h.handleError(c, "storage.remaining.consume", err)
+		return
+	}
+
+	h.handlerResponse(c, "consume remaining", http.StatusOK, resp)
+}
@@ -0,0 +1,201 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"market/api/models"
+)
+
+const reportTimeLayout = "2006-01-02 15:04:05"
+
+func (h *Handler) InventoryValuationReport(c *gin.Context) {
+
+	asOf, err := h.getReportTimeQuery(c.Query("as_of"), true)
+	if err != nil {
+		h.handlerResponse(c, "inventory valuation report", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if asOf == "" {
+		asOf = time.Now().Format(reportTimeLayout)
+	}
+
+	method := c.DefaultQuery("method", models.CostingFIFO)
+
+	switch method {
+	case models.CostingFIFO, models.CostingAverage, models.CostingLastPurchase:
+	default:
+		h.handlerResponse(c, "inventory valuation report", http.StatusBadRequest, "invalid method, expected fifo, average or last")
+		return
+	}
+
+	resp, err := h.strg.Report().InventoryValuation(c.Request.Context(), &models.InventoryValuationRequest{
+		BranchId:   c.Query("branch_id"),
+		CategoryId: c.Query("category_id"),
+		Method:     method,
+		AsOf:       asOf,
+		BranchIds:  h.branchScope(c),
+	})
+	if err != nil {
+		h.handleError(c, "storage.report.inventoryValuation", err)
+		return
+	}
+
+	h.handlerResponse(c, "inventory valuation report", http.StatusOK, resp)
+}
+
+func (h *Handler) GoodsReceiptReport(c *gin.Context) {
+
+	offset, err := h.getOffsetQuery(c.Query("offset"))
+	if err != nil {
+		h.handlerResponse(c, "goods receipt report", http.StatusBadRequest, "invalid offset")
+		return
+	}
+
+	limit, err := h.getLimitQuery(c.Query("limit"))
+	if err != nil {
+		h.handlerResponse(c, "goods receipt report", http.StatusBadRequest, "invalid limit")
+		return
+	}
+
+	from, err := h.getReportTimeQuery(c.Query("from"), false)
+	if err != nil {
+		h.handlerResponse(c, "goods receipt report", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	to, err := h.getReportTimeQuery(c.Query("to"), true)
+	if err != nil {
+		h.handlerResponse(c, "goods receipt report", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	groupBy := c.DefaultQuery("group_by", models.GoodsReceiptGroupBranch)
+
+	switch groupBy {
+	case models.GoodsReceiptGroupBranch, models.GoodsReceiptGroupCategory, models.GoodsReceiptGroupProduct,
+		models.GoodsReceiptGroupDay, models.GoodsReceiptGroupWeek, models.GoodsReceiptGroupMonth,
+		models.GoodsReceiptGroupReceipt:
+	default:
+		h.handlerResponse(c, "goods receipt report", http.StatusBadRequest, "invalid group_by, expected branch, category, product, day, week, month or receipt")
+		return
+	}
+
+	resp, err := h.strg.Report().GoodsReceipt(c.Request.Context(), &models.GoodsReceiptReportRequest{
+		Offset:     offset,
+		Limit:      limit,
+		GroupBy:    groupBy,
+		From:       from,
+		To:         to,
+		Status:     c.Query("status"),
+		BranchId:   c.Query("branch_id"),
+		CategoryId: c.Query("category_id"),
+		ProductId:  c.Query("product_id"),
+		BranchIds:  h.branchScope(c),
+	})
+	if err != nil {
+		h.handleError(c, "storage.report.goodsReceipt", err)
+		return
+	}
+
+	h.handlerResponse(c, "goods receipt report", http.StatusOK, resp)
+}
+
+func (h *Handler) ReorderReport(c *gin.Context) {
+
+	all, err := h.getBoolQuery(c.Query("all"))
+	if err != nil {
+		h.handlerResponse(c, "reorder report", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	windowDays := h.cfg.ReorderWindowDays
+	if value := c.Query("window_days"); value != "" {
+		windowDays, err = strconv.Atoi(value)
+		if err != nil || windowDays <= 0 {
+			h.handlerResponse(c, "reorder report", http.StatusBadRequest, "invalid window_days")
+			return
+		}
+	}
+
+	leadDays := h.cfg.ReorderLeadDays
+	if value := c.Query("lead_days"); value != "" {
+		leadDays, err = strconv.Atoi(value)
+		if err != nil || leadDays < 0 {
+			h.handlerResponse(c, "reorder report", http.StatusBadRequest, "invalid lead_days")
+			return
+		}
+	}
+
+	resp, err := h.strg.Report().Reorder(c.Request.Context(), &models.ReorderReportRequest{
+		BranchId:   c.Query("branch_id"),
+		WindowDays: windowDays,
+		LeadDays:   leadDays,
+		All:        all,
+		BranchIds:  h.branchScope(c),
+	})
+	if err != nil {
+		h.handleError(c, "storage.report.reorder", err)
+		return
+	}
+
+	h.handlerResponse(c, "reorder report", http.StatusOK, resp)
+}
+
+func (h *Handler) ExpiringReport(c *gin.Context) {
+
+	var (
+		days = 30
+		err  error
+	)
+
+	if value := c.Query("days"); value != "" {
+		days, err = strconv.Atoi(value)
+		if err != nil || days < 0 {
+			h.handlerResponse(c, "expiring report", http.StatusBadRequest, "invalid days")
+			return
+		}
+	}
+
+	resp, err := h.strg.Report().Expiring(c.Request.Context(), &models.ExpiringReportRequest{
+		BranchId:  c.Query("branch_id"),
+		Days:      days,
+		BranchIds: h.branchScope(c),
+	})
+	if err != nil {
+		h.handleError(c, "storage.report.expiring", err)
+		return
+	}
+
+	h.handlerResponse(c, "expiring report", http.StatusOK, resp)
+}
+
+// getReportTimeQuery accepts a date or an RFC 3339 time. A bare date means
+// the start of that day, or its end when endOfDay is set.
+func (h *Handler) getReportTimeQuery(value string, endOfDay bool) (string, error) {
+
+	if len(value) <= 0 {
+		return "", nil
+	}
+
+	date, err := time.Parse("2006-01-02", value)
+	if err == nil {
+		if endOfDay {
+			date = date.Add(24*time.Hour - time.Second)
+		}
+
+		return date.Format(reportTimeLayout), nil
+	}
+
+	moment, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return "", errors.New("invalid date query param: " + value)
+	}
+
+	return moment.Local().Format(reportTimeLayout), nil
+}
@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"market/api/models"
+	"market/pkg/helper"
+)
+
+func (h *Handler) GetByIdSerialNumber(c *gin.Context) {
+
+	var id = c.Param("id")
+
+	resp, err := h.strg.SerialNumber().GetByID(c.Request.Context(), &models.SerialNumberPrimaryKey{Id: id})
+	if err != nil {
+		h.handleError(c, "storage.serial_number.getById", err)
+		return
+	}
+
+	if !h.inBranchScope(c, resp.BranchId) {
+		h.outOfBranchScope(c, "get by id serial number")
+		return
+	}
+
+	h.handlerResponse(c, "get by id serial number", http.StatusOK, resp)
+}
+
+func (h *Handler) GetListSerialNumber(c *gin.Context) {
+
+	offset, err := h.getOffsetQuery(c.Query("offset"))
+	if err != nil {
+		h.handlerResponse(c, "get list serial number", http.StatusBadRequest, "invalid offset")
+		return
+	}
+
+	limit, err := h.getLimitQuery(c.Query("limit"))
+	if err != nil {
+		h.handlerResponse(c, "get list serial number", http.StatusBadRequest, "invalid limit")
+		return
+	}
+
+	resp, err := h.strg.SerialNumber().GetList(c.Request.Context(), &models.SerialNumberGetListRequest{
+		Offset:    offset,
+		Limit:     limit,
+		Serial:    c.Query("serial"),
+		ProductId: c.Query("product_id"),
+		BranchId:  c.Query("branch_id"),
+		Status:    c.Query("status"),
+		BranchIds: h.branchScope(c),
+	})
+	if err != nil {
+		h.handleError(c, "storage.serial_number.getList", err)
+		return
+	}
+
+	h.handlerResponse(c, "get list serial number", http.StatusOK, resp)
+}
+
+func (h *Handler) MoveSerialNumber(c *gin.Context) {
+
+	var moveSerialNumber models.MoveSerialNumber
+
+	err := c.ShouldBindJSON(&moveSerialNumber)
+	if err != nil {
+		h.handleError(c, "move serial number", helper.ValidationError(err))
+		return
+	}
+
+	moveSerialNumber.Id = c.Param("id")
+
+	if h.branchScope(c) != nil {
+		serialNumber, err := h.strg.SerialNumber().GetByID(c.Request.Context(), &models.SerialNumberPrimaryKey{Id: moveSerialNumber.Id})
+		if err != nil {
+			h.handleError(c, "storage.serial_number.getById", err)
+			return
+		}
+
+		if !h.inBranchScope(c, serialNumber.BranchId) || (moveSerialNumber.BranchId != "" && !h.inBranchScope(c, moveSerialNumber.BranchId)) {
+			h.outOfBranchScope(c, "move serial number")
+			return
+		}
+	}
+
+	_, err = h.strg.SerialNumber().Move(c.Request.Context(), &moveSerialNumber)
+	if err != nil {
+		h.handleError(c, "storage.serial_number.move", err)
+		return
+	}
+
+	resp, err := h.strg.SerialNumber().GetByID(c.Request.Context(), &models.SerialNumberPrimaryKey{Id: moveSerialNumber.Id})
+	if err != nil {
+		h.handleError(c, "storage.serial_number.getById", err)
+		return
+	}
+
+	h.handlerResponse(c, "move serial number", http.StatusAccepted, resp)
+}
@@ -0,0 +1,122 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"market/api/models"
+	"market/pkg/apperr"
+	"market/pkg/helper"
+)
+
+func (h *Handler) CreateStockLevel(c *gin.Context) {
+
+	var createStockLevel models.CreateStockLevel
+
+	err := c.ShouldBindJSON(&createStockLevel)
+	if err != nil {
+		h.handleError(c, "create stock level", helper.ValidationError(err))
+		return
+	}
+
+	if createStockLevel.MaxCount < createStockLevel.MinCount {
+		h.handleError(c, "create stock level", apperr.Validation("invalid request", apperr.FieldError{
+			Field:   "max_count",
+			Message: "must be at least min_count",
+		}))
+		return
+	}
+
+	if !h.inBranchScope(c, createStockLevel.BranchId) {
+		h.outOfBranchScope(c, "create stock level")
+		return
+	}
+
+	id, err := h.strg.StockLevel().Create(c.Request.Context(), &createStockLevel)
+	if err != nil {
+		h.handleError(c, "storage.stock_level.create", err)
+		return
+	}
+
+	resp, err := h.strg.StockLevel().GetByID(c.Request.Context(), &models.StockLevelPrimaryKey{Id: id})
+	if err != nil {
+		h.handleError(c, "storage.stock_level.getById", err)
+		return
+	}
+
+	h.handlerResponse(c, "create stock level", http.StatusCreated, resp)
+}
+
+func (h *Handler) GetByIdStockLevel(c *gin.Context) {
+
+	var id = c.Param("id")
+
+	resp, err := h.strg.StockLevel().GetByID(c.Request.Context(), &models.StockLevelPrimaryKey{Id: id})
+	if err != nil {
+		h.handleError(c, "storage.stock_level.getById", err)
+		return
+	}
+
+	if !h.inBranchScope(c, resp.BranchId) {
+		h.outOfBranchScope(c, "get by id stock level")
+		return
+	}
+
+	h.handlerResponse(c, "get by id stock level", http.StatusOK, resp)
+}
+
+func (h *Handler) GetListStockLevel(c *gin.Context) {
+
+	offset, err := h.getOffsetQuery(c.Query("offset"))
+	if err != nil {
+		h.handlerResponse(c, "get list stock level", http.StatusBadRequest, "invalid offset")
+		return
+	}
+
+	limit, err := h.getLimitQuery(c.Query("limit"))
+	if err != nil {
+		h.handlerResponse(c, "get list stock level", http.StatusBadRequest, "invalid limit")
+		return
+	}
+
+	resp, err := h.strg.StockLevel().GetList(c.Request.Context(), &models.StockLevelGetListRequest{
+		Offset:    offset,
+		Limit:     limit,
+		BranchId:  c.Query("branch_id"),
+		ProductId: c.Query("product_id"),
+		BranchIds: h.branchScope(c),
+	})
+	if err != nil {
+		h.handleError(c, "storage.stock_level.getList", err)
+		return
+	}
+
+	h.handlerResponse(c, "get list stock level", http.StatusOK, resp)
+}
+
+func (h *Handler) DeleteStockLevel(c *gin.Context) {
+
+	var id = c.Param("id")
+
+	if h.branchScope(c) != nil {
+		stockLevel, err := h.strg.StockLevel().GetByID(c.Request.Context(), &models.StockLevelPrimaryKey{Id: id})
+		if err != nil {
+			h.handleError(c, "storage.stock_level.getById", err)
+			return
+		}
+
+		if !h.inBranchScope(c, stockLevel.BranchId) {
+			h.outOfBranchScope(c, "delete stock level")
+			return
+		}
+	}
+
+	err := h.strg.StockLevel().Delete(c.Request.Context(), &models.StockLevelPrimaryKey{Id: id})
+	if err != nil {
+		h.handleError(c, "storage.stock_level.delete", err)
+		return
+	}
+
+	h.handlerResponse(c, "delete stock level", http.StatusNoContent, nil)
+}
@@ -0,0 +1,289 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"market/api/models"
+	"market/pkg/helper"
+	"market/storage"
+)
+
+func (h *Handler) CreateStorageComing(c *gin.Context) {
+
+	var createStorageComing models.CreateStorageComing
+
+	err := c.ShouldBindJSON(&createStorageComing)
+	if err != nil {
+		h.handleError(c, "create storage coming", helper.ValidationError(err))
+		return
+	}
+
+	if !h.inBranchScope(c, createStorageComing.BranchId) {
+		h.outOfBranchScope(c, "create storage coming")
+		return
+	}
+
+	id, err := h.strg.StorageComing().Create(c.Request.Context(), &createStorageComing)
+	if err != nil {
+		h.handleError(c, "storage.storage_coming.create", err)
+		return
+	}
+
+	resp, err := h.strg.StorageComing().GetByID(c.Request.Context(), &models.StorageComingPrimaryKey{Id: id})
+	if err != nil {
+		h.handleError(c, "storage.storage_coming.getById", err)
+		return
+	}
+
+	h.handlerResponse(c, "create storage coming", http.StatusCreated, resp)
+}
+
+func (h *Handler) GetByIdStorageComing(c *gin.Context) {
+
+	var id = c.Param("id")
+
+	includeDeleted, ok := h.getIncludeDeletedQuery(c, "get by id storage coming")
+	if !ok {
+		return
+	}
+
+	resp, err := h.strg.StorageComing().GetByID(c.Request.Context(), &models.StorageComingPrimaryKey{Id: id, IncludeDeleted: includeDeleted})
+	if err != nil {
+		h.handleError(c, "storage.storage_coming.getById", err)
+		return
+	}
+
+	if !h.inBranchScope(c, resp.BranchId) {
+		h.outOfBranchScope(c, "get by id storage coming")
+		return
+	}
+
+	h.setETag(c, resp.Version)
+
+	h.handlerResponse(c, "get by id storage coming", http.StatusOK, resp)
+}
+
+func (h *Handler) GetListStorageComing(c *gin.Context) {
+
+	offset, err := h.getOffsetQuery(c.Query("offset"))
+	if err != nil {
+		h.handlerResponse(c, "get list storage coming", http.StatusBadRequest, "invalid offset")
+		return
+	}
+
+	limit, err := h.getLimitQuery(c.Query("limit"))
+	if err != nil {
+		h.handlerResponse(c, "get list storage coming", http.StatusBadRequest, "invalid limit")
+		return
+	}
+
+	includeDeleted, ok := h.getIncludeDeletedQuery(c, "get list storage coming")
+	if !ok {
+		return
+	}
+
+	resp, err := h.strg.StorageComing().GetList(c.Request.Context(), &models.StorageComingGetListRequest{
+		Offset:         offset,
+		Limit:          limit,
+		Search:         c.Query("search"),
+		BranchIds:      h.branchScope(c),
+		IncludeDeleted: includeDeleted,
+	})
+	if err != nil {
+		h.handleError(c, "storage.storage_coming.getList", err)
+		return
+	}
+
+	h.handlerResponse(c, "get list storage coming", http.StatusOK, resp)
+}
+
+func (h *Handler) UpdateStorageComing(c *gin.Context) {
+
+	var updateStorageComing models.UpdateStorageComing
+
+	err := c.ShouldBindJSON(&updateStorageComing)
+	if err != nil {
+		h.handleError(c, "update storage coming", helper.ValidationError(err))
+		return
+	}
+
+	updateStorageComing.Id = c.Param("id")
+
+	version, ok := h.getIfMatchVersion(c, "update storage coming", updateStorageComing.Version)
+	if !ok {
+		return
+	}
+
+	updateStorageComing.Version = version
+
+	inScope, err := h.storageComingInScope(c, updateStorageComing.Id)
+	if err != nil {
+		h.handleError(c, "storage.storage_coming.getById", err)
+		return
+	}
+
+	if !inScope || (updateStorageComing.BranchId != "" && !h.inBranchScope(c, updateStorageComing.BranchId)) {
+		h.outOfBranchScope(c, "update storage coming")
+		return
+	}
+
+	_, err = h.strg.StorageComing().Update(c.Request.Context(), &updateStorageComing)
+	if errors.Is(err, storage.ErrVersionConflict) {
+		current, err := h.strg.StorageComing().GetByID(c.Request.Context(), &models.StorageComingPrimaryKey{Id: updateStorageComing.Id})
+		if err != nil {
+			h.handleError(c, "storage.storage_coming.getById", err)
+			return
+		}
+
+		h.setETag(c, current.Version)
+		h.handlerResponse(c, "storage.storage_coming.update", http.StatusConflict, current)
+		return
+	} else if err != nil {
+		h.handleError(c, "storage.storage_coming.update", err)
+		return
+	}
+
+	resp, err := h.strg.StorageComing().GetByID(c.Request.Context(), &models.StorageComingPrimaryKey{Id: updateStorageComing.Id})
+	if err != nil {
+		h.handleError(c, "storage.storage_coming.getById", err)
+		return
+	}
+
+	h.setETag(c, resp.Version)
+
+	h.handlerResponse(c, "update storage coming", http.StatusAccepted, resp)
+}
+
+func (h *Handler) DeleteStorageComing(c *gin.Context) {
+
+	var id = c.Param("id")
+
+	inScope, err := h.storageComingInScope(c, id)
+	if err != nil {
+		h.handleError(c, "storage.storage_coming.getById", err)
+		return
+	}
+
+	if !inScope {
+		h.outOfBranchScope(c, "delete storage coming")
+		return
+	}
+
+	err = h.strg.StorageComing().Delete(c.Request.Context(), &models.StorageComingPrimaryKey{Id: id})
+	if err != nil {
+		h.handleError(c, "storage.storage_coming.delete", err)
+		return
+	}
+
+	h.handlerResponse(c, "delete storage coming", http.StatusNoContent, nil)
+}
+
+func (h *Handler) RestoreStorageComing(c *gin.Context) {
+
+	var id = c.Param("id")
+
+	inScope, err := h.storageComingInScope(c, id)
+	if err != nil {
+		h.handleError(c, "storage.storage_coming.getById", err)
+		return
+	}
+
+	if !inScope {
+		h.outOfBranchScope(c, "restore storage coming")
+		return
+	}
+
+	_, err = h.strg.StorageComing().Restore(c.Request.Context(), &models.StorageComingPrimaryKey{Id: id})
+	if err != nil {
+		h.handleError(c, "storage.storage_coming.restore", err)
+		return
+	}
+
+	resp, err := h.strg.StorageComing().GetByID(c.Request.Context(), &models.StorageComingPrimaryKey{Id: id})
+	if err != nil {
+		h.handleError(c, "storage.storage_coming.getById", err)
+		return
+	}
+
+	h.handlerResponse(c, "restore storage coming", http.StatusOK, resp)
+}
+
+func (h *Handler) StartStorageComing(c *gin.Context) {
+	h.transitionStorageComing(c, "start storage coming", models.StorageComingStatusInProcess)
+}
+
+func (h *Handler) ReceiveStorageComing(c *gin.Context) {
+	h.transitionStorageComing(c, "receive storage coming", models.StorageComingStatusReceived)
+}
+
+func (h *Handler) FinishStorageComing(c *gin.Context) {
+	h.transitionStorageComing(c, "finish storage coming", models.StorageComingStatusFinished)
+}
+
+func (h *Handler) CancelStorageComing(c *gin.Context) {
+	h.transitionStorageComing(c, "cancel storage coming", models.StorageComingStatusCancelled)
+}
+
+// transitionStorageComing moves the storage coming of the route to status.
+// The body is optional and may carry a note for the history.
+func (h *Handler) transitionStorageComing(c *gin.Context, path, status string) {
+
+	var transition models.TransitionStorageComing
+
+	err := c.ShouldBindJSON(&transition)
+	if err != nil && !errors.Is(err, io.EOF) {
+		h.handleError(c, path, helper.ValidationError(err))
+		return
+	}
+
+	transition.Id = c.Param("id")
+	transition.Status = status
+
+	version, ok := h.getIfMatchVersion(c, path, transition.Version)
+	if !ok {
+		return
+	}
+
+	transition.Version = version
+
+	inScope, err := h.storageComingInScope(c, transition.Id)
+	if err != nil {
+		h.handleError(c, "storage.storage_coming.getById", err)
+		return
+	}
+
+	if !inScope {
+		h.outOfBranchScope(c, path)
+		return
+	}
+
+	_, err = h.strg.StorageComing().Transition(c.Request.Context(), &transition)
+	if errors.Is(err, storage.ErrVersionConflict) {
+		current, err := h.strg.StorageComing().GetByID(c.Request.Context(), &models.StorageComingPrimaryKey{Id: transition.Id})
+		if err != nil {
+			h.handleError(c, "storage.storage_coming.getById", err)
+			return
+		}
+
+		h.setETag(c, current.Version)
+		h.handlerResponse(c, "storage.storage_coming.transition", http.StatusConflict, current)
+		return
+	} else if err != nil {
+		h.handleError(c, "storage.storage_coming.transition", err)
+		return
+	}
+
+	resp, err := h.strg.StorageComing().GetByID(c.Request.Context(), &models.StorageComingPrimaryKey{Id: transition.Id})
+	if err != nil {
+		h.handleError(c, "storage.storage_coming.getById", err)
+		return
+	}
+
+	h.setETag(c, resp.Version)
+
+	h.handlerResponse(c, path, http.StatusAccepted, resp)
+}
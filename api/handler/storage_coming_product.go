@@ -0,0 +1,263 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"market/api/models"
+	"market/pkg/apperr"
+	"market/pkg/helper"
+	"market/storage"
+)
+
+func (h *Handler) CreateStorageComingProduct(c *gin.Context) {
+
+	var createStorageComingProduct models.CreateStorageComingProduct
+
+	err := c.ShouldBindJSON(&createStorageComingProduct)
+	if err != nil {
+		h.handleError(c, "create storage coming product", helper.ValidationError(err))
+		return
+	}
+
+	if !h.checkSerials(c, "create storage coming product", createStorageComingProduct.ProductId, createStorageComingProduct.Quantity, createStorageComingProduct.Serials) {
+		return
+	}
+
+	inScope, err := h.storageComingInScope(c, createStorageComingProduct.StorageComingId)
+	if err != nil {
+		h.handleError(c, "storage.storage_coming.getById", err)
+		return
+	}
+
+	if !inScope {
+		h.outOfBranchScope(c, "create storage coming product")
+		return
+	}
+
+	id, err := h.strg.StorageComingProduct().Create(c.Request.Context(), &createStorageComingProduct)
+	if err != nil {
+		h.handleError(c, "storage.storage_coming_product.create", err)
+		return
+	}
+
+	resp, err := h.strg.StorageComingProduct().GetByID(c.Request.Context(), &models.StorageComingProductPrimaryKey{Id: id})
+	if err != nil {
+		h.handleError(c, "storage.storage_coming_product.getById", err)
+		return
+	}
+
+	h.handlerResponse(c, "create storage coming product", http.StatusCreated, resp)
+}
+
+func (h *Handler) GetByIdStorageComingProduct(c *gin.Context) {
+
+	var id = c.Param("id")
+
+	includeDeleted, ok := h.getIncludeDeletedQuery(c, "get by id storage coming product")
+	if !ok {
+		return
+	}
+
+	resp, err := h.strg.StorageComingProduct().GetByID(c.Request.Context(), &models.StorageComingProductPrimaryKey{Id: id, IncludeDeleted: includeDeleted})
+	if err != nil {
+		h.handleError(c, "storage.storage_coming_product.getById", err)
+		return
+	}
+
+	inScope, err := h.storageComingInScope(c, resp.StorageComingId)
+	if err != nil {
+		h.handleError(c, "storage.storage_coming.getById", err)
+		return
+	}
+
+	if !inScope {
+		h.outOfBranchScope(c, "get by id storage coming product")
+		return
+	}
+
+	h.setETag(c, resp.Version)
+
+	h.handlerResponse(c, "get by id storage coming product", http.StatusOK, resp)
+}
+
+func (h *Handler) GetListStorageComingProduct(c *gin.Context) {
+
+	offset, err := h.getOffsetQuery(c.Query("offset"))
+	if err != nil {
+		h.handlerResponse(c, "get list storage coming product", http.StatusBadRequest, "invalid offset")
+		return
+	}
+
+	limit, err := h.getLimitQuery(c.Query("limit"))
+	if err != nil {
+		h.handlerResponse(c, "get list storage coming product", http.StatusBadRequest, "invalid limit")
+		return
+	}
+
+	includeDeleted, ok := h.getIncludeDeletedQuery(c, "get list storage coming product")
+	if !ok {
+		return
+	}
+
+	resp, err := h.strg.StorageComingProduct().GetList(c.Request.Context(), &models.StorageComingProductGetListRequest{
+		Offset:          offset,
+		Limit:           limit,
+		Search:          c.Query("search"),
+		StorageComingId: c.Query("storage_coming_id"),
+		BranchIds:       h.branchScope(c),
+		IncludeDeleted:  includeDeleted,
+	})
+	if err != nil {
+		h.handleError(c, "storage.storage_coming_product.getList", err)
+		return
+	}
+
+	h.handlerResponse(c, "get list storage coming product", http.StatusOK, resp)
+}
+
+func (h *Handler) UpdateStorageComingProduct(c *gin.Context) {
+
+	var updateStorageComingProduct models.UpdateStorageComingProduct
+
+	err := c.ShouldBindJSON(&updateStorageComingProduct)
+	if err != nil {
+		h.handleError(c, "update storage coming product", helper.ValidationError(err))
+		return
+	}
+
+	if !h.checkSerials(c, "update storage coming product", updateStorageComingProduct.ProductId, updateStorageComingProduct.Quantity, updateStorageComingProduct.Serials) {
+		return
+	}
+
+	updateStorageComingProduct.Id = c.Param("id")
+
+	version, ok := h.getIfMatchVersion(c, "update storage coming product", updateStorageComingProduct.Version)
+	if !ok {
+		return
+	}
+
+	updateStorageComingProduct.Version = version
+
+	inScope, err := h.storageComingProductInScope(c, updateStorageComingProduct.Id)
+	if err == nil && inScope && updateStorageComingProduct.StorageComingId != "" {
+		inScope, err = h.storageComingInScope(c, updateStorageComingProduct.StorageComingId)
+	}
+	if err != nil {
+		h.handleError(c, "storage.storage_coming_product.getById", err)
+		return
+	}
+
+	if !inScope {
+		h.outOfBranchScope(c, "update storage coming product")
+		return
+	}
+
+	_, err = h.strg.StorageComingProduct().Update(c.Request.Context(), &updateStorageComingProduct)
+	if errors.Is(err, storage.ErrVersionConflict) {
+		current, err := h.strg.StorageComingProduct().GetByID(c.Request.Context(), &models.StorageComingProductPrimaryKey{Id: updateStorageComingProduct.Id})
+		if err != nil {
+			h.handleError(c, "storage.storage_coming_product.getById", err)
+			return
+		}
+
+		h.setETag(c, current.Version)
+		h.handlerResponse(c, "storage.storage_coming_product.update", http.StatusConflict, current)
+		return
+	} else if err != nil {
+		h.handleError(c, "storage.storage_coming_product.update", err)
+		return
+	}
+
+	resp, err := h.strg.StorageComingProduct().GetByID(c.Request.Context(), &models.StorageComingProductPrimaryKey{Id: updateStorageComingProduct.Id})
+	if err != nil {
+		h.handleError(c, "storage.storage_coming_product.getById", err)
+		return
+	}
+
+	h.setETag(c, resp.Version)
+
+	h.handlerResponse(c, "update storage coming product", http.StatusAccepted, resp)
+}
+
+func (h *Handler) DeleteStorageComingProduct(c *gin.Context) {
+
+	var id = c.Param("id")
+
+	inScope, err := h.storageComingProductInScope(c, id)
+	if err != nil {
+		h.handleError(c, "storage.storage_coming_product.getById", err)
+		return
+	}
+
+	if !inScope {
+		h.outOfBranchScope(c, "delete storage coming product")
+		return
+	}
+
+	err = h.strg.StorageComingProduct().Delete(c.Request.Context(), &models.StorageComingProductPrimaryKey{Id: id})
+	if err != nil {
+		h.handleError(c, "storage.storage_coming_product.delete", err)
+		return
+	}
+
+	h.handlerResponse(c, "delete storage coming product", http.StatusNoContent, nil)
+}
+
+func (h *Handler) RestoreStorageComingProduct(c *gin.Context) {
+
+	var id = c.Param("id")
+
+	inScope, err := h.storageComingProductInScope(c, id)
+	if err != nil {
+		h.handleError(c, "storage.storage_coming_product.getById", err)
+		return
+	}
+
+	if !inScope {
+		h.outOfBranchScope(c, "restore storage coming product")
+		return
+	}
+
+	_, err = h.strg.StorageComingProduct().Restore(c.Request.Context(), &models.StorageComingProductPrimaryKey{Id: id})
+	if err != nil {
+		h.handleError(c, "storage.storage_coming_product.restore", err)
+		return
+	}
+
+	resp, err := h.strg.StorageComingProduct().GetByID(c.Request.Context(), &models.StorageComingProductPrimaryKey{Id: id})
+	if err != nil {
+		h.handleError(c, "storage.storage_coming_product.getById", err)
+		return
+	}
+
+	h.handlerResponse(c, "restore storage coming product", http.StatusOK, resp)
+}
+
+// checkSerials runs storage.CheckSerials and answers a wrong serial the way
+// this API always has, as a bad request with a plain message rather than
+// the field list gRPC reports.
+func (h *Handler) checkSerials(c *gin.Context, path, productId string, quantity int32, serials []string) bool {
+
+	err := storage.CheckSerials(c.Request.Context(), h.strg, productId, quantity, serials)
+	if err == nil {
+		return true
+	}
+
+	appErr, ok := apperr.As(err)
+	if !ok || appErr.Kind != apperr.KindValidation || len(appErr.Fields) != 1 {
+		h.handleError(c, path, err)
+		return false
+	}
+
+	message := appErr.Fields[0].Message
+	if appErr.Fields[0].Field != "serials" {
+		message = appErr.Fields[0].Field + " " + message
+	}
+
+	h.handlerResponse(c, path, http.StatusBadRequest, message)
+
+	return false
+}
@@ -0,0 +1,142 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"market/api/models"
+	"market/pkg/helper"
+	"market/pkg/rbac"
+	"market/pkg/security"
+)
+
+func (h *Handler) CreateUser(c *gin.Context) {
+
+	var createUser models.CreateUser
+
+	err := c.ShouldBindJSON(&createUser)
+	if err != nil {
+		h.handleError(c, "create user", helper.ValidationError(err))
+		return
+	}
+
+	if !rbac.ValidRole(createUser.Role) {
+		h.handlerResponse(c, "create user", http.StatusBadRequest, "invalid role, expected one of "+strings.Join(rbac.Roles(), ", "))
+		return
+	}
+
+	createUser.PasswordHash, err = security.HashPassword(createUser.Password)
+	if err != nil {
+		h.handleError(c, "create user", err)
+		return
+	}
+
+	id, err := h.strg.User().Create(c.Request.Context(), &createUser)
+	if err != nil {
+		h.handleError(c, "storage.user.create", err)
+		return
+	}
+
+	resp, err := h.strg.User().GetByID(c.Request.Context(), &models.UserPrimaryKey{Id: id})
+	if err != nil {
+		h.handleError(c, "storage.user.getById", err)
+		return
+	}
+
+	h.handlerResponse(c, "create user", http.StatusCreated, resp)
+}
+
+func (h *Handler) GetByIdUser(c *gin.Context) {
+
+	var id = c.Param("id")
+
+	resp, err := h.strg.User().GetByID(c.Request.Context(), &models.UserPrimaryKey{Id: id})
+	if err != nil {
+		h.handleError(c, "storage.user.getById", err)
+		return
+	}
+
+	h.handlerResponse(c, "get by id user", http.StatusOK, resp)
+}
+
+func (h *Handler) GetListUser(c *gin.Context) {
+
+	offset, err := h.getOffsetQuery(c.Query("offset"))
+	if err != nil {
+		h.handlerResponse(c, "get list user", http.StatusBadRequest, "invalid offset")
+		return
+	}
+
+	limit, err := h.getLimitQuery(c.Query("limit"))
+	if err != nil {
+		h.handlerResponse(c, "get list user", http.StatusBadRequest, "invalid limit")
+		return
+	}
+
+	resp, err := h.strg.User().GetList(c.Request.Context(), &models.UserGetListRequest{
+		Offset: offset,
+		Limit:  limit,
+		Search: c.Query("search"),
+	})
+	if err != nil {
+		h.handleError(c, "storage.user.getList", err)
+		return
+	}
+
+	h.handlerResponse(c, "get list user", http.StatusOK, resp)
+}
+
+func (h *Handler) UpdateUser(c *gin.Context) {
+
+	var updateUser models.UpdateUser
+
+	err := c.ShouldBindJSON(&updateUser)
+	if err != nil {
+		h.handleError(c, "update user", helper.ValidationError(err))
+		return
+	}
+
+	updateUser.Id = c.Param("id")
+
+	if !rbac.ValidRole(updateUser.Role) {
+		h.handlerResponse(c, "update user", http.StatusBadRequest, "invalid role, expected one of "+strings.Join(rbac.Roles(), ", "))
+		return
+	}
+
+	if updateUser.Password != "" {
+		updateUser.PasswordHash, err = security.HashPassword(updateUser.Password)
+		if err != nil {
+			h.handleError(c, "update user", err)
+			return
+		}
+	}
+
+	_, err = h.strg.User().Update(c.Request.Context(), &updateUser)
+	if err != nil {
+		h.handleError(c, "storage.user.update", err)
+		return
+	}
+
+	resp, err := h.strg.User().GetByID(c.Request.Context(), &models.UserPrimaryKey{Id: updateUser.Id})
+	if err != nil {
+		h.handleError(c, "storage.user.getById", err)
+		return
+	}
+
+	h.handlerResponse(c, "update user", http.StatusAccepted, resp)
+}
+
+func (h *Handler) DeleteUser(c *gin.Context) {
+
+	var id = c.Param("id")
+
+	err := h.strg.User().Delete(c.Request.Context(), &models.UserPrimaryKey{Id: id})
+	if err != nil {
+		h.handleError(c, "storage.user.delete", err)
+		return
+	}
+
+	h.handlerResponse(c, "delete user", http.StatusNoContent, nil)
+}
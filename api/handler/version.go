@@ -0,0 +1,40 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// setETag tags the response with the version of the record it carries.
+func (h *Handler) setETag(c *gin.Context, version int) {
+	c.Header("ETag", strconv.Quote(strconv.Itoa(version)))
+}
+
+// getIfMatchVersion returns the version an update is based on. The If-Match
+// header wins over the version sent in the body; "*" or no version at all
+// means the update is not conditional and zero is returned.
+func (h *Handler) getIfMatchVersion(c *gin.Context, path string, version int) (int, bool) {
+
+	var value = strings.TrimSpace(c.GetHeader("If-Match"))
+
+	if value == "" {
+		return version, true
+	}
+
+	if value == "*" {
+		return 0, true
+	}
+
+	value = strings.Trim(strings.TrimPrefix(value, "W/"), `"`)
+
+	version, err := strconv.Atoi(value)
+	if err != nil || version <= 0 {
+		h.handlerResponse(c, path, http.StatusBadRequest, "invalid If-Match header, expected a version")
+		return 0, false
+	}
+
+	return version, true
+}
@@ -0,0 +1,58 @@
+package models
+
+type ApiKeyPrimaryKey struct {
+	Id string `json:"id"`
+}
+
+type ApiKeyHash struct {
+	KeyHash string `json:"-"`
+}
+
+type CreateApiKey struct {
+	Name      string   `json:"name" binding:"required,max=100"`
+	Scopes    []string `json:"scopes" binding:"required,min=1,dive,max=30"`
+	RateLimit int      `json:"rate_limit" binding:"min=0"`
+	CreatedBy string   `json:"-"`
+	Prefix    string   `json:"-"`
+	KeyHash   string   `json:"-"`
+}
+
+// ApiKey is a key of a machine client. Only its Prefix is kept in clear so
+// admins can tell keys apart, RateLimit is in requests per minute with 0
+// meaning the configured default.
+type ApiKey struct {
+	Id         string   `json:"id"`
+	Name       string   `json:"name"`
+	Prefix     string   `json:"prefix"`
+	Scopes     []string `json:"scopes"`
+	RateLimit  int      `json:"rate_limit"`
+	CreatedBy  string   `json:"created_by"`
+	LastUsedAt string   `json:"last_used_at"`
+	RevokedAt  string   `json:"revoked_at"`
+	CreatedAt  string   `json:"created_at"`
+	UpdatedAt  string   `json:"updated_at"`
+}
+
+// ApiKeySecret is returned once, when a key is issued or rotated.
+type ApiKeySecret struct {
+	*ApiKey
+	Key string `json:"key"`
+}
+
+type RotateApiKey struct {
+	Id      string `json:"id"`
+	Prefix  string `json:"-"`
+	KeyHash string `json:"-"`
+}
+
+type ApiKeyGetListRequest struct {
+	Offset  int    `json:"offset"`
+	Limit   int    `json:"limit"`
+	Search  string `json:"search"`
+	Revoked bool   `json:"revoked"`
+}
+
+type ApiKeyGetListResponse struct {
+	Count   int       `json:"count"`
+	ApiKeys []*ApiKey `json:"api_keys"`
+}
@@ -0,0 +1,34 @@
+package models
+
+import "encoding/json"
+
+// AuditLog is one recorded change. Before and After hold the changed
+// columns only, Before is null for creates and After for deletes.
+type AuditLog struct {
+	Id        string          `json:"id"`
+	ActorType string          `json:"actor_type"`
+	ActorId   string          `json:"actor_id"`
+	Entity    string          `json:"entity"`
+	EntityId  string          `json:"entity_id"`
+	Action    string          `json:"action"`
+	Before    json.RawMessage `json:"before"`
+	After     json.RawMessage `json:"after"`
+	RequestId string          `json:"request_id"`
+	CreatedAt string          `json:"created_at"`
+}
+
+type AuditLogGetListRequest struct {
+	Offset   int    `json:"offset"`
+	Limit    int    `json:"limit"`
+	Entity   string `json:"entity"`
+	EntityId string `json:"entity_id"`
+	ActorId  string `json:"actor_id"`
+	Action   string `json:"action"`
+	From     string `json:"from"`
+	To       string `json:"to"`
+}
+
+type AuditLogGetListResponse struct {
+	Count     int         `json:"count"`
+	AuditLogs []*AuditLog `json:"audit_logs"`
+}
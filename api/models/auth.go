@@ -0,0 +1,42 @@
+package models
+
+type Login struct {
+	Login    string `json:"login"`
+	Password string `json:"password"`
+}
+
+type RefreshToken struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+type Token struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int    `json:"expires_in"`
+}
+
+// Session is one refresh token. Tokens issued by rotation share the FamilyId
+// of the login they descend from.
+type Session struct {
+	Id        string `json:"id"`
+	UserId    string `json:"user_id"`
+	FamilyId  string `json:"family_id"`
+	TokenHash string `json:"-"`
+	ExpiresAt string `json:"expires_at"`
+}
+
+type CreateSession struct {
+	UserId    string `json:"user_id"`
+	FamilyId  string `json:"family_id"`
+	TokenHash string `json:"-"`
+	TTL       int    `json:"ttl"`
+}
+
+// RotateSession exchanges the refresh token hashed as TokenHash for
+// NewTokenHash in the same family.
+type RotateSession struct {
+	TokenHash    string `json:"-"`
+	NewTokenHash string `json:"-"`
+	TTL          int    `json:"ttl"`
+}
@@ -1,13 +1,14 @@
 package models
 
 type BranchPrimaryKey struct {
-	Id string `json:"id"`
+	Id             string `json:"id"`
+	IncludeDeleted bool   `json:"include_deleted"`
 }
 
 type CreateBranch struct {
-	Name        string `json:"name"`
-	Address     string `json:"address"`
-	PhoneNumber string `json:"phone_number"`
+	Name        string `json:"name" binding:"required,max=45"`
+	Address     string `json:"address" binding:"max=55"`
+	PhoneNumber string `json:"phone_number" binding:"omitempty,phone"`
 }
 
 type Branch struct {
@@ -15,21 +16,26 @@ type Branch struct {
 	Name        string `json:"name"`
 	Address     string `json:"address"`
 	PhoneNumber string `json:"phone_number"`
+	Version     int    `json:"version"`
 	CreatedAt   string `json:"created_at"`
 	UpdatedAt   string `json:"updated_at"`
+	DeletedAt   string `json:"deleted_at,omitempty"`
 }
 
 type UpdateBranch struct {
 	Id          string `json:"id"`
-	Name        string `json:"name"`
-	Address     string `json:"address"`
-	PhoneNumber string `json:"phone_number"`
+	Name        string `json:"name" binding:"required,max=45"`
+	Address     string `json:"address" binding:"max=55"`
+	PhoneNumber string `json:"phone_number" binding:"omitempty,phone"`
+	Version     int    `json:"version" binding:"min=0"`
 }
 
 type BranchGetListRequest struct {
-	Offset int    `json:"offset"`
-	Limit  int    `json:"limit"`
-	Search string `json:"search"`
+	Offset         int      `json:"offset"`
+	Limit          int      `json:"limit"`
+	Search         string   `json:"search"`
+	BranchIds      []string `json:"-"`
+	IncludeDeleted bool     `json:"include_deleted"`
 }
 
 type BranchGetListResponse struct {
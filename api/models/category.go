@@ -1,32 +1,37 @@
 package models
 
 type CategoryPrimaryKey struct {
-	Id string `json:"id"`
+	Id             string `json:"id"`
+	IncludeDeleted bool   `json:"include_deleted"`
 }
 
 type CreateCategory struct {
-	Title    string `json:"title"`
-	ParentID string `json:"parent_id"`
+	Title    string `json:"title" binding:"required,max=50"`
+	ParentID string `json:"parent_id" binding:"omitempty,uuid"`
 }
 
 type Category struct {
 	Id        string `json:"id"`
 	Title     string `json:"title"`
 	ParentID  string `json:"parent_id"`
+	Version   int    `json:"version"`
 	CreatedAt string `json:"created_at"`
 	UpdatedAt string `json:"updated_at"`
+	DeletedAt string `json:"deleted_at,omitempty"`
 }
 
 type UpdateCategory struct {
 	Id       string `json:"id"`
-	Title    string `json:"title"`
-	ParentID string `json:"parent_id"`
+	Title    string `json:"title" binding:"required,max=50"`
+	ParentID string `json:"parent_id" binding:"omitempty,uuid"`
+	Version  int    `json:"version" binding:"min=0"`
 }
 
 type CategoryGetListRequest struct {
-	Offset int    `json:"offset"`
-	Limit  int    `json:"limit"`
-	Search string `json:"search"`
+	Offset         int    `json:"offset"`
+	Limit          int    `json:"limit"`
+	Search         string `json:"search"`
+	IncludeDeleted bool   `json:"include_deleted"`
 }
 
 type CategoryGetListResponse struct {
@@ -0,0 +1,35 @@
+package models
+
+// IdempotencyKey is a request sent with an Idempotency-Key header and the
+// response it got. ResponseCode is zero while the request is in progress.
+type IdempotencyKey struct {
+	Key          string `json:"key"`
+	ActorId      string `json:"actor_id"`
+	RequestHash  string `json:"request_hash"`
+	ResponseCode int    `json:"response_code"`
+	ResponseType string `json:"response_type"`
+	ResponseBody []byte `json:"response_body"`
+	CreatedAt    string `json:"created_at"`
+	ExpiresAt    string `json:"expires_at"`
+}
+
+type IdempotencyKeyPrimaryKey struct {
+	Key     string `json:"key"`
+	ActorId string `json:"actor_id"`
+}
+
+// CreateIdempotencyKey reserves a key for TTL hours.
+type CreateIdempotencyKey struct {
+	Key         string `json:"key"`
+	ActorId     string `json:"actor_id"`
+	RequestHash string `json:"request_hash"`
+	TTL         int    `json:"ttl"`
+}
+
+type SaveIdempotencyKey struct {
+	Key          string `json:"key"`
+	ActorId      string `json:"actor_id"`
+	ResponseCode int    `json:"response_code"`
+	ResponseType string `json:"response_type"`
+	ResponseBody []byte `json:"response_body"`
+}
@@ -0,0 +1,25 @@
+package models
+
+type CreateStorageComingWithProducts struct {
+	StorageComing *CreateStorageComing          `json:"storage_coming"`
+	Products      []*CreateStorageComingProduct `json:"products"`
+}
+
+type InvoiceImportLine struct {
+	Row             int    `json:"row"`
+	Barcode         string `json:"barcode" binding:"max=48"`
+	Name            string `json:"name" binding:"required"`
+	Quantity        int32  `json:"quantity" binding:"gt=0"`
+	Price           int32  `json:"price" binding:"min=0"`
+	ProductId       string `json:"product_id" binding:"omitempty,uuid"`
+	Known           bool   `json:"known"`
+	CurrentPrice    int32  `json:"current_price"`
+	PriceDifference int32  `json:"price_difference"`
+}
+
+type InvoiceImportResponse struct {
+	StorageComing   *StorageComing       `json:"storage_coming"`
+	Lines           []*InvoiceImportLine `json:"lines"`
+	Unknown         int                  `json:"unknown"`
+	PriceMismatches int                  `json:"price_mismatches"`
+}
@@ -0,0 +1,9 @@
+package models
+
+type LabelRequest struct {
+	Format          string   `json:"format" binding:"omitempty,oneof=pdf zpl"`
+	ProductIds      []string `json:"product_ids" binding:"dive,uuid"`
+	CategoryId      string   `json:"category_id" binding:"omitempty,uuid"`
+	StorageComingId string   `json:"storage_coming_id" binding:"omitempty,uuid"`
+	Copies          int      `json:"copies" binding:"gte=0,lte=1000"`
+}
@@ -1,6 +1,7 @@
 package models
 
 type PatchRequest struct {
-	ID     string `json:"id"`
-	Fields map[string]interface{}
+	ID      string `json:"id"`
+	Version int    `json:"version"`
+	Fields  map[string]interface{}
 }
@@ -1,38 +1,65 @@
 package models
 
 type ProductPrimaryKey struct {
-	Id string `json:"id"`
+	Id             string `json:"id"`
+	IncludeDeleted bool   `json:"include_deleted"`
 }
 
 type CreateProduct struct {
-	Name       string `json:"name"`
-	Barcode    string `json:"bracode"`
-	Price      int32  `json:"price"`
-	CategoryId string `json:"category_id"`
+	Name       string                  `json:"name" binding:"required,max=55"`
+	Barcode    string                  `json:"bracode" binding:"barcode"`
+	Price      int32                   `json:"price" binding:"min=0"`
+	CategoryId string                  `json:"category_id" binding:"omitempty,uuid"`
+	ParentId   string                  `json:"parent_id" binding:"omitempty,uuid"`
+	Size       string                  `json:"size" binding:"max=20"`
+	Color      string                  `json:"color" binding:"max=30"`
+	Volume     string                  `json:"volume" binding:"max=20"`
+	Serialized bool                    `json:"serialized"`
+	Barcodes   []*CreateProductBarcode `json:"barcodes" binding:"dive"`
 }
 
 type Product struct {
-	Id         string `json:"id"`
-	Name       string `json:"name"`
-	Barcode    string `json:"bracode"`
-	Price      int32  `json:"price"`
-	CategoryId string `json:"category_id"`
-	CreatedAt  string `json:"created_at"`
-	UpdatedAt  string `json:"updated_at"`
+	Id         string            `json:"id"`
+	Name       string            `json:"name"`
+	Barcode    string            `json:"bracode"`
+	Price      int32             `json:"price"`
+	CategoryId string            `json:"category_id"`
+	ParentId   string            `json:"parent_id"`
+	Size       string            `json:"size"`
+	Color      string            `json:"color"`
+	Volume     string            `json:"volume"`
+	Serialized bool              `json:"serialized"`
+	Variants   []*Product        `json:"variants,omitempty"`
+	Barcodes   []*ProductBarcode `json:"barcodes,omitempty"`
+	Images     []*ProductImage   `json:"images"`
+	Version    int               `json:"version"`
+	CreatedAt  string            `json:"created_at"`
+	UpdatedAt  string            `json:"updated_at"`
+	DeletedAt  string            `json:"deleted_at,omitempty"`
 }
 
 type UpdateProduct struct {
 	Id         string `json:"id"`
-	Name       string `json:"name"`
-	Barcode    string `json:"bracode"`
-	Price      int32  `json:"price"`
-	CategoryId string `json:"category_id"`
+	Name       string `json:"name" binding:"required,max=55"`
+	Barcode    string `json:"bracode" binding:"barcode"`
+	Price      int32  `json:"price" binding:"min=0"`
+	CategoryId string `json:"category_id" binding:"omitempty,uuid"`
+	ParentId   string `json:"parent_id" binding:"omitempty,uuid"`
+	Size       string `json:"size" binding:"max=20"`
+	Color      string `json:"color" binding:"max=30"`
+	Volume     string `json:"volume" binding:"max=20"`
+	Serialized bool   `json:"serialized"`
+	Version    int    `json:"version" binding:"min=0"`
 }
 
 type ProductGetListRequest struct {
-	Offset int    `json:"offset"`
-	Limit  int    `json:"limit"`
-	Search string `json:"search"`
+	Offset         int    `json:"offset"`
+	Limit          int    `json:"limit"`
+	Search         string `json:"search"`
+	CategoryId     string `json:"category_id"`
+	ParentId       string `json:"parent_id"`
+	GroupVariants  bool   `json:"group_variants"`
+	IncludeDeleted bool   `json:"include_deleted"`
 }
 
 type ProductGetListResponse struct {
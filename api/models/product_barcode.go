@@ -0,0 +1,60 @@
+package models
+
+const (
+	ProductBarcodeTypeManufacturer = "manufacturer"
+	ProductBarcodeTypeInternal     = "internal"
+	ProductBarcodeTypePack         = "pack"
+)
+
+type ProductBarcodePrimaryKey struct {
+	Id string `json:"id"`
+}
+
+type CreateProductBarcode struct {
+	ProductId string `json:"product_id" binding:"omitempty,uuid"`
+	Barcode   string `json:"barcode" binding:"barcode,max=48"`
+	Type      string `json:"type" binding:"omitempty,oneof=manufacturer internal pack"`
+	Quantity  int32  `json:"quantity" binding:"min=0"`
+}
+
+type ProductBarcode struct {
+	Id        string `json:"id"`
+	ProductId string `json:"product_id"`
+	Barcode   string `json:"barcode"`
+	Type      string `json:"type"`
+	Quantity  int32  `json:"quantity"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+type ProductBarcodeGetListRequest struct {
+	ProductId string `json:"product_id"`
+}
+
+type ProductBarcodeGetListResponse struct {
+	Count    int               `json:"count"`
+	Barcodes []*ProductBarcode `json:"barcodes"`
+}
+
+type ProductBarcodeLookup struct {
+	Barcode string `json:"barcode"`
+}
+
+type ProductBarcodeLookupResponse struct {
+	Product  *Product `json:"product"`
+	Type     string   `json:"type"`
+	Quantity int32    `json:"quantity"`
+	Weight   int32    `json:"weight,omitempty"`
+	Price    int32    `json:"price,omitempty"`
+}
+
+type GenerateBarcode struct {
+	Weighed      bool   `json:"weighed"`
+	PrefixFrom   int    `json:"-"`
+	PrefixTo     int    `json:"-"`
+	WeightPrefix string `json:"-"`
+}
+
+type GenerateBarcodeResponse struct {
+	Barcode string `json:"barcode"`
+}
@@ -0,0 +1,41 @@
+package models
+
+type ProductImagePrimaryKey struct {
+	Id string `json:"id"`
+}
+
+type CreateProductImage struct {
+	ProductId    string `json:"product_id"`
+	FileKey      string `json:"file_key"`
+	ThumbnailKey string `json:"thumbnail_key"`
+	ContentType  string `json:"content_type"`
+	Size         int64  `json:"size"`
+}
+
+type ProductImage struct {
+	Id           string `json:"id"`
+	ProductId    string `json:"product_id"`
+	FileKey      string `json:"-"`
+	ThumbnailKey string `json:"-"`
+	ContentType  string `json:"content_type"`
+	Size         int64  `json:"size"`
+	Position     int32  `json:"position"`
+	Url          string `json:"url"`
+	ThumbnailUrl string `json:"thumbnail_url"`
+	CreatedAt    string `json:"created_at"`
+	UpdatedAt    string `json:"updated_at"`
+}
+
+type ProductImageGetListRequest struct {
+	ProductIds []string `json:"product_ids"`
+}
+
+type ProductImageGetListResponse struct {
+	Count  int             `json:"count"`
+	Images []*ProductImage `json:"images"`
+}
+
+type ProductImageOrder struct {
+	ProductId string   `json:"product_id"`
+	ImageIds  []string `json:"image_ids"`
+}
@@ -0,0 +1,29 @@
+package models
+
+type ProductImportRow struct {
+	Row          int      `json:"row"`
+	Name         string   `json:"name" binding:"required,max=55"`
+	Barcode      string   `json:"barcode" binding:"barcode"`
+	Price        int32    `json:"price" binding:"min=0"`
+	CategoryPath []string `json:"category_path" binding:"dive,required,max=50"`
+}
+
+type ProductImport struct {
+	DryRun bool                `json:"dry_run"`
+	Rows   []*ProductImportRow `json:"rows"`
+}
+
+type ProductImportRowError struct {
+	Row     int    `json:"row"`
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+type ProductImportResponse struct {
+	DryRun            bool                     `json:"dry_run"`
+	Total             int                      `json:"total"`
+	Created           int                      `json:"created"`
+	Updated           int                      `json:"updated"`
+	CreatedCategories int                      `json:"created_categories"`
+	Errors            []*ProductImportRowError `json:"errors"`
+}
@@ -0,0 +1,74 @@
+package models
+
+type RemainingPrimaryKey struct {
+	Id string `json:"id"`
+}
+
+type Remaining struct {
+	Id         string `json:"id"`
+	BranchId   string `json:"branch_id"`
+	CategoryId string `json:"category_id"`
+	ProductId  string `json:"product_id"`
+	Name       string `json:"name"`
+	Price      int32  `json:"price"`
+	Barcode    string `json:"barcode"`
+	Count      int32  `json:"count"`
+	TotalPrice int32  `json:"total_price"`
+	CreatedAt  string `json:"created_at"`
+	UpdatedAt  string `json:"updated_at"`
+}
+
+type RemainingGetListRequest struct {
+	Offset     int      `json:"offset"`
+	Limit      int      `json:"limit"`
+	Search     string   `json:"search"`
+	BranchId   string   `json:"branch_id"`
+	CategoryId string   `json:"category_id"`
+	BranchIds  []string `json:"-"`
+}
+
+type RemainingGetListResponse struct {
+	Count      int          `json:"count"`
+	Remainings []*Remaining `json:"remainings"`
+}
+
+type RemainingBatch struct {
+	Id              string `json:"id"`
+	BranchId        string `json:"branch_id"`
+	ProductId       string `json:"product_id"`
+	IncomeProductId string `json:"income_product_id"`
+	Batch           string `json:"batch"`
+	ExpiryDate      string `json:"expiry_date"`
+	Count           int32  `json:"count"`
+	CreatedAt       string `json:"created_at"`
+	UpdatedAt       string `json:"updated_at"`
+}
+
+type RemainingBatchGetListRequest struct {
+	RemainingId string `json:"remaining_id"`
+}
+
+type RemainingBatchGetListResponse struct {
+	Count   int               `json:"count"`
+	Batches []*RemainingBatch `json:"batches"`
+}
+
+// ConsumeRemaining takes Quantity units of a product out of a branch stock,
+// from the batches that expire first.
+type ConsumeRemaining struct {
+	BranchId  string `json:"branch_id" binding:"required,uuid"`
+	ProductId string `json:"product_id" binding:"required,uuid"`
+	Quantity  int32  `json:"quantity" binding:"gt=0"`
+}
+
+type ConsumedBatch struct {
+	BatchId    string `json:"batch_id"`
+	Batch      string `json:"batch"`
+	ExpiryDate string `json:"expiry_date"`
+	Quantity   int32  `json:"quantity"`
+}
+
+type ConsumeRemainingResponse struct {
+	Remaining *Remaining       `json:"remaining"`
+	Batches   []*ConsumedBatch `json:"batches"`
+}
@@ -0,0 +1,139 @@
+package models
+
+const (
+	CostingFIFO         = "fifo"
+	CostingAverage      = "average"
+	CostingLastPurchase = "last"
+)
+
+type InventoryValuationRequest struct {
+	BranchId   string   `json:"branch_id"`
+	CategoryId string   `json:"category_id"`
+	Method     string   `json:"method"`
+	AsOf       string   `json:"as_of"`
+	BranchIds  []string `json:"-"`
+}
+
+type InventoryValuation struct {
+	Method   string                      `json:"method"`
+	AsOf     string                      `json:"as_of"`
+	Count    int64                       `json:"count"`
+	Value    int64                       `json:"value"`
+	Branches []*BranchInventoryValuation `json:"branches"`
+}
+
+type BranchInventoryValuation struct {
+	BranchId   string                        `json:"branch_id"`
+	BranchName string                        `json:"branch_name"`
+	Count      int64                         `json:"count"`
+	Value      int64                         `json:"value"`
+	Categories []*CategoryInventoryValuation `json:"categories"`
+}
+
+type CategoryInventoryValuation struct {
+	CategoryId    string `json:"category_id"`
+	CategoryTitle string `json:"category_title"`
+	Count         int64  `json:"count"`
+	Value         int64  `json:"value"`
+}
+
+const (
+	GoodsReceiptGroupBranch   = "branch"
+	GoodsReceiptGroupCategory = "category"
+	GoodsReceiptGroupProduct  = "product"
+	GoodsReceiptGroupDay      = "day"
+	GoodsReceiptGroupWeek     = "week"
+	GoodsReceiptGroupMonth    = "month"
+	GoodsReceiptGroupReceipt  = "receipt"
+)
+
+type GoodsReceiptReportRequest struct {
+	Offset     int      `json:"offset"`
+	Limit      int      `json:"limit"`
+	GroupBy    string   `json:"group_by"`
+	From       string   `json:"from"`
+	To         string   `json:"to"`
+	Status     string   `json:"status"`
+	BranchId   string   `json:"branch_id"`
+	CategoryId string   `json:"category_id"`
+	ProductId  string   `json:"product_id"`
+	BranchIds  []string `json:"-"`
+}
+
+type GoodsReceiptReport struct {
+	GroupBy  string                   `json:"group_by"`
+	Count    int                      `json:"count"`
+	Receipts int64                    `json:"receipts"`
+	Lines    int64                    `json:"lines"`
+	Quantity int64                    `json:"quantity"`
+	Value    int64                    `json:"value"`
+	Rows     []*GoodsReceiptReportRow `json:"rows"`
+}
+
+// GoodsReceiptReportRow is one group of the report. Key is the id of the
+// branch, category, product or receipt, or the first day of the period, and
+// can be passed back as a filter to drill down.
+type GoodsReceiptReportRow struct {
+	Key      string `json:"key"`
+	Title    string `json:"title"`
+	Receipts int64  `json:"receipts"`
+	Lines    int64  `json:"lines"`
+	Quantity int64  `json:"quantity"`
+	Value    int64  `json:"value"`
+}
+
+type ReorderReportRequest struct {
+	BranchId   string   `json:"branch_id"`
+	WindowDays int      `json:"window_days"`
+	LeadDays   int      `json:"lead_days"`
+	All        bool     `json:"all"`
+	BranchIds  []string `json:"-"`
+}
+
+type ReorderReport struct {
+	Count       int                  `json:"count"`
+	Suggestions []*ReorderSuggestion `json:"suggestions"`
+}
+
+// ReorderSuggestion is a product whose stock at a branch is below its
+// minimum level. Velocity is the average consumption per day over the
+// report window, Quantity is what to order to be back at the maximum level
+// when the delivery arrives.
+type ReorderSuggestion struct {
+	BranchId   string  `json:"branch_id"`
+	BranchName string  `json:"branch_name"`
+	ProductId  string  `json:"product_id"`
+	Name       string  `json:"name"`
+	Barcode    string  `json:"barcode"`
+	Count      int32   `json:"count"`
+	MinCount   int32   `json:"min_count"`
+	MaxCount   int32   `json:"max_count"`
+	Velocity   float64 `json:"velocity"`
+	DaysLeft   float64 `json:"days_left"`
+	Quantity   int32   `json:"quantity"`
+}
+
+type ExpiringReportRequest struct {
+	BranchId  string   `json:"branch_id"`
+	Days      int      `json:"days"`
+	BranchIds []string `json:"-"`
+}
+
+type ExpiringReport struct {
+	Count   int              `json:"count"`
+	Batches []*ExpiringBatch `json:"batches"`
+}
+
+// ExpiringBatch is stock of a batch expiring within the requested days,
+// DaysLeft is negative for batches already expired.
+type ExpiringBatch struct {
+	BranchId   string `json:"branch_id"`
+	BranchName string `json:"branch_name"`
+	ProductId  string `json:"product_id"`
+	Name       string `json:"name"`
+	Barcode    string `json:"barcode"`
+	Batch      string `json:"batch"`
+	ExpiryDate string `json:"expiry_date"`
+	DaysLeft   int32  `json:"days_left"`
+	Count      int32  `json:"count"`
+}
@@ -0,0 +1,64 @@
+package models
+
+const (
+	SerialNumberStatusPending = "pending"
+	SerialNumberStatusInStock = "in_stock"
+	SerialNumberStatusSold    = "sold"
+
+	SerialNumberActionReceived = "received"
+	SerialNumberActionTransfer = "transferred"
+	SerialNumberActionSell     = "sold"
+	SerialNumberActionReturn   = "returned"
+	SerialNumberActionCancel   = "cancelled"
+)
+
+type SerialNumberPrimaryKey struct {
+	Id string `json:"id"`
+}
+
+// SerialNumber is one unit of a serialized product. It is pending while the
+// receipt it came with is not finished, then in stock at BranchId until sold.
+type SerialNumber struct {
+	Id              string               `json:"id"`
+	ProductId       string               `json:"product_id"`
+	Serial          string               `json:"serial"`
+	Status          string               `json:"status"`
+	BranchId        string               `json:"branch_id"`
+	IncomeProductId string               `json:"income_product_id"`
+	History         []*SerialNumberEvent `json:"history,omitempty"`
+	CreatedAt       string               `json:"created_at"`
+	UpdatedAt       string               `json:"updated_at"`
+}
+
+type SerialNumberEvent struct {
+	Id        string `json:"id"`
+	Action    string `json:"action"`
+	Status    string `json:"status"`
+	BranchId  string `json:"branch_id"`
+	Note      string `json:"note"`
+	CreatedAt string `json:"created_at"`
+}
+
+type SerialNumberGetListRequest struct {
+	Offset    int      `json:"offset"`
+	Limit     int      `json:"limit"`
+	Serial    string   `json:"serial"`
+	ProductId string   `json:"product_id"`
+	BranchId  string   `json:"branch_id"`
+	Status    string   `json:"status"`
+	BranchIds []string `json:"-"`
+}
+
+type SerialNumberGetListResponse struct {
+	Count         int             `json:"count"`
+	SerialNumbers []*SerialNumber `json:"serial_numbers"`
+}
+
+// MoveSerialNumber transfers, sells or returns a unit. BranchId is the
+// destination of a transfer or the branch a unit is returned to.
+type MoveSerialNumber struct {
+	Id       string `json:"id"`
+	Action   string `json:"action" binding:"required"`
+	BranchId string `json:"branch_id" binding:"omitempty,uuid"`
+	Note     string `json:"note" binding:"max=255"`
+}
@@ -0,0 +1,35 @@
+package models
+
+type StockLevelPrimaryKey struct {
+	Id string `json:"id"`
+}
+
+type CreateStockLevel struct {
+	BranchId  string `json:"branch_id" binding:"required,uuid"`
+	ProductId string `json:"product_id" binding:"required,uuid"`
+	MinCount  int32  `json:"min_count" binding:"min=0"`
+	MaxCount  int32  `json:"max_count" binding:"min=0"`
+}
+
+type StockLevel struct {
+	Id        string `json:"id"`
+	BranchId  string `json:"branch_id"`
+	ProductId string `json:"product_id"`
+	MinCount  int32  `json:"min_count"`
+	MaxCount  int32  `json:"max_count"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+type StockLevelGetListRequest struct {
+	Offset    int      `json:"offset"`
+	Limit     int      `json:"limit"`
+	BranchId  string   `json:"branch_id"`
+	ProductId string   `json:"product_id"`
+	BranchIds []string `json:"-"`
+}
+
+type StockLevelGetListResponse struct {
+	Count       int           `json:"count"`
+	StockLevels []*StockLevel `json:"stock_levels"`
+}
@@ -1,12 +1,38 @@
 package models
 
+const (
+	StorageComingStatusDraft     = "draft"
+	StorageComingStatusInProcess = "in process"
+	StorageComingStatusReceived  = "received"
+	StorageComingStatusFinished  = "finished"
+	StorageComingStatusCancelled = "cancelled"
+)
+
+// StorageComingTransitions lists the statuses a receipt may go to from each
+// status. Finishing posts its lines to stock, cancelling a finished receipt
+// takes that stock back out; cancelled is final.
+var StorageComingTransitions = map[string][]string{
+	StorageComingStatusDraft:     {StorageComingStatusInProcess, StorageComingStatusCancelled},
+	StorageComingStatusInProcess: {StorageComingStatusReceived, StorageComingStatusFinished, StorageComingStatusCancelled},
+	StorageComingStatusReceived:  {StorageComingStatusInProcess, StorageComingStatusFinished, StorageComingStatusCancelled},
+	StorageComingStatusFinished:  {StorageComingStatusCancelled},
+	StorageComingStatusCancelled: {},
+}
+
+// StorageComingEditable reports whether a receipt and its lines may still be
+// changed in status.
+func StorageComingEditable(status string) bool {
+	return status != StorageComingStatusFinished && status != StorageComingStatusCancelled
+}
+
 type StorageComingPrimaryKey struct {
-	Id string `json:"id"`
+	Id             string `json:"id"`
+	IncludeDeleted bool   `json:"include_deleted"`
 }
 
 type CreateStorageComing struct {
-	ComingId string `json:"coming_id"`
-	BranchId string `json:"branch_id"`
+	ComingId string `json:"coming_id" binding:"required"`
+	BranchId string `json:"branch_id" binding:"omitempty,uuid"`
 }
 
 type StorageComing struct {
@@ -15,21 +41,48 @@ type StorageComing struct {
 	BranchId  string `json:"branch_id"`
 	Status    string `json:"status"`
 	DateTime  string `json:"date_time"`
+	Version   int    `json:"version"`
 	CreatedAt string `json:"created_at"`
 	UpdatedAt string `json:"updated_at"`
+	DeletedAt string `json:"deleted_at,omitempty"`
+
+	History []*StorageComingTransition `json:"history,omitempty"`
+}
+
+// StorageComingTransition is one status change of a receipt. FromStatus is
+// empty for the status it was created with.
+type StorageComingTransition struct {
+	Id         string `json:"id"`
+	FromStatus string `json:"from_status"`
+	ToStatus   string `json:"to_status"`
+	ActorType  string `json:"actor_type"`
+	ActorId    string `json:"actor_id"`
+	Note       string `json:"note"`
+	CreatedAt  string `json:"created_at"`
+}
+
+// TransitionStorageComing moves a receipt to Status.
+type TransitionStorageComing struct {
+	Id      string `json:"id"`
+	Status  string `json:"status"`
+	Note    string `json:"note" binding:"max=255"`
+	Version int    `json:"version" binding:"min=0"`
 }
 
 type UpdateStorageComing struct {
 	Id       string `json:"id"`
-	ComingId string `json:"coming_id"`
-	BranchId string `json:"branch_id"`
-	Status   string `json:"status"`
+	ComingId string `json:"coming_id" binding:"required"`
+	BranchId string `json:"branch_id" binding:"omitempty,uuid"`
+	Status   string `json:"status" binding:"max=20"`
+	Version  int    `json:"version" binding:"min=0"`
 }
 
 type StorageComingGetListRequest struct {
-	Offset int    `json:"offset"`
-	Limit  int    `json:"limit"`
-	Search string `json:"search"`
+	Offset         int      `json:"offset"`
+	Limit          int      `json:"limit"`
+	Search         string   `json:"search"`
+	BranchIds      []string `json:"-"`
+	IncludeDeleted bool     `json:"include_deleted"`
 }
 
 type StorageComingGetListResponse struct {
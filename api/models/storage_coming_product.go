@@ -1,42 +1,64 @@
 package models
 
 type StorageComingProductPrimaryKey struct {
-	Id string `json:"id"`
+	Id             string `json:"id"`
+	IncludeDeleted bool   `json:"include_deleted"`
 }
 
 type CreateStorageComingProduct struct {
-	Name            string `json:"name"`
-	Quantity        int32  `json:"status"`
-	Price           int32  `json:"date_time"`
-	CategoryId      string `json:"category_id"`
-	StorageComingId string `json:"storage_coming_id"`
+	Name            string   `json:"name" binding:"required"`
+	Quantity        int32    `json:"status" binding:"min=0"`
+	Price           int32    `json:"date_time" binding:"min=0"`
+	CategoryId      string   `json:"category_id" binding:"omitempty,uuid"`
+	ProductId       string   `json:"product_id" binding:"omitempty,uuid"`
+	Barcode         string   `json:"barcode" binding:"max=48"`
+	Batch           string   `json:"batch" binding:"max=50"`
+	ExpiryDate      string   `json:"expiry_date" binding:"omitempty,datetime=2006-01-02"`
+	Serials         []string `json:"serials" binding:"dive,required,max=64"`
+	StorageComingId string   `json:"storage_coming_id" binding:"omitempty,uuid"`
 }
 
 type StorageComingProduct struct {
-	Id              string `json:"id"`
-	Name            string `json:"name"`
-	Quantity        int32  `json:"status"`
-	Price           int32  `json:"date_time"`
-	TotalPrice      int32  `json:"total_price"`
-	CategoryId      string `json:"category_id"`
-	StorageComingId string `json:"storage_coming_id"`
-	CreatedAt       string `json:"created_at"`
-	UpdatedAt       string `json:"updated_at"`
+	Id              string   `json:"id"`
+	Name            string   `json:"name"`
+	Quantity        int32    `json:"status"`
+	Price           int32    `json:"date_time"`
+	TotalPrice      int32    `json:"total_price"`
+	CategoryId      string   `json:"category_id"`
+	ProductId       string   `json:"product_id"`
+	Barcode         string   `json:"barcode"`
+	Batch           string   `json:"batch"`
+	ExpiryDate      string   `json:"expiry_date"`
+	Serials         []string `json:"serials,omitempty"`
+	StorageComingId string   `json:"storage_coming_id"`
+	Version         int      `json:"version"`
+	CreatedAt       string   `json:"created_at"`
+	UpdatedAt       string   `json:"updated_at"`
+	DeletedAt       string   `json:"deleted_at,omitempty"`
 }
 
 type UpdateStorageComingProduct struct {
-	Id              string `json:"id"`
-	Name            string `json:"name"`
-	Quantity        int32  `json:"status"`
-	Price           int32  `json:"date_time"`
-	CategoryId      string `json:"category_id"`
-	StorageComingId string `json:"storage_coming_id"`
+	Id              string   `json:"id"`
+	Name            string   `json:"name" binding:"required"`
+	Quantity        int32    `json:"status" binding:"min=0"`
+	Price           int32    `json:"date_time" binding:"min=0"`
+	CategoryId      string   `json:"category_id" binding:"omitempty,uuid"`
+	ProductId       string   `json:"product_id" binding:"omitempty,uuid"`
+	Barcode         string   `json:"barcode" binding:"max=48"`
+	Batch           string   `json:"batch" binding:"max=50"`
+	ExpiryDate      string   `json:"expiry_date" binding:"omitempty,datetime=2006-01-02"`
+	Serials         []string `json:"serials" binding:"dive,required,max=64"`
+	StorageComingId string   `json:"storage_coming_id" binding:"omitempty,uuid"`
+	Version         int      `json:"version" binding:"min=0"`
 }
 
 type StorageComingProductGetListRequest struct {
-	Offset int    `json:"offset"`
-	Limit  int    `json:"limit"`
-	Search string `json:"search"`
+	Offset          int      `json:"offset"`
+	Limit           int      `json:"limit"`
+	Search          string   `json:"search"`
+	StorageComingId string   `json:"storage_coming_id"`
+	BranchIds       []string `json:"-"`
+	IncludeDeleted  bool     `json:"include_deleted"`
 }
 
 type StorageComingProductGetListResponse struct {
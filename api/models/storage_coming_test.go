@@ -0,0 +1,73 @@
+package models
+
+import "testing"
+
+func TestStorageComingTransitions(t *testing.T) {
+
+	statuses := []string{
+		StorageComingStatusDraft,
+		StorageComingStatusInProcess,
+		StorageComingStatusReceived,
+		StorageComingStatusFinished,
+		StorageComingStatusCancelled,
+	}
+
+	tests := []struct {
+		from    string
+		allowed []string
+	}{
+		{StorageComingStatusDraft, []string{StorageComingStatusInProcess, StorageComingStatusCancelled}},
+		{StorageComingStatusInProcess, []string{StorageComingStatusReceived, StorageComingStatusFinished, StorageComingStatusCancelled}},
+		{StorageComingStatusReceived, []string{StorageComingStatusInProcess, StorageComingStatusFinished, StorageComingStatusCancelled}},
+		{StorageComingStatusFinished, []string{StorageComingStatusCancelled}},
+		{StorageComingStatusCancelled, nil},
+	}
+
+	if len(StorageComingTransitions) != len(tests) {
+		t.Fatalf("StorageComingTransitions has %d statuses, want %d", len(StorageComingTransitions), len(tests))
+	}
+
+	for _, tt := range tests {
+		next, ok := StorageComingTransitions[tt.from]
+		if !ok {
+			t.Errorf("StorageComingTransitions has no entry for %q", tt.from)
+			continue
+		}
+
+		for _, to := range statuses {
+			want := false
+			for _, allowed := range tt.allowed {
+				want = want || allowed == to
+			}
+
+			got := false
+			for _, status := range next {
+				got = got || status == to
+			}
+
+			if got != want {
+				t.Errorf("transition %q -> %q allowed = %v, want %v", tt.from, to, got, want)
+			}
+		}
+	}
+}
+
+func TestStorageComingEditable(t *testing.T) {
+
+	tests := []struct {
+		status string
+		want   bool
+	}{
+		{StorageComingStatusDraft, true},
+		{StorageComingStatusInProcess, true},
+		{StorageComingStatusReceived, true},
+		{StorageComingStatusFinished, false},
+		{StorageComingStatusCancelled, false},
+	}
+
+	for _, tt := range tests {
+		if got := StorageComingEditable(tt.status); got != tt.want {
+			t.Errorf("StorageComingEditable(%q) = %v, want %v", tt.status, got, tt.want)
+		}
+	}
+}
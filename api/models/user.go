@@ -0,0 +1,53 @@
+package models
+
+type UserPrimaryKey struct {
+	Id string `json:"id"`
+}
+
+type CreateUser struct {
+	Login        string   `json:"login" binding:"required,max=50"`
+	Password     string   `json:"password" binding:"required,min=8,max=72"`
+	PasswordHash string   `json:"-"`
+	FullName     string   `json:"full_name" binding:"max=100"`
+	Role         string   `json:"role" binding:"required"`
+	BranchIds    []string `json:"branch_ids" binding:"dive,uuid"`
+}
+
+type User struct {
+	Id           string   `json:"id"`
+	Login        string   `json:"login"`
+	PasswordHash string   `json:"-"`
+	FullName     string   `json:"full_name"`
+	Active       bool     `json:"active"`
+	Role         string   `json:"role"`
+	BranchIds    []string `json:"branch_ids"`
+	CreatedAt    string   `json:"created_at"`
+	UpdatedAt    string   `json:"updated_at"`
+}
+
+// UpdateUser keeps the current password when Password is empty.
+type UpdateUser struct {
+	Id           string   `json:"id"`
+	Login        string   `json:"login" binding:"required,max=50"`
+	Password     string   `json:"password" binding:"omitempty,min=8,max=72"`
+	PasswordHash string   `json:"-"`
+	FullName     string   `json:"full_name" binding:"max=100"`
+	Active       bool     `json:"active"`
+	Role         string   `json:"role" binding:"required"`
+	BranchIds    []string `json:"branch_ids" binding:"dive,uuid"`
+}
+
+type UserGetListRequest struct {
+	Offset int    `json:"offset"`
+	Limit  int    `json:"limit"`
+	Search string `json:"search"`
+}
+
+type UserGetListResponse struct {
+	Count int     `json:"count"`
+	Users []*User `json:"users"`
+}
+
+type UserLogin struct {
+	Login string `json:"login"`
+}
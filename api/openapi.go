@@ -0,0 +1,198 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+
+	"market/api/handler"
+	"market/api/models"
+	"market/pkg/openapi"
+)
+
+const specTitle = "Market API"
+
+var (
+	specOnce sync.Once
+	specJSON []byte
+)
+
+var (
+	idempotent        = []string{"Idempotency-Key"}
+	ifMatch           = []string{"If-Match"}
+	idempotentIfMatch = []string{"Idempotency-Key", "If-Match"}
+)
+
+// Spec documents the routes NewApi registers. Keep routes in step with
+// NewApi; `market openapi -check` and the warnings at startup list where
+// they drift apart.
+func Spec() *openapi.Document {
+
+	builder := &openapi.Builder{
+		Title:       specTitle,
+		Description: "Inventory of branches: catalog, goods receipts, stock and reports. Every JSON response is wrapped in an envelope with the payload in data and, on errors, the reason in error.",
+		Version:     "1.0.0",
+		Envelope:    handler.Response{},
+		Security: map[string]*openapi.SecurityScheme{
+			"bearer": {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+			"apiKey": {Type: "apiKey", Name: "X-API-Key", In: "header"},
+		},
+	}
+
+	return builder.Build(routes())
+}
+
+// CheckSpec lists the differences between the routes served by r and Spec.
+func CheckSpec(r *gin.Engine) []string {
+
+	var served []openapi.Served
+
+	for _, route := range r.Routes() {
+		served = append(served, openapi.Served{
+			Method:  route.Method,
+			Path:    route.Path,
+			Handler: route.Handler,
+		})
+	}
+
+	return Spec().Diff(served)
+}
+
+func serveSpec(c *gin.Context) {
+
+	specOnce.Do(func() {
+		specJSON, _ = json.Marshal(Spec())
+	})
+
+	c.Data(http.StatusOK, "application/json; charset=utf-8", specJSON)
+}
+
+func serveDocs(c *gin.Context) {
+	c.Data(http.StatusOK, "text/html; charset=utf-8", openapi.SwaggerUI(specTitle, "/openapi.json"))
+}
+
+// params declares query or form parameters as "name" for strings or
+// "name:type" for anything else.
+func params(names ...string) []openapi.Param {
+
+	var result []openapi.Param
+
+	for _, name := range names {
+		param := openapi.Param{Name: name}
+
+		if i := strings.Index(name, ":"); i >= 0 {
+			param.Name, param.Type = name[:i], name[i+1:]
+		}
+
+		result = append(result, param)
+	}
+
+	return result
+}
+
+func routes() []openapi.Route {
+	return []openapi.Route{
+		{Method: http.MethodGet, Path: "/openapi.json", Handler: serveSpec, Tag: "docs", Summary: "This document", Public: true, ContentType: "application/json"},
+		{Method: http.MethodGet, Path: "/docs", Handler: serveDocs, Tag: "docs", Summary: "Swagger UI for this document", Public: true, ContentType: "text/html"},
+
+		{Method: http.MethodPost, Path: "/auth/login", Handler: (*handler.Handler).Login, Tag: "auth", Summary: "Log in with login and password", Public: true, Request: models.Login{}, Response: models.Token{}},
+		{Method: http.MethodPost, Path: "/auth/refresh", Handler: (*handler.Handler).RefreshToken, Tag: "auth", Summary: "Trade a refresh token for a new pair of tokens", Public: true, Request: models.RefreshToken{}, Response: models.Token{}},
+		{Method: http.MethodPost, Path: "/auth/logout", Handler: (*handler.Handler).Logout, Tag: "auth", Summary: "End the session of the token", Status: http.StatusNoContent},
+		{Method: http.MethodGet, Path: "/auth/me", Handler: (*handler.Handler).Me, Tag: "auth", Summary: "The user of the token", Response: models.User{}},
+
+		{Method: http.MethodPost, Path: "/user", Handler: (*handler.Handler).CreateUser, Tag: "user", Summary: "Create a user", Headers: idempotent, Request: models.CreateUser{}, Response: models.User{}, Status: http.StatusCreated},
+		{Method: http.MethodGet, Path: "/user/:id", Handler: (*handler.Handler).GetByIdUser, Tag: "user", Summary: "Get a user", Response: models.User{}},
+		{Method: http.MethodGet, Path: "/user", Handler: (*handler.Handler).GetListUser, Tag: "user", Summary: "List users", Query: params("offset:integer", "limit:integer", "search"), Response: models.UserGetListResponse{}},
+		{Method: http.MethodPut, Path: "/user/:id", Handler: (*handler.Handler).UpdateUser, Tag: "user", Summary: "Update a user", Request: models.UpdateUser{}, Response: models.User{}, Status: http.StatusAccepted},
+		{Method: http.MethodDelete, Path: "/user/:id", Handler: (*handler.Handler).DeleteUser, Tag: "user", Summary: "Delete a user", Status: http.StatusNoContent},
+
+		{Method: http.MethodPost, Path: "/api_key", Handler: (*handler.Handler).CreateApiKey, Tag: "api_key", Summary: "Issue an API key, the secret is shown only once", Headers: idempotent, Request: models.CreateApiKey{}, Response: models.ApiKeySecret{}, Status: http.StatusCreated},
+		{Method: http.MethodGet, Path: "/api_key/:id", Handler: (*handler.Handler).GetByIdApiKey, Tag: "api_key", Summary: "Get an API key", Response: models.ApiKey{}},
+		{Method: http.MethodGet, Path: "/api_key", Handler: (*handler.Handler).GetListApiKey, Tag: "api_key", Summary: "List API keys", Query: params("offset:integer", "limit:integer", "revoked:boolean", "search"), Response: models.ApiKeyGetListResponse{}},
+		{Method: http.MethodPost, Path: "/api_key/:id/rotate", Handler: (*handler.Handler).RotateApiKey, Tag: "api_key", Summary: "Issue a new secret for an API key", Response: models.ApiKeySecret{}},
+		{Method: http.MethodDelete, Path: "/api_key/:id", Handler: (*handler.Handler).DeleteApiKey, Tag: "api_key", Summary: "Revoke an API key", Status: http.StatusNoContent},
+
+		{Method: http.MethodGet, Path: "/audit_log", Handler: (*handler.Handler).GetListAuditLog, Tag: "audit_log", Summary: "List changes to records", Query: params("offset:integer", "limit:integer", "from", "to", "entity", "entity_id", "actor_id", "action"), Response: models.AuditLogGetListResponse{}},
+
+		{Method: http.MethodPost, Path: "/branch", Handler: (*handler.Handler).CreateBranch, Tag: "branch", Summary: "Create a branch", Headers: idempotent, Request: models.CreateBranch{}, Response: models.Branch{}, Status: http.StatusCreated},
+		{Method: http.MethodGet, Path: "/branch/:id", Handler: (*handler.Handler).GetByIdBranch, Tag: "branch", Summary: "Get a branch", Query: params("include_deleted:boolean"), Response: models.Branch{}},
+		{Method: http.MethodGet, Path: "/branch", Handler: (*handler.Handler).GetListBranch, Tag: "branch", Summary: "List branches", Query: params("offset:integer", "limit:integer", "search", "include_deleted:boolean"), Response: models.BranchGetListResponse{}},
+		{Method: http.MethodPut, Path: "/branch/:id", Handler: (*handler.Handler).UpdateBranch, Tag: "branch", Summary: "Update a branch", Headers: ifMatch, Request: models.UpdateBranch{}, Response: models.Branch{}, Status: http.StatusAccepted},
+		{Method: http.MethodDelete, Path: "/branch/:id", Handler: (*handler.Handler).DeleteBranch, Tag: "branch", Summary: "Delete a branch", Status: http.StatusNoContent},
+		{Method: http.MethodPost, Path: "/branch/:id/restore", Handler: (*handler.Handler).RestoreBranch, Tag: "branch", Summary: "Restore a deleted branch", Response: models.Branch{}},
+
+		{Method: http.MethodPost, Path: "/category", Handler: (*handler.Handler).CreateCategory, Tag: "category", Summary: "Create a category", Headers: idempotent, Request: models.CreateCategory{}, Response: models.Category{}, Status: http.StatusCreated},
+		{Method: http.MethodGet, Path: "/category/:id", Handler: (*handler.Handler).GetByIdCategory, Tag: "category", Summary: "Get a category", Query: params("include_deleted:boolean"), Response: models.Category{}},
+		{Method: http.MethodGet, Path: "/category", Handler: (*handler.Handler).GetListCategory, Tag: "category", Summary: "List categories", Query: params("offset:integer", "limit:integer", "search", "include_deleted:boolean"), Response: models.CategoryGetListResponse{}},
+		{Method: http.MethodPut, Path: "/category/:id", Handler: (*handler.Handler).UpdateCategory, Tag: "category", Summary: "Update a category", Headers: ifMatch, Request: models.UpdateCategory{}, Response: models.Category{}, Status: http.StatusAccepted},
+		{Method: http.MethodDelete, Path: "/category/:id", Handler: (*handler.Handler).DeleteCategory, Tag: "category", Summary: "Delete a category", Status: http.StatusNoContent},
+		{Method: http.MethodPost, Path: "/category/:id/restore", Handler: (*handler.Handler).RestoreCategory, Tag: "category", Summary: "Restore a deleted category", Response: models.Category{}},
+
+		{Method: http.MethodPost, Path: "/product", Handler: (*handler.Handler).CreateProduct, Tag: "product", Summary: "Create a product", Headers: idempotent, Request: models.CreateProduct{}, Response: models.Product{}, Status: http.StatusCreated},
+		{Method: http.MethodPost, Path: "/product/import", Handler: (*handler.Handler).ImportProduct, Tag: "product", Summary: "Import products from a CSV or XLSX file", Headers: idempotent, Form: params("file:file", "dry_run:boolean", "mapping"), Response: models.ProductImportResponse{}, Status: http.StatusCreated},
+		{Method: http.MethodGet, Path: "/product/:id", Handler: (*handler.Handler).GetByIdProduct, Tag: "product", Summary: "Get a product", Query: params("include_deleted:boolean"), Response: models.Product{}},
+		{Method: http.MethodGet, Path: "/product", Handler: (*handler.Handler).GetListProduct, Tag: "product", Summary: "List products", Query: params("offset:integer", "limit:integer", "search", "category_id", "parent_id", "group_variants:boolean", "include_deleted:boolean"), Response: models.ProductGetListResponse{}},
+		{Method: http.MethodPut, Path: "/product/:id", Handler: (*handler.Handler).UpdateProduct, Tag: "product", Summary: "Update a product", Headers: ifMatch, Request: models.UpdateProduct{}, Response: models.Product{}, Status: http.StatusAccepted},
+		{Method: http.MethodPatch, Path: "/product/:id", Handler: (*handler.Handler).PatchProduct, Tag: "product", Summary: "Update some fields of a product", Headers: ifMatch, Request: map[string]interface{}{}, Response: models.Product{}, Status: http.StatusAccepted},
+		{Method: http.MethodDelete, Path: "/product/:id", Handler: (*handler.Handler).DeleteProduct, Tag: "product", Summary: "Delete a product", Status: http.StatusNoContent},
+		{Method: http.MethodPost, Path: "/product/:id/restore", Handler: (*handler.Handler).RestoreProduct, Tag: "product", Summary: "Restore a deleted product", Response: models.Product{}},
+
+		{Method: http.MethodPost, Path: "/product/:id/barcode", Handler: (*handler.Handler).CreateProductBarcode, Tag: "barcode", Summary: "Add a barcode to a product", Headers: idempotent, Request: models.CreateProductBarcode{}, Response: models.ProductBarcode{}, Status: http.StatusCreated},
+		{Method: http.MethodGet, Path: "/product/:id/barcode", Handler: (*handler.Handler).GetListProductBarcode, Tag: "barcode", Summary: "List the barcodes of a product", Response: models.ProductBarcodeGetListResponse{}},
+		{Method: http.MethodDelete, Path: "/product_barcode/:id", Handler: (*handler.Handler).DeleteProductBarcode, Tag: "barcode", Summary: "Remove a barcode from a product", Status: http.StatusNoContent},
+		{Method: http.MethodPost, Path: "/product/:id/image", Handler: (*handler.Handler).UploadProductImage, Tag: "product_image", Summary: "Upload an image of a product", Headers: idempotent, Form: params("file:file"), Response: models.ProductImage{}, Status: http.StatusCreated},
+		{Method: http.MethodPut, Path: "/product/:id/image/order", Handler: (*handler.Handler).ReorderProductImage, Tag: "product_image", Summary: "Order the images of a product", Request: models.ProductImageOrder{}, Response: models.ProductImageGetListResponse{}, Status: http.StatusAccepted},
+		{Method: http.MethodGet, Path: "/product_image/:id/file", Handler: (*handler.Handler).GetProductImageFile, Tag: "product_image", Summary: "Download an image", ContentType: "image/*"},
+		{Method: http.MethodGet, Path: "/product_image/:id/thumbnail", Handler: (*handler.Handler).GetProductImageThumbnail, Tag: "product_image", Summary: "Download the thumbnail of an image", ContentType: "image/*"},
+		{Method: http.MethodDelete, Path: "/product_image/:id", Handler: (*handler.Handler).DeleteProductImage, Tag: "product_image", Summary: "Delete an image", Status: http.StatusNoContent},
+
+		{Method: http.MethodGet, Path: "/barcode/:barcode", Handler: (*handler.Handler).GetByBarcodeProduct, Tag: "barcode", Summary: "Find a product by any of its barcodes or a scale label", Response: models.ProductBarcodeLookupResponse{}},
+		{Method: http.MethodPost, Path: "/barcode/generate", Handler: (*handler.Handler).GenerateBarcode, Tag: "barcode", Summary: "Issue an unused internal barcode", Request: models.GenerateBarcode{}, Response: models.GenerateBarcodeResponse{}, Status: http.StatusCreated},
+		{Method: http.MethodGet, Path: "/barcode/:barcode/image", Handler: (*handler.Handler).GetBarcodeImage, Tag: "barcode", Summary: "Draw a barcode as SVG or PNG", Query: params("format"), ContentType: "application/octet-stream"},
+
+		{Method: http.MethodPost, Path: "/label", Handler: (*handler.Handler).PrintLabels, Tag: "label", Summary: "Print price labels as SVG, PNG, PDF or ZPL", Request: models.LabelRequest{}, ContentType: "application/octet-stream"},
+
+		{Method: http.MethodPost, Path: "/storage_coming", Handler: (*handler.Handler).CreateStorageComing, Tag: "storage_coming", Summary: "Create a goods receipt", Headers: idempotent, Request: models.CreateStorageComing{}, Response: models.StorageComing{}, Status: http.StatusCreated},
+		{Method: http.MethodPost, Path: "/storage_coming/import", Handler: (*handler.Handler).ImportInvoice, Tag: "storage_coming", Summary: "Create a goods receipt from a supplier invoice", Headers: idempotent, Form: params("file:file", "branch_id", "coming_id", "mapping"), Response: models.InvoiceImportResponse{}, Status: http.StatusCreated},
+		{Method: http.MethodGet, Path: "/storage_coming/:id", Handler: (*handler.Handler).GetByIdStorageComing, Tag: "storage_coming", Summary: "Get a goods receipt with its history", Query: params("include_deleted:boolean"), Response: models.StorageComing{}},
+		{Method: http.MethodGet, Path: "/storage_coming", Handler: (*handler.Handler).GetListStorageComing, Tag: "storage_coming", Summary: "List goods receipts", Query: params("offset:integer", "limit:integer", "search", "include_deleted:boolean"), Response: models.StorageComingGetListResponse{}},
+		{Method: http.MethodPut, Path: "/storage_coming/:id", Handler: (*handler.Handler).UpdateStorageComing, Tag: "storage_coming", Summary: "Update a goods receipt", Headers: idempotentIfMatch, Request: models.UpdateStorageComing{}, Response: models.StorageComing{}, Status: http.StatusAccepted},
+		{Method: http.MethodDelete, Path: "/storage_coming/:id", Handler: (*handler.Handler).DeleteStorageComing, Tag: "storage_coming", Summary: "Delete a goods receipt", Status: http.StatusNoContent},
+		{Method: http.MethodPost, Path: "/storage_coming/:id/restore", Handler: (*handler.Handler).RestoreStorageComing, Tag: "storage_coming", Summary: "Restore a deleted goods receipt", Response: models.StorageComing{}},
+		{Method: http.MethodPost, Path: "/storage_coming/:id/start", Handler: (*handler.Handler).StartStorageComing, Tag: "storage_coming", Summary: "Start receiving goods", Headers: idempotentIfMatch, Request: models.TransitionStorageComing{}, Response: models.StorageComing{}},
+		{Method: http.MethodPost, Path: "/storage_coming/:id/receive", Handler: (*handler.Handler).ReceiveStorageComing, Tag: "storage_coming", Summary: "Mark the goods as received", Headers: idempotentIfMatch, Request: models.TransitionStorageComing{}, Response: models.StorageComing{}},
+		{Method: http.MethodPost, Path: "/storage_coming/:id/finish", Handler: (*handler.Handler).FinishStorageComing, Tag: "storage_coming", Summary: "Finish a goods receipt and post its stock", Headers: idempotentIfMatch, Request: models.TransitionStorageComing{}, Response: models.StorageComing{}},
+		{Method: http.MethodPost, Path: "/storage_coming/:id/cancel", Handler: (*handler.Handler).CancelStorageComing, Tag: "storage_coming", Summary: "Cancel a goods receipt, reversing its stock when finished", Headers: idempotentIfMatch, Request: models.TransitionStorageComing{}, Response: models.StorageComing{}},
+
+		{Method: http.MethodPost, Path: "/storage_coming_product", Handler: (*handler.Handler).CreateStorageComingProduct, Tag: "storage_coming_product", Summary: "Add a line to a goods receipt", Headers: idempotent, Request: models.CreateStorageComingProduct{}, Response: models.StorageComingProduct{}, Status: http.StatusCreated},
+		{Method: http.MethodGet, Path: "/storage_coming_product/:id", Handler: (*handler.Handler).GetByIdStorageComingProduct, Tag: "storage_coming_product", Summary: "Get a line of a goods receipt", Query: params("include_deleted:boolean"), Response: models.StorageComingProduct{}},
+		{Method: http.MethodGet, Path: "/storage_coming_product", Handler: (*handler.Handler).GetListStorageComingProduct, Tag: "storage_coming_product", Summary: "List lines of goods receipts", Query: params("offset:integer", "limit:integer", "search", "storage_coming_id", "include_deleted:boolean"), Response: models.StorageComingProductGetListResponse{}},
+		{Method: http.MethodPut, Path: "/storage_coming_product/:id", Handler: (*handler.Handler).UpdateStorageComingProduct, Tag: "storage_coming_product", Summary: "Update a line of a goods receipt", Headers: ifMatch, Request: models.UpdateStorageComingProduct{}, Response: models.StorageComingProduct{}, Status: http.StatusAccepted},
+		{Method: http.MethodDelete, Path: "/storage_coming_product/:id", Handler: (*handler.Handler).DeleteStorageComingProduct, Tag: "storage_coming_product", Summary: "Delete a line of a goods receipt", Status: http.StatusNoContent},
+		{Method: http.MethodPost, Path: "/storage_coming_product/:id/restore", Handler: (*handler.Handler).RestoreStorageComingProduct, Tag: "storage_coming_product", Summary: "Restore a deleted line", Response: models.StorageComingProduct{}},
+
+		{Method: http.MethodGet, Path: "/remaining/:id", Handler: (*handler.Handler).GetByIdRemaining, Tag: "remaining", Summary: "Get the stock of a product in a branch", Response: models.Remaining{}},
+		{Method: http.MethodGet, Path: "/remaining", Handler: (*handler.Handler).GetListRemaining, Tag: "remaining", Summary: "List stock", Query: params("offset:integer", "limit:integer", "search", "branch_id", "category_id"), Response: models.RemainingGetListResponse{}},
+		{Method: http.MethodGet, Path: "/remaining/:id/batch", Handler: (*handler.Handler).GetListRemainingBatch, Tag: "remaining", Summary: "List the batches of a stock record", Response: models.RemainingBatchGetListResponse{}},
+		{Method: http.MethodPost, Path: "/remaining/consume", Handler: (*handler.Handler).ConsumeRemaining, Tag: "remaining", Summary: "Take stock out, oldest batches first", Headers: idempotent, Request: models.ConsumeRemaining{}, Response: models.ConsumeRemainingResponse{}},
+
+		{Method: http.MethodGet, Path: "/serial_number/:id", Handler: (*handler.Handler).GetByIdSerialNumber, Tag: "serial_number", Summary: "Get a serial number with its events", Response: models.SerialNumber{}},
+		{Method: http.MethodGet, Path: "/serial_number", Handler: (*handler.Handler).GetListSerialNumber, Tag: "serial_number", Summary: "List serial numbers", Query: params("offset:integer", "limit:integer", "serial", "product_id", "branch_id", "status"), Response: models.SerialNumberGetListResponse{}},
+		{Method: http.MethodPost, Path: "/serial_number/:id/move", Handler: (*handler.Handler).MoveSerialNumber, Tag: "serial_number", Summary: "Sell, return or transfer a unit", Request: models.MoveSerialNumber{}, Response: models.SerialNumber{}, Status: http.StatusAccepted},
+
+		{Method: http.MethodPost, Path: "/stock_level", Handler: (*handler.Handler).CreateStockLevel, Tag: "stock_level", Summary: "Set the minimum and maximum stock of a product in a branch", Headers: idempotent, Request: models.CreateStockLevel{}, Response: models.StockLevel{}, Status: http.StatusCreated},
+		{Method: http.MethodGet, Path: "/stock_level/:id", Handler: (*handler.Handler).GetByIdStockLevel, Tag: "stock_level", Summary: "Get a stock level", Response: models.StockLevel{}},
+		{Method: http.MethodGet, Path: "/stock_level", Handler: (*handler.Handler).GetListStockLevel, Tag: "stock_level", Summary: "List stock levels", Query: params("offset:integer", "limit:integer", "branch_id", "product_id"), Response: models.StockLevelGetListResponse{}},
+		{Method: http.MethodDelete, Path: "/stock_level/:id", Handler: (*handler.Handler).DeleteStockLevel, Tag: "stock_level", Summary: "Delete a stock level", Status: http.StatusNoContent},
+
+		{Method: http.MethodGet, Path: "/export/:entity", Handler: (*handler.Handler).Export, Tag: "export", Summary: "Export records as CSV, XLSX or NDJSON, other query parameters filter", Query: params("format"), ContentType: "application/octet-stream"},
+
+		{Method: http.MethodGet, Path: "/report/inventory_valuation", Handler: (*handler.Handler).InventoryValuationReport, Tag: "report", Summary: "Value of the stock at a point in time", Query: params("as_of", "method", "branch_id", "category_id"), Response: models.InventoryValuation{}},
+		{Method: http.MethodGet, Path: "/report/goods_receipt", Handler: (*handler.Handler).GoodsReceiptReport, Tag: "report", Summary: "Goods received over a period", Query: params("offset:integer", "limit:integer", "from", "to", "group_by", "status", "branch_id", "category_id", "product_id"), Response: models.GoodsReceiptReport{}},
+		{Method: http.MethodGet, Path: "/report/reorder", Handler: (*handler.Handler).ReorderReport, Tag: "report", Summary: "What to order to get back to the stock levels", Query: params("all:boolean", "window_days:integer", "lead_days:integer", "branch_id"), Response: models.ReorderReport{}},
+		{Method: http.MethodGet, Path: "/report/expiring", Handler: (*handler.Handler).ExpiringReport, Tag: "report", Summary: "Batches that expire soon", Query: params("days:integer", "branch_id"), Response: models.ExpiringReport{}},
+	}
+}
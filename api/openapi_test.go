@@ -0,0 +1,26 @@
+package api
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"market/config"
+	"market/pkg/logger"
+)
+
+// TestSpecMatchesRoutes fails when a route is added, removed or renamed
+// without the table of api/openapi.go following.
+func TestSpecMatchesRoutes(t *testing.T) {
+
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	NewApi(r, &config.Config{}, nil, nil, nil, logger.NewLogger("test", logger.LevelError))
+
+	drift := CheckSpec(r)
+	if len(drift) > 0 {
+		t.Fatalf("routes and specification differ:\n  %s", strings.Join(drift, "\n  "))
+	}
+}
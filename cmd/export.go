@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"market/config"
+	"market/export"
+	"market/storage/postgres"
+)
+
+// runExport implements `market export <entity> [-format csv] [-o file] [-filter key=value ...]`.
+func runExport(cfg *config.Config, args []string) error {
+
+	var (
+		flags   = flag.NewFlagSet("export", flag.ContinueOnError)
+		format  = flags.String("format", export.FormatCSV, "output format: csv, xlsx or ndjson")
+		output  = flags.String("o", "", "output file, stdout when empty")
+		filters = export.Filters{}
+	)
+
+	flags.Func("filter", "list filter as key=value, may be repeated", func(value string) error {
+		key, val, ok := strings.Cut(value, "=")
+		if !ok {
+			return fmt.Errorf("expected key=value, got %q", value)
+		}
+
+		filters[key] = val
+		return nil
+	})
+
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "usage: export <%s> [flags]\n", strings.Join(export.Entities(), "|"))
+		flags.PrintDefaults()
+	}
+
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		flags.Usage()
+		return export.ErrUnknownEntity
+	}
+
+	entity := args[0]
+
+	err := flags.Parse(args[1:])
+	if err != nil {
+		return err
+	}
+
+	err = export.Check(entity, *format)
+	if err != nil {
+		return err
+	}
+
+	pgconn, err := postgres.NewConnectionPostgres(cfg)
+	if err != nil {
+		return err
+	}
+	defer pgconn.Close()
+
+	out := os.Stdout
+	if *output != "" {
+		out, err = os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer out.Close()
+	}
+
+	return export.Run(context.Background(), pgconn, entity, *format, filters, nil, out)
+}
@@ -1 +1,132 @@
-package cmd
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"market/api"
+	"market/config"
+	"market/grpc"
+	"market/job"
+	"market/pkg/blob"
+	"market/pkg/logger"
+	"market/pkg/ratelimit"
+	"market/pkg/security"
+	"market/storage/postgres"
+)
+
+func main() {
+
+	cfg := config.Load()
+
+	if err := cfg.Validate(); err != nil {
+		fmt.Fprintln(os.Stderr, "config:", err)
+		os.Exit(1)
+	}
+
+	if len(os.Args) > 1 {
+		var err error
+
+		switch os.Args[1] {
+		case "export":
+			err = runExport(&cfg, os.Args[2:])
+		case "create-user":
+			err = runCreateUser(&cfg, os.Args[2:])
+		case "openapi":
+			err = runOpenAPI(&cfg, os.Args[2:])
+		default:
+			err = fmt.Errorf("unknown command %q, expected export, create-user or openapi", os.Args[1])
+		}
+
+		if err != nil {
+			fmt.Fprintln(os.Stderr, os.Args[1]+":", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	var loggerLevel string
+
+	switch cfg.Environment {
+	case config.DebugMode:
+		loggerLevel = logger.LevelDebug
+		gin.SetMode(gin.DebugMode)
+	case config.TestMode:
+		loggerLevel = logger.LevelDebug
+		gin.SetMode(gin.TestMode)
+	default:
+		loggerLevel = logger.LevelInfo
+		gin.SetMode(gin.ReleaseMode)
+	}
+
+	log := logger.NewLogger("app", loggerLevel)
+	defer func() {
+		err := logger.Cleanup(log)
+		if err != nil {
+			return
+		}
+	}()
+
+	if cfg.JWTSecret == "" {
+		cfg.JWTSecret, _ = security.NewToken(32)
+		log.Warn("JWT_SECRET is not set, tokens will not survive a restart")
+	}
+
+	pgconn, err := postgres.NewConnectionPostgres(&cfg)
+	if err != nil {
+		panic("postgres no connection: " + err.Error())
+	}
+	defer pgconn.Close()
+
+	blobStorage, err := blob.New(&cfg)
+	if err != nil {
+		panic("blob storage: " + err.Error())
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go job.NewSnapshot(&cfg, pgconn, log).Run(ctx)
+	go job.NewLowStock(&cfg, pgconn, log).Run(ctx)
+	go job.NewPurge(&cfg, pgconn, log).Run(ctx)
+
+	// REST and gRPC share the limiter, so an API key gets one budget.
+	limiter := ratelimit.New(time.Minute)
+
+	r := gin.New()
+
+	r.Use(gin.Logger(), gin.Recovery())
+
+	api.NewApi(r, &cfg, pgconn, blobStorage, limiter, log)
+
+	if cfg.GRPCPort != "" {
+		lis, err := net.Listen("tcp", cfg.ServerHost+cfg.GRPCPort)
+		if err != nil {
+			panic("gRPC listen error: " + err.Error())
+		}
+
+		grpcServer := grpc.SetUpServer(&cfg, pgconn, limiter, log)
+		defer grpcServer.Stop()
+
+		go func() {
+			log.Info("Listening gRPC server", logger.Any("address", cfg.ServerHost+cfg.GRPCPort))
+
+			err := grpcServer.Serve(lis)
+			if err != nil {
+				log.Error("gRPC server error", logger.Error(err))
+			}
+		}()
+	}
+
+	log.Info("Listening server", logger.Any("address", cfg.ServerHost+cfg.HTTPPort))
+
+	err = r.Run(cfg.ServerHost + cfg.HTTPPort)
+	if err != nil {
+		panic("Listening server error: " + err.Error())
+	}
+}
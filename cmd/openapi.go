@@ -0,0 +1,62 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"market/api"
+	"market/config"
+	"market/pkg/logger"
+)
+
+// runOpenAPI implements `market openapi [-o file] [-check]`. With -check it
+// registers the routes without connecting anywhere and fails when they and
+// the specification disagree, which is how CI keeps the two in step.
+func runOpenAPI(cfg *config.Config, args []string) error {
+
+	var (
+		flags  = flag.NewFlagSet("openapi", flag.ContinueOnError)
+		output = flags.String("o", "", "output file, stdout when empty")
+		check  = flags.Bool("check", false, "compare the routes with the specification instead of printing it")
+	)
+
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	if *check {
+		gin.SetMode(gin.ReleaseMode)
+
+		r := gin.New()
+		api.NewApi(r, cfg, nil, nil, nil, logger.NewLogger("openapi", logger.LevelError))
+
+		drift := api.CheckSpec(r)
+		if len(drift) > 0 {
+			return fmt.Errorf("routes and specification differ:\n  %s", strings.Join(drift, "\n  "))
+		}
+
+		fmt.Println("routes and specification match")
+		return nil
+	}
+
+	var out = os.Stdout
+
+	if *output != "" {
+		out, err = os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer out.Close()
+	}
+
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(api.Spec())
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+
+	"market/api/models"
+	"market/config"
+	"market/pkg/rbac"
+	"market/pkg/security"
+	"market/storage/postgres"
+)
+
+// runCreateUser implements `market create-user -login admin -password ... [-name ...]
+// [-role admin] [-branch id ...]`, the way to get the first user in before
+// anyone can log in.
+func runCreateUser(cfg *config.Config, args []string) error {
+
+	var (
+		flags    = flag.NewFlagSet("create-user", flag.ContinueOnError)
+		login    = flags.String("login", "", "user login")
+		password = flags.String("password", "", "user password, at least 8 characters")
+		name     = flags.String("name", "", "full name")
+		role     = flags.String("role", rbac.RoleAdmin, "one of "+strings.Join(rbac.Roles(), ", "))
+		branches branchFlag
+	)
+
+	flags.Var(&branches, "branch", "branch id the user works in, repeatable")
+
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	if *login == "" || len(*password) < 8 {
+		flags.Usage()
+		return errors.New("login is required and password must be at least 8 characters")
+	}
+
+	if !rbac.ValidRole(*role) {
+		return fmt.Errorf("invalid role %q", *role)
+	}
+
+	hash, err := security.HashPassword(*password)
+	if err != nil {
+		return err
+	}
+
+	pgconn, err := postgres.NewConnectionPostgres(cfg)
+	if err != nil {
+		return err
+	}
+	defer pgconn.Close()
+
+	id, err := pgconn.User().Create(context.Background(), &models.CreateUser{
+		Login:        *login,
+		PasswordHash: hash,
+		FullName:     *name,
+		Role:         *role,
+		BranchIds:    branches,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(id)
+
+	return nil
+}
+
+type branchFlag []string
+
+func (b *branchFlag) String() string {
+	return strings.Join(*b, ",")
+}
+
+func (b *branchFlag) Set(value string) error {
+	*b = append(*b, value)
+	return nil
+}
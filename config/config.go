@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/joho/godotenv"
 	"github.com/spf13/cast"
@@ -19,6 +20,7 @@ type Config struct {
 
 	ServerHost string
 	HTTPPort   string
+	GRPCPort   string
 
 	PostgresHost          string
 	PostgresUser          string
@@ -29,12 +31,47 @@ type Config struct {
 
 	DefaultOffset int
 	DefaultLimit  int
+
+	InternalBarcodePrefixFrom int
+	InternalBarcodePrefixTo   int
+	WeightBarcodePrefixes     []string
+	PriceBarcodePrefixes      []string
+
+	BlobBackend   string
+	BlobLocalPath string
+	S3Endpoint    string
+	S3Region      string
+	S3Bucket      string
+	S3AccessKey   string
+	S3SecretKey   string
+
+	PublicBaseURL      string
+	ImageMaxSize       int64
+	ImageThumbnailSize int
+
+	ImportMaxSize int64
+
+	ReorderWindowDays     int
+	ReorderLeadDays       int
+	LowStockCheckInterval int
+	LowStockWebhookURL    string
+	StockSnapshotInterval int
+
+	JWTSecret       string
+	AccessTokenTTL  int
+	RefreshTokenTTL int
+
+	ApiKeyRateLimit int
+
+	IdempotencyKeyTTL int
+
+	PurgeAfterDays int
 }
 
 func Load() Config {
 
 	if err := godotenv.Load(".env"); err != nil {
-		fmt.Println("No .env file found")
+		fmt.Fprintln(os.Stderr, "No .env file found")
 	}
 
 	cfg := Config{}
@@ -46,6 +83,8 @@ func Load() Config {
 
 	cfg.ServerHost = cast.ToString(getOrReturnDefaultValue("SERVER_HOST", "localhost"))
 	cfg.HTTPPort = cast.ToString(getOrReturnDefaultValue("HTTP_PORT", ":8080"))
+	// The gRPC server is off unless a port like ":9090" is set.
+	cfg.GRPCPort = cast.ToString(getOrReturnDefaultValue("GRPC_PORT", ""))
 
 	cfg.PostgresHost = cast.ToString(getOrReturnDefaultValue("POSTGRES_HOST", "localhost"))
 	cfg.PostgresUser = cast.ToString(getOrReturnDefaultValue("POSTGRES_USER", "abdulbosit"))
@@ -58,6 +97,49 @@ func Load() Config {
 	cfg.DefaultOffset = cast.ToInt(getOrReturnDefaultValue("OFFSET", 0))
 	cfg.DefaultLimit = cast.ToInt(getOrReturnDefaultValue("LIMIT", 10))
 
+	cfg.InternalBarcodePrefixFrom = cast.ToInt(getOrReturnDefaultValue("INTERNAL_BARCODE_PREFIX_FROM", 200))
+	cfg.InternalBarcodePrefixTo = cast.ToInt(getOrReturnDefaultValue("INTERNAL_BARCODE_PREFIX_TO", 209))
+	cfg.WeightBarcodePrefixes = strings.Split(cast.ToString(getOrReturnDefaultValue("WEIGHT_BARCODE_PREFIXES", "21,22,23,24,25")), ",")
+	cfg.PriceBarcodePrefixes = strings.Split(cast.ToString(getOrReturnDefaultValue("PRICE_BARCODE_PREFIXES", "26,27,28,29")), ",")
+
+	cfg.BlobBackend = cast.ToString(getOrReturnDefaultValue("BLOB_BACKEND", "local"))
+	cfg.BlobLocalPath = cast.ToString(getOrReturnDefaultValue("BLOB_LOCAL_PATH", "./uploads"))
+	cfg.S3Endpoint = cast.ToString(getOrReturnDefaultValue("S3_ENDPOINT", ""))
+	cfg.S3Region = cast.ToString(getOrReturnDefaultValue("S3_REGION", "us-east-1"))
+	cfg.S3Bucket = cast.ToString(getOrReturnDefaultValue("S3_BUCKET", ""))
+	cfg.S3AccessKey = cast.ToString(getOrReturnDefaultValue("S3_ACCESS_KEY", ""))
+	cfg.S3SecretKey = cast.ToString(getOrReturnDefaultValue("S3_SECRET_KEY", ""))
+
+	cfg.PublicBaseURL = cast.ToString(getOrReturnDefaultValue("PUBLIC_BASE_URL", ""))
+	cfg.ImageMaxSize = cast.ToInt64(getOrReturnDefaultValue("IMAGE_MAX_SIZE", 10<<20))
+	cfg.ImageThumbnailSize = cast.ToInt(getOrReturnDefaultValue("IMAGE_THUMBNAIL_SIZE", 256))
+
+	cfg.ImportMaxSize = cast.ToInt64(getOrReturnDefaultValue("IMPORT_MAX_SIZE", 20<<20))
+
+	cfg.ReorderWindowDays = cast.ToInt(getOrReturnDefaultValue("REORDER_WINDOW_DAYS", 30))
+	cfg.ReorderLeadDays = cast.ToInt(getOrReturnDefaultValue("REORDER_LEAD_DAYS", 3))
+	// Minutes between low-stock checks, 0 disables the job.
+	cfg.LowStockCheckInterval = cast.ToInt(getOrReturnDefaultValue("LOW_STOCK_CHECK_INTERVAL", 60))
+	cfg.LowStockWebhookURL = cast.ToString(getOrReturnDefaultValue("LOW_STOCK_WEBHOOK_URL", ""))
+	// Minutes between snapshots of remaining counts, past stock valuation and
+	// reorder suggestions need them, 0 disables the job.
+	cfg.StockSnapshotInterval = cast.ToInt(getOrReturnDefaultValue("STOCK_SNAPSHOT_INTERVAL", 60))
+
+	cfg.JWTSecret = cast.ToString(getOrReturnDefaultValue("JWT_SECRET", ""))
+	// Access token lifetime in minutes, refresh token lifetime in hours.
+	cfg.AccessTokenTTL = cast.ToInt(getOrReturnDefaultValue("ACCESS_TOKEN_TTL", 15))
+	cfg.RefreshTokenTTL = cast.ToInt(getOrReturnDefaultValue("REFRESH_TOKEN_TTL", 720))
+
+	// Requests per minute of API keys without a limit of their own, counted
+	// per process across REST and gRPC.
+	cfg.ApiKeyRateLimit = cast.ToInt(getOrReturnDefaultValue("API_KEY_RATE_LIMIT", 60))
+
+	// Hours a response is kept for replaying requests with the same Idempotency-Key.
+	cfg.IdempotencyKeyTTL = cast.ToInt(getOrReturnDefaultValue("IDEMPOTENCY_KEY_TTL", 24))
+
+	// Days deleted records are kept for restoring, 0 keeps them forever.
+	cfg.PurgeAfterDays = cast.ToInt(getOrReturnDefaultValue("PURGE_AFTER_DAYS", 30))
+
 	return cfg
 }
 
@@ -70,3 +152,27 @@ func getOrReturnDefaultValue(key string, defaultValue interface{}) interface{} {
 
 	return defaultValue
 }
+
+// Validate reports settings that can not work together. Internal barcodes are
+// EAN-13s starting with a three digit prefix, one that starts with a weight
+// or price prefix would be read back as a scale label.
+func (c *Config) Validate() error {
+
+	if c.InternalBarcodePrefixFrom < 0 || c.InternalBarcodePrefixTo > 999 || c.InternalBarcodePrefixFrom > c.InternalBarcodePrefixTo {
+		return fmt.Errorf("internal barcode prefix range %d-%d is invalid", c.InternalBarcodePrefixFrom, c.InternalBarcodePrefixTo)
+	}
+
+	embedded := append(append([]string{}, c.WeightBarcodePrefixes...), c.PriceBarcodePrefixes...)
+
+	for prefix := c.InternalBarcodePrefixFrom; prefix <= c.InternalBarcodePrefixTo; prefix++ {
+		internal := fmt.Sprintf("%03d", prefix)
+
+		for _, other := range embedded {
+			if other != "" && strings.HasPrefix(internal, other) {
+				return fmt.Errorf("internal barcode prefix %s overlaps weight or price barcode prefix %s", internal, other)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,38 @@
+package config
+
+import "testing"
+
+func TestValidate(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		from    int
+		to      int
+		weight  []string
+		price   []string
+		wantErr bool
+	}{
+		{name: "defaults", from: 200, to: 209, weight: []string{"21", "22", "23", "24", "25"}, price: []string{"26", "27", "28", "29"}},
+		{name: "range reaches a weight prefix", from: 200, to: 219, weight: []string{"21"}, wantErr: true},
+		{name: "range inside a price prefix", from: 260, to: 260, price: []string{"26"}, wantErr: true},
+		{name: "no embedded prefixes", from: 200, to: 299, weight: []string{""}, price: []string{""}},
+		{name: "reversed range", from: 209, to: 200, wantErr: true},
+		{name: "range beyond three digits", from: 900, to: 1000, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			cfg := &Config{
+				InternalBarcodePrefixFrom: tt.from,
+				InternalBarcodePrefixTo:   tt.to,
+				WeightBarcodePrefixes:     tt.weight,
+				PriceBarcodePrefixes:      tt.price,
+			}
+
+			if err := cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,204 @@
+package export
+
+import (
+	"context"
+	"strconv"
+
+	"market/api/models"
+	"market/storage"
+)
+
+type emitFunc func(item interface{}, record []string) error
+
+type definition struct {
+	columns []string
+	numeric []int
+	each    func(ctx context.Context, strg storage.StorageI, filters Filters, branchIds []string, emit emitFunc) error
+}
+
+var entities = map[string]*definition{
+	"branch": {
+		columns: []string{"id", "name", "address", "phone_number", "created_at", "updated_at"},
+		each: func(ctx context.Context, strg storage.StorageI, filters Filters, branchIds []string, emit emitFunc) error {
+			return paginate(func(offset int) (int, error) {
+				resp, err := strg.Branch().GetList(ctx, &models.BranchGetListRequest{
+					Offset:    offset,
+					Limit:     pageSize,
+					Search:    filters["search"],
+					BranchIds: branchIds,
+				})
+				if err != nil {
+					return 0, err
+				}
+
+				for _, b := range resp.Branches {
+					err = emit(b, []string{b.Id, b.Name, b.Address, b.PhoneNumber, b.CreatedAt, b.UpdatedAt})
+					if err != nil {
+						return 0, err
+					}
+				}
+
+				return len(resp.Branches), nil
+			})
+		},
+	},
+	"category": {
+		columns: []string{"id", "title", "parent_id", "created_at", "updated_at"},
+		each: func(ctx context.Context, strg storage.StorageI, filters Filters, branchIds []string, emit emitFunc) error {
+			return paginate(func(offset int) (int, error) {
+				resp, err := strg.Category().GetList(ctx, &models.CategoryGetListRequest{
+					Offset: offset,
+					Limit:  pageSize,
+					Search: filters["search"],
+				})
+				if err != nil {
+					return 0, err
+				}
+
+				for _, c := range resp.Categories {
+					err = emit(c, []string{c.Id, c.Title, c.ParentID, c.CreatedAt, c.UpdatedAt})
+					if err != nil {
+						return 0, err
+					}
+				}
+
+				return len(resp.Categories), nil
+			})
+		},
+	},
+	"product": {
+		columns: []string{"id", "name", "barcode", "price", "category_id", "parent_id", "size", "color", "volume", "created_at", "updated_at"},
+		numeric: []int{3},
+		each: func(ctx context.Context, strg storage.StorageI, filters Filters, branchIds []string, emit emitFunc) error {
+			return paginate(func(offset int) (int, error) {
+				resp, err := strg.Product().GetList(ctx, &models.ProductGetListRequest{
+					Offset:     offset,
+					Limit:      pageSize,
+					Search:     filters["search"],
+					CategoryId: filters["category_id"],
+					ParentId:   filters["parent_id"],
+				})
+				if err != nil {
+					return 0, err
+				}
+
+				for _, p := range resp.Products {
+					err = emit(p, []string{
+						p.Id, p.Name, p.Barcode, itoa(p.Price), p.CategoryId, p.ParentId,
+						p.Size, p.Color, p.Volume, p.CreatedAt, p.UpdatedAt,
+					})
+					if err != nil {
+						return 0, err
+					}
+				}
+
+				return len(resp.Products), nil
+			})
+		},
+	},
+	"storage_coming": {
+		columns: []string{"id", "coming_id", "branch_id", "status", "date_time", "created_at", "updated_at"},
+		each: func(ctx context.Context, strg storage.StorageI, filters Filters, branchIds []string, emit emitFunc) error {
+			return paginate(func(offset int) (int, error) {
+				resp, err := strg.StorageComing().GetList(ctx, &models.StorageComingGetListRequest{
+					Offset:    offset,
+					Limit:     pageSize,
+					Search:    filters["search"],
+					BranchIds: branchIds,
+				})
+				if err != nil {
+					return 0, err
+				}
+
+				for _, s := range resp.StorageComings {
+					err = emit(s, []string{s.Id, s.ComingId, s.BranchId, s.Status, s.DateTime, s.CreatedAt, s.UpdatedAt})
+					if err != nil {
+						return 0, err
+					}
+				}
+
+				return len(resp.StorageComings), nil
+			})
+		},
+	},
+	"storage_coming_product": {
+		columns: []string{"id", "storage_coming_id", "product_id", "barcode", "name", "quantity", "price", "total_price", "category_id", "batch", "expiry_date", "created_at", "updated_at"},
+		numeric: []int{5, 6, 7},
+		each: func(ctx context.Context, strg storage.StorageI, filters Filters, branchIds []string, emit emitFunc) error {
+			return paginate(func(offset int) (int, error) {
+				resp, err := strg.StorageComingProduct().GetList(ctx, &models.StorageComingProductGetListRequest{
+					Offset:          offset,
+					Limit:           pageSize,
+					Search:          filters["search"],
+					StorageComingId: filters["storage_coming_id"],
+					BranchIds:       branchIds,
+				})
+				if err != nil {
+					return 0, err
+				}
+
+				for _, s := range resp.StorageComingProducts {
+					err = emit(s, []string{
+						s.Id, s.StorageComingId, s.ProductId, s.Barcode, s.Name, itoa(s.Quantity),
+						itoa(s.Price), itoa(s.TotalPrice), s.CategoryId, s.Batch, s.ExpiryDate, s.CreatedAt, s.UpdatedAt,
+					})
+					if err != nil {
+						return 0, err
+					}
+				}
+
+				return len(resp.StorageComingProducts), nil
+			})
+		},
+	},
+	"remaining": {
+		columns: []string{"id", "branch_id", "category_id", "product_id", "barcode", "name", "count", "price", "total_price", "created_at", "updated_at"},
+		numeric: []int{6, 7, 8},
+		each: func(ctx context.Context, strg storage.StorageI, filters Filters, branchIds []string, emit emitFunc) error {
+			return paginate(func(offset int) (int, error) {
+				resp, err := strg.Remaining().GetList(ctx, &models.RemainingGetListRequest{
+					Offset:     offset,
+					Limit:      pageSize,
+					Search:     filters["search"],
+					BranchId:   filters["branch_id"],
+					CategoryId: filters["category_id"],
+					BranchIds:  branchIds,
+				})
+				if err != nil {
+					return 0, err
+				}
+
+				for _, r := range resp.Remainings {
+					err = emit(r, []string{
+						r.Id, r.BranchId, r.CategoryId, r.ProductId, r.Barcode, r.Name,
+						itoa(r.Count), itoa(r.Price), itoa(r.TotalPrice), r.CreatedAt, r.UpdatedAt,
+					})
+					if err != nil {
+						return 0, err
+					}
+				}
+
+				return len(resp.Remainings), nil
+			})
+		},
+	},
+}
+
+// paginate calls fetch with growing offsets until a short page comes back.
+func paginate(fetch func(offset int) (int, error)) error {
+
+	for offset := 0; ; offset += pageSize {
+		n, err := fetch(offset)
+		if err != nil {
+			return err
+		}
+
+		if n < pageSize {
+			return nil
+		}
+	}
+}
+
+func itoa(value int32) string {
+	return strconv.FormatInt(int64(value), 10)
+}
@@ -0,0 +1,216 @@
+package export
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"sort"
+
+	"market/pkg/xlsx"
+	"market/storage"
+)
+
+const (
+	FormatCSV    = "csv"
+	FormatXLSX   = "xlsx"
+	FormatNDJSON = "ndjson"
+)
+
+// pageSize is how many rows are read from the database at a time; only one
+// page is held in memory while the file is written.
+const pageSize = 500
+
+var (
+	ErrUnknownEntity = errors.New("unknown export entity")
+	ErrUnknownFormat = errors.New("unknown export format, expected csv, xlsx or ndjson")
+)
+
+// Filters are the list filters of the exported entity, named like the query
+// parameters of its list endpoint (search, branch_id, ...).
+type Filters map[string]string
+
+type rowWriter interface {
+	Write(item interface{}, record []string) error
+	Flush() error
+	Close() error
+}
+
+// Entities returns the names accepted by Run.
+func Entities() []string {
+
+	var names []string
+	for name := range entities {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Check reports whether entity and format can be exported, so callers can
+// answer with an error before any output is written.
+func Check(entity, format string) error {
+
+	if _, ok := entities[entity]; !ok {
+		return ErrUnknownEntity
+	}
+
+	switch format {
+	case FormatCSV, FormatXLSX, FormatNDJSON:
+		return nil
+	default:
+		return ErrUnknownFormat
+	}
+}
+
+// ContentType returns the MIME type of the given format.
+func ContentType(format string) string {
+
+	switch format {
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	case FormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	case FormatNDJSON:
+		return "application/x-ndjson"
+	default:
+		return "application/octet-stream"
+	}
+}
+
+// Run streams every row of the entity list that matches filters to w.
+// Lists of branch bound entities are limited to branchIds unless it is nil.
+func Run(ctx context.Context, strg storage.StorageI, entity, format string, filters Filters, branchIds []string, w io.Writer) error {
+
+	def, ok := entities[entity]
+	if !ok {
+		return ErrUnknownEntity
+	}
+
+	out, err := newRowWriter(w, format, entity, def)
+	if err != nil {
+		return err
+	}
+
+	var rows int
+
+	err = def.each(ctx, strg, filters, branchIds, func(item interface{}, record []string) error {
+		err := out.Write(item, record)
+		if err != nil {
+			return err
+		}
+
+		rows++
+		if rows%pageSize == 0 {
+			return out.Flush()
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return out.Close()
+}
+
+// Each calls fn with every record of entity that matches filters, reading a
+// page at a time, for callers that stream records instead of writing a file.
+func Each(ctx context.Context, strg storage.StorageI, entity string, filters Filters, branchIds []string, fn func(item interface{}) error) error {
+
+	def, ok := entities[entity]
+	if !ok {
+		return ErrUnknownEntity
+	}
+
+	return def.each(ctx, strg, filters, branchIds, func(item interface{}, _ []string) error {
+		return fn(item)
+	})
+}
+
+func newRowWriter(w io.Writer, format, entity string, def *definition) (rowWriter, error) {
+
+	switch format {
+	case FormatCSV:
+		out := &csvWriter{csv: csv.NewWriter(w), w: w}
+		return out, out.csv.Write(def.columns)
+	case FormatXLSX:
+		sheet, err := xlsx.NewWriter(w, entity)
+		if err != nil {
+			return nil, err
+		}
+
+		sheet.SetNumeric(def.numeric...)
+
+		return &xlsxWriter{sheet: sheet, w: w}, sheet.Write(def.columns)
+	case FormatNDJSON:
+		return &ndjsonWriter{enc: json.NewEncoder(w), w: w}, nil
+	default:
+		return nil, ErrUnknownFormat
+	}
+}
+
+type csvWriter struct {
+	csv *csv.Writer
+	w   io.Writer
+}
+
+func (c *csvWriter) Write(item interface{}, record []string) error {
+	return c.csv.Write(record)
+}
+
+func (c *csvWriter) Flush() error {
+	c.csv.Flush()
+	flush(c.w)
+	return c.csv.Error()
+}
+
+func (c *csvWriter) Close() error {
+	return c.Flush()
+}
+
+type xlsxWriter struct {
+	sheet *xlsx.Writer
+	w     io.Writer
+}
+
+func (x *xlsxWriter) Write(item interface{}, record []string) error {
+	return x.sheet.Write(record)
+}
+
+func (x *xlsxWriter) Flush() error {
+	err := x.sheet.Flush()
+	flush(x.w)
+	return err
+}
+
+func (x *xlsxWriter) Close() error {
+	return x.sheet.Close()
+}
+
+type ndjsonWriter struct {
+	enc *json.Encoder
+	w   io.Writer
+}
+
+func (n *ndjsonWriter) Write(item interface{}, record []string) error {
+	return n.enc.Encode(item)
+}
+
+func (n *ndjsonWriter) Flush() error {
+	flush(n.w)
+	return nil
+}
+
+func (n *ndjsonWriter) Close() error {
+	return nil
+}
+
+// flush pushes data to the client when writing to an HTTP response.
+func flush(w io.Writer) {
+	if flusher, ok := w.(interface{ Flush() }); ok {
+		flusher.Flush()
+	}
+}
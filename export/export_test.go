@@ -0,0 +1,219 @@
+package export
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"market/api/models"
+	"market/pkg/xlsx"
+	"market/storage"
+)
+
+type fakeStorage struct {
+	storage.StorageI
+	branches *fakeBranches
+}
+
+func (s *fakeStorage) Branch() storage.BranchRepoI {
+	return s.branches
+}
+
+type fakeBranches struct {
+	storage.BranchRepoI
+	all      []*models.Branch
+	requests []models.BranchGetListRequest
+}
+
+func (f *fakeBranches) GetList(ctx context.Context, req *models.BranchGetListRequest) (*models.BranchGetListResponse, error) {
+
+	f.requests = append(f.requests, *req)
+
+	var (
+		start = req.Offset
+		end   = req.Offset + req.Limit
+	)
+
+	if start > len(f.all) {
+		start = len(f.all)
+	}
+
+	if end > len(f.all) {
+		end = len(f.all)
+	}
+
+	return &models.BranchGetListResponse{Count: len(f.all), Branches: f.all[start:end]}, nil
+}
+
+// flushBuffer stands in for an HTTP response that can be flushed.
+type flushBuffer struct {
+	bytes.Buffer
+	flushes int
+}
+
+func (f *flushBuffer) Flush() {
+	f.flushes++
+}
+
+func newFakeStorage(count int) *fakeStorage {
+
+	branches := &fakeBranches{}
+
+	for i := 0; i < count; i++ {
+		branches.all = append(branches.all, &models.Branch{
+			Id:          "b" + strconv.Itoa(i+1),
+			Name:        "Branch " + strconv.Itoa(i+1),
+			Address:     "Toshkent",
+			PhoneNumber: "+998901234567",
+			CreatedAt:   "2026-10-01 09:00:00",
+		})
+	}
+
+	return &fakeStorage{branches: branches}
+}
+
+func TestRunCSV(t *testing.T) {
+
+	var (
+		strg = newFakeStorage(2)
+		buf  bytes.Buffer
+	)
+
+	strg.branches.all[0].Name = `Chilonzor, "Markaz"`
+	strg.branches.all[1].Address = "Yunusobod\n4-kvartal"
+
+	err := Run(context.Background(), strg, "branch", FormatCSV, Filters{"search": "Branch"}, []string{"b1", "b2"}, &buf)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	want := "id,name,address,phone_number,created_at,updated_at\n" +
+		`b1,"Chilonzor, ""Markaz""",Toshkent,+998901234567,2026-10-01 09:00:00,` + "\n" +
+		"b2,Branch 2,\"Yunusobod\n4-kvartal\",+998901234567,2026-10-01 09:00:00,\n"
+
+	if buf.String() != want {
+		t.Errorf("Run() =\n%s\nwant\n%s", buf.String(), want)
+	}
+
+	req := strg.branches.requests[0]
+	if req.Search != "Branch" || !reflect.DeepEqual(req.BranchIds, []string{"b1", "b2"}) {
+		t.Errorf("GetList() request = %+v, want the filters and branch ids passed on", req)
+	}
+}
+
+func TestRunNDJSON(t *testing.T) {
+
+	var (
+		strg = newFakeStorage(3)
+		buf  bytes.Buffer
+	)
+
+	err := Run(context.Background(), strg, "branch", FormatNDJSON, nil, nil, &buf)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	var (
+		scanner = bufio.NewScanner(&buf)
+		got     []*models.Branch
+	)
+
+	for scanner.Scan() {
+		var branch models.Branch
+
+		err = json.Unmarshal(scanner.Bytes(), &branch)
+		if err != nil {
+			t.Fatalf("line %q: %v", scanner.Text(), err)
+		}
+
+		got = append(got, &branch)
+	}
+
+	if !reflect.DeepEqual(got, strg.branches.all) {
+		t.Errorf("Run() lines = %+v, want %+v", got, strg.branches.all)
+	}
+}
+
+func TestRunXLSX(t *testing.T) {
+
+	var (
+		strg = newFakeStorage(2)
+		buf  bytes.Buffer
+	)
+
+	err := Run(context.Background(), strg, "branch", FormatXLSX, nil, nil, &buf)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	rows, err := xlsx.ReadAll(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("xlsx.ReadAll() error = %v", err)
+	}
+
+	if len(rows) != 3 || rows[0][1] != "name" || rows[2][1] != "Branch 2" {
+		t.Errorf("Run() rows = %q, want the header and 2 branches", rows)
+	}
+}
+
+func TestRunPages(t *testing.T) {
+
+	var (
+		strg = newFakeStorage(2*pageSize + 1)
+		out  = &flushBuffer{}
+	)
+
+	err := Run(context.Background(), strg, "branch", FormatCSV, nil, nil, out)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if got := strings.Count(out.String(), "\n"); got != 2*pageSize+2 {
+		t.Errorf("Run() wrote %d lines, want %d", got, 2*pageSize+2)
+	}
+
+	var offsets []int
+	for _, req := range strg.branches.requests {
+		offsets = append(offsets, req.Offset)
+	}
+
+	if want := []int{0, pageSize, 2 * pageSize}; !reflect.DeepEqual(offsets, want) {
+		t.Errorf("GetList() offsets = %v, want %v", offsets, want)
+	}
+
+	// Once per full page and once at the end.
+	if out.flushes != 3 {
+		t.Errorf("Run() flushed %d times, want 3", out.flushes)
+	}
+}
+
+func TestCheck(t *testing.T) {
+
+	tests := []struct {
+		entity  string
+		format  string
+		wantErr error
+	}{
+		{"product", FormatCSV, nil},
+		{"branch", FormatXLSX, nil},
+		{"storage_coming", FormatNDJSON, nil},
+		{"password", FormatCSV, ErrUnknownEntity},
+		{"product", "pdf", ErrUnknownFormat},
+	}
+
+	for _, tt := range tests {
+		if err := Check(tt.entity, tt.format); !errors.Is(err, tt.wantErr) {
+			t.Errorf("Check(%q, %q) error = %v, want %v", tt.entity, tt.format, err, tt.wantErr)
+		}
+	}
+
+	if err := Run(context.Background(), newFakeStorage(1), "branch", "pdf", nil, nil, &bytes.Buffer{}); !errors.Is(err, ErrUnknownFormat) {
+		t.Errorf("Run() error = %v, want ErrUnknownFormat", err)
+	}
+}
@@ -0,0 +1,364 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             v3.5.1-go
+// source: market_service/branch.proto
+
+package market_service
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	BranchService_Create_FullMethodName  = "/market_service.BranchService/Create"
+	BranchService_GetByID_FullMethodName = "/market_service.BranchService/GetByID"
+	BranchService_GetList_FullMethodName = "/market_service.BranchService/GetList"
+	BranchService_Update_FullMethodName  = "/market_service.BranchService/Update"
+	BranchService_Delete_FullMethodName  = "/market_service.BranchService/Delete"
+	BranchService_Restore_FullMethodName = "/market_service.BranchService/Restore"
+	BranchService_Export_FullMethodName  = "/market_service.BranchService/Export"
+)
+
+// BranchServiceClient is the client API for BranchService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type BranchServiceClient interface {
+	Create(ctx context.Context, in *CreateBranch, opts ...grpc.CallOption) (*Branch, error)
+	GetByID(ctx context.Context, in *BranchPrimaryKey, opts ...grpc.CallOption) (*Branch, error)
+	GetList(ctx context.Context, in *BranchGetListRequest, opts ...grpc.CallOption) (*BranchGetListResponse, error)
+	Update(ctx context.Context, in *UpdateBranch, opts ...grpc.CallOption) (*Branch, error)
+	Delete(ctx context.Context, in *BranchPrimaryKey, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	Restore(ctx context.Context, in *BranchPrimaryKey, opts ...grpc.CallOption) (*Branch, error)
+	// Export streams every branch matching the filters of the request, its
+	// offset and limit are ignored.
+	Export(ctx context.Context, in *BranchGetListRequest, opts ...grpc.CallOption) (BranchService_ExportClient, error)
+}
+
+type branchServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewBranchServiceClient(cc grpc.ClientConnInterface) BranchServiceClient {
+	return &branchServiceClient{cc}
+}
+
+func (c *branchServiceClient) Create(ctx context.Context, in *CreateBranch, opts ...grpc.CallOption) (*Branch, error) {
+	out := new(Branch)
+	err := c.cc.Invoke(ctx, BranchService_Create_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *branchServiceClient) GetByID(ctx context.Context, in *BranchPrimaryKey, opts ...grpc.CallOption) (*Branch, error) {
+	out := new(Branch)
+	err := c.cc.Invoke(ctx, BranchService_GetByID_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *branchServiceClient) GetList(ctx context.Context, in *BranchGetListRequest, opts ...grpc.CallOption) (*BranchGetListResponse, error) {
+	out := new(BranchGetListResponse)
+	err := c.cc.Invoke(ctx, BranchService_GetList_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *branchServiceClient) Update(ctx context.Context, in *UpdateBranch, opts ...grpc.CallOption) (*Branch, error) {
+	out := new(Branch)
+	err := c.cc.Invoke(ctx, BranchService_Update_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *branchServiceClient) Delete(ctx context.Context, in *BranchPrimaryKey, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, BranchService_Delete_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *branchServiceClient) Restore(ctx context.Context, in *BranchPrimaryKey, opts ...grpc.CallOption) (*Branch, error) {
+	out := new(Branch)
+	err := c.cc.Invoke(ctx, BranchService_Restore_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *branchServiceClient) Export(ctx context.Context, in *BranchGetListRequest, opts ...grpc.CallOption) (BranchService_ExportClient, error) {
+	stream, err := c.cc.NewStream(ctx, &BranchService_ServiceDesc.Streams[0], BranchService_Export_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &branchServiceExportClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type BranchService_ExportClient interface {
+	Recv() (*Branch, error)
+	grpc.ClientStream
+}
+
+type branchServiceExportClient struct {
+	grpc.ClientStream
+}
+
+func (x *branchServiceExportClient) Recv() (*Branch, error) {
+	m := new(Branch)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// BranchServiceServer is the server API for BranchService service.
+// All implementations must embed UnimplementedBranchServiceServer
+// for forward compatibility
+type BranchServiceServer interface {
+	Create(context.Context, *CreateBranch) (*Branch, error)
+	GetByID(context.Context, *BranchPrimaryKey) (*Branch, error)
+	GetList(context.Context, *BranchGetListRequest) (*BranchGetListResponse, error)
+	Update(context.Context, *UpdateBranch) (*Branch, error)
+	Delete(context.Context, *BranchPrimaryKey) (*emptypb.Empty, error)
+	Restore(context.Context, *BranchPrimaryKey) (*Branch, error)
+	// Export streams every branch matching the filters of the request, its
+	// offset and limit are ignored.
+	Export(*BranchGetListRequest, BranchService_ExportServer) error
+	mustEmbedUnimplementedBranchServiceServer()
+}
+
+// UnimplementedBranchServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedBranchServiceServer struct {
+}
+
+func (UnimplementedBranchServiceServer) Create(context.Context, *CreateBranch) (*Branch, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Create not implemented")
+}
+func (UnimplementedBranchServiceServer) GetByID(context.Context, *BranchPrimaryKey) (*Branch, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetByID not implemented")
+}
+func (UnimplementedBranchServiceServer) GetList(context.Context, *BranchGetListRequest) (*BranchGetListResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetList not implemented")
+}
+func (UnimplementedBranchServiceServer) Update(context.Context, *UpdateBranch) (*Branch, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Update not implemented")
+}
+func (UnimplementedBranchServiceServer) Delete(context.Context, *BranchPrimaryKey) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Delete not implemented")
+}
+func (UnimplementedBranchServiceServer) Restore(context.Context, *BranchPrimaryKey) (*Branch, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Restore not implemented")
+}
+func (UnimplementedBranchServiceServer) Export(*BranchGetListRequest, BranchService_ExportServer) error {
+	return status.Errorf(codes.Unimplemented, "method Export not implemented")
+}
+func (UnimplementedBranchServiceServer) mustEmbedUnimplementedBranchServiceServer() {}
+
+// UnsafeBranchServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to BranchServiceServer will
+// result in compilation errors.
+type UnsafeBranchServiceServer interface {
+	mustEmbedUnimplementedBranchServiceServer()
+}
+
+func RegisterBranchServiceServer(s grpc.ServiceRegistrar, srv BranchServiceServer) {
+	s.RegisterService(&BranchService_ServiceDesc, srv)
+}
+
+func _BranchService_Create_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateBranch)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BranchServiceServer).Create(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BranchService_Create_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BranchServiceServer).Create(ctx, req.(*CreateBranch))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BranchService_GetByID_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BranchPrimaryKey)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BranchServiceServer).GetByID(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BranchService_GetByID_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BranchServiceServer).GetByID(ctx, req.(*BranchPrimaryKey))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BranchService_GetList_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BranchGetListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BranchServiceServer).GetList(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BranchService_GetList_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BranchServiceServer).GetList(ctx, req.(*BranchGetListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BranchService_Update_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateBranch)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BranchServiceServer).Update(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BranchService_Update_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BranchServiceServer).Update(ctx, req.(*UpdateBranch))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BranchService_Delete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BranchPrimaryKey)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BranchServiceServer).Delete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BranchService_Delete_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BranchServiceServer).Delete(ctx, req.(*BranchPrimaryKey))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BranchService_Restore_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BranchPrimaryKey)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BranchServiceServer).Restore(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BranchService_Restore_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BranchServiceServer).Restore(ctx, req.(*BranchPrimaryKey))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BranchService_Export_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(BranchGetListRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BranchServiceServer).Export(m, &branchServiceExportServer{stream})
+}
+
+type BranchService_ExportServer interface {
+	Send(*Branch) error
+	grpc.ServerStream
+}
+
+type branchServiceExportServer struct {
+	grpc.ServerStream
+}
+
+func (x *branchServiceExportServer) Send(m *Branch) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// BranchService_ServiceDesc is the grpc.ServiceDesc for BranchService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var BranchService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "market_service.BranchService",
+	HandlerType: (*BranchServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Create",
+			Handler:    _BranchService_Create_Handler,
+		},
+		{
+			MethodName: "GetByID",
+			Handler:    _BranchService_GetByID_Handler,
+		},
+		{
+			MethodName: "GetList",
+			Handler:    _BranchService_GetList_Handler,
+		},
+		{
+			MethodName: "Update",
+			Handler:    _BranchService_Update_Handler,
+		},
+		{
+			MethodName: "Delete",
+			Handler:    _BranchService_Delete_Handler,
+		},
+		{
+			MethodName: "Restore",
+			Handler:    _BranchService_Restore_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Export",
+			Handler:       _BranchService_Export_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "market_service/branch.proto",
+}
@@ -0,0 +1,442 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             v3.5.1-go
+// source: market_service/product.proto
+
+package market_service
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	ProductService_Create_FullMethodName       = "/market_service.ProductService/Create"
+	ProductService_GetByID_FullMethodName      = "/market_service.ProductService/GetByID"
+	ProductService_GetList_FullMethodName      = "/market_service.ProductService/GetList"
+	ProductService_Update_FullMethodName       = "/market_service.ProductService/Update"
+	ProductService_Patch_FullMethodName        = "/market_service.ProductService/Patch"
+	ProductService_Delete_FullMethodName       = "/market_service.ProductService/Delete"
+	ProductService_Restore_FullMethodName      = "/market_service.ProductService/Restore"
+	ProductService_GetByBarcode_FullMethodName = "/market_service.ProductService/GetByBarcode"
+	ProductService_Export_FullMethodName       = "/market_service.ProductService/Export"
+)
+
+// ProductServiceClient is the client API for ProductService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ProductServiceClient interface {
+	Create(ctx context.Context, in *CreateProduct, opts ...grpc.CallOption) (*Product, error)
+	GetByID(ctx context.Context, in *ProductPrimaryKey, opts ...grpc.CallOption) (*Product, error)
+	GetList(ctx context.Context, in *ProductGetListRequest, opts ...grpc.CallOption) (*ProductGetListResponse, error)
+	Update(ctx context.Context, in *UpdateProduct, opts ...grpc.CallOption) (*Product, error)
+	Patch(ctx context.Context, in *PatchProduct, opts ...grpc.CallOption) (*Product, error)
+	Delete(ctx context.Context, in *ProductPrimaryKey, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	Restore(ctx context.Context, in *ProductPrimaryKey, opts ...grpc.CallOption) (*Product, error)
+	// GetByBarcode finds a product by any of its barcodes, or by the base code
+	// of a scale label with the weight or price read from the label.
+	GetByBarcode(ctx context.Context, in *ProductBarcodeLookup, opts ...grpc.CallOption) (*ProductBarcodeLookupResponse, error)
+	// Export streams every product matching the filters of the request, its
+	// offset, limit and group_variants are ignored.
+	Export(ctx context.Context, in *ProductGetListRequest, opts ...grpc.CallOption) (ProductService_ExportClient, error)
+}
+
+type productServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewProductServiceClient(cc grpc.ClientConnInterface) ProductServiceClient {
+	return &productServiceClient{cc}
+}
+
+func (c *productServiceClient) Create(ctx context.Context, in *CreateProduct, opts ...grpc.CallOption) (*Product, error) {
+	out := new(Product)
+	err := c.cc.Invoke(ctx, ProductService_Create_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) GetByID(ctx context.Context, in *ProductPrimaryKey, opts ...grpc.CallOption) (*Product, error) {
+	out := new(Product)
+	err := c.cc.Invoke(ctx, ProductService_GetByID_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) GetList(ctx context.Context, in *ProductGetListRequest, opts ...grpc.CallOption) (*ProductGetListResponse, error) {
+	out := new(ProductGetListResponse)
+	err := c.cc.Invoke(ctx, ProductService_GetList_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) Update(ctx context.Context, in *UpdateProduct, opts ...grpc.CallOption) (*Product, error) {
+	out := new(Product)
+	err := c.cc.Invoke(ctx, ProductService_Update_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) Patch(ctx context.Context, in *PatchProduct, opts ...grpc.CallOption) (*Product, error) {
+	out := new(Product)
+	err := c.cc.Invoke(ctx, ProductService_Patch_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) Delete(ctx context.Context, in *ProductPrimaryKey, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, ProductService_Delete_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) Restore(ctx context.Context, in *ProductPrimaryKey, opts ...grpc.CallOption) (*Product, error) {
+	out := new(Product)
+	err := c.cc.Invoke(ctx, ProductService_Restore_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) GetByBarcode(ctx context.Context, in *ProductBarcodeLookup, opts ...grpc.CallOption) (*ProductBarcodeLookupResponse, error) {
+	out := new(ProductBarcodeLookupResponse)
+	err := c.cc.Invoke(ctx, ProductService_GetByBarcode_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) Export(ctx context.Context, in *ProductGetListRequest, opts ...grpc.CallOption) (ProductService_ExportClient, error) {
+	stream, err := c.cc.NewStream(ctx, &ProductService_ServiceDesc.Streams[0], ProductService_Export_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &productServiceExportClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ProductService_ExportClient interface {
+	Recv() (*Product, error)
+	grpc.ClientStream
+}
+
+type productServiceExportClient struct {
+	grpc.ClientStream
+}
+
+func (x *productServiceExportClient) Recv() (*Product, error) {
+	m := new(Product)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// ProductServiceServer is the server API for ProductService service.
+// All implementations must embed UnimplementedProductServiceServer
+// for forward compatibility
+type ProductServiceServer interface {
+	Create(context.Context, *CreateProduct) (*Product, error)
+	GetByID(context.Context, *ProductPrimaryKey) (*Product, error)
+	GetList(context.Context, *ProductGetListRequest) (*ProductGetListResponse, error)
+	Update(context.Context, *UpdateProduct) (*Product, error)
+	Patch(context.Context, *PatchProduct) (*Product, error)
+	Delete(context.Context, *ProductPrimaryKey) (*emptypb.Empty, error)
+	Restore(context.Context, *ProductPrimaryKey) (*Product, error)
+	// GetByBarcode finds a product by any of its barcodes, or by the base code
+	// of a scale label with the weight or price read from the label.
+	GetByBarcode(context.Context, *ProductBarcodeLookup) (*ProductBarcodeLookupResponse, error)
+	// Export streams every product matching the filters of the request, its
+	// offset, limit and group_variants are ignored.
+	Export(*ProductGetListRequest, ProductService_ExportServer) error
+	mustEmbedUnimplementedProductServiceServer()
+}
+
+// UnimplementedProductServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedProductServiceServer struct {
+}
+
+func (UnimplementedProductServiceServer) Create(context.Context, *CreateProduct) (*Product, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Create not implemented")
+}
+func (UnimplementedProductServiceServer) GetByID(context.Context, *ProductPrimaryKey) (*Product, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetByID not implemented")
+}
+func (UnimplementedProductServiceServer) GetList(context.Context, *ProductGetListRequest) (*ProductGetListResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetList not implemented")
+}
+func (UnimplementedProductServiceServer) Update(context.Context, *UpdateProduct) (*Product, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Update not implemented")
+}
+func (UnimplementedProductServiceServer) Patch(context.Context, *PatchProduct) (*Product, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Patch not implemented")
+}
+func (UnimplementedProductServiceServer) Delete(context.Context, *ProductPrimaryKey) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Delete not implemented")
+}
+func (UnimplementedProductServiceServer) Restore(context.Context, *ProductPrimaryKey) (*Product, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Restore not implemented")
+}
+func (UnimplementedProductServiceServer) GetByBarcode(context.Context, *ProductBarcodeLookup) (*ProductBarcodeLookupResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetByBarcode not implemented")
+}
+func (UnimplementedProductServiceServer) Export(*ProductGetListRequest, ProductService_ExportServer) error {
+	return status.Errorf(codes.Unimplemented, "method Export not implemented")
+}
+func (UnimplementedProductServiceServer) mustEmbedUnimplementedProductServiceServer() {}
+
+// UnsafeProductServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ProductServiceServer will
+// result in compilation errors.
+type UnsafeProductServiceServer interface {
+	mustEmbedUnimplementedProductServiceServer()
+}
+
+func RegisterProductServiceServer(s grpc.ServiceRegistrar, srv ProductServiceServer) {
+	s.RegisterService(&ProductService_ServiceDesc, srv)
+}
+
+func _ProductService_Create_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateProduct)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).Create(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProductService_Create_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).Create(ctx, req.(*CreateProduct))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_GetByID_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ProductPrimaryKey)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).GetByID(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProductService_GetByID_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).GetByID(ctx, req.(*ProductPrimaryKey))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_GetList_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ProductGetListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).GetList(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProductService_GetList_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).GetList(ctx, req.(*ProductGetListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_Update_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateProduct)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).Update(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProductService_Update_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).Update(ctx, req.(*UpdateProduct))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_Patch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PatchProduct)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).Patch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProductService_Patch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).Patch(ctx, req.(*PatchProduct))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_Delete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ProductPrimaryKey)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).Delete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProductService_Delete_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).Delete(ctx, req.(*ProductPrimaryKey))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_Restore_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ProductPrimaryKey)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).Restore(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProductService_Restore_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).Restore(ctx, req.(*ProductPrimaryKey))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_GetByBarcode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ProductBarcodeLookup)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).GetByBarcode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProductService_GetByBarcode_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).GetByBarcode(ctx, req.(*ProductBarcodeLookup))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_Export_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ProductGetListRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ProductServiceServer).Export(m, &productServiceExportServer{stream})
+}
+
+type ProductService_ExportServer interface {
+	Send(*Product) error
+	grpc.ServerStream
+}
+
+type productServiceExportServer struct {
+	grpc.ServerStream
+}
+
+func (x *productServiceExportServer) Send(m *Product) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// ProductService_ServiceDesc is the grpc.ServiceDesc for ProductService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ProductService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "market_service.ProductService",
+	HandlerType: (*ProductServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Create",
+			Handler:    _ProductService_Create_Handler,
+		},
+		{
+			MethodName: "GetByID",
+			Handler:    _ProductService_GetByID_Handler,
+		},
+		{
+			MethodName: "GetList",
+			Handler:    _ProductService_GetList_Handler,
+		},
+		{
+			MethodName: "Update",
+			Handler:    _ProductService_Update_Handler,
+		},
+		{
+			MethodName: "Patch",
+			Handler:    _ProductService_Patch_Handler,
+		},
+		{
+			MethodName: "Delete",
+			Handler:    _ProductService_Delete_Handler,
+		},
+		{
+			MethodName: "Restore",
+			Handler:    _ProductService_Restore_Handler,
+		},
+		{
+			MethodName: "GetByBarcode",
+			Handler:    _ProductService_GetByBarcode_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Export",
+			Handler:       _ProductService_Export_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "market_service/product.proto",
+}
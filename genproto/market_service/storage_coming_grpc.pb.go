@@ -0,0 +1,405 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             v3.5.1-go
+// source: market_service/storage_coming.proto
+
+package market_service
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	StorageComingService_Create_FullMethodName     = "/market_service.StorageComingService/Create"
+	StorageComingService_GetByID_FullMethodName    = "/market_service.StorageComingService/GetByID"
+	StorageComingService_GetList_FullMethodName    = "/market_service.StorageComingService/GetList"
+	StorageComingService_Update_FullMethodName     = "/market_service.StorageComingService/Update"
+	StorageComingService_Transition_FullMethodName = "/market_service.StorageComingService/Transition"
+	StorageComingService_Delete_FullMethodName     = "/market_service.StorageComingService/Delete"
+	StorageComingService_Restore_FullMethodName    = "/market_service.StorageComingService/Restore"
+	StorageComingService_Export_FullMethodName     = "/market_service.StorageComingService/Export"
+)
+
+// StorageComingServiceClient is the client API for StorageComingService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type StorageComingServiceClient interface {
+	Create(ctx context.Context, in *CreateStorageComing, opts ...grpc.CallOption) (*StorageComing, error)
+	GetByID(ctx context.Context, in *StorageComingPrimaryKey, opts ...grpc.CallOption) (*StorageComing, error)
+	GetList(ctx context.Context, in *StorageComingGetListRequest, opts ...grpc.CallOption) (*StorageComingGetListResponse, error)
+	Update(ctx context.Context, in *UpdateStorageComing, opts ...grpc.CallOption) (*StorageComing, error)
+	// Transition moves a receipt along its lifecycle: draft, in process,
+	// received, finished or cancelled.
+	Transition(ctx context.Context, in *TransitionStorageComing, opts ...grpc.CallOption) (*StorageComing, error)
+	Delete(ctx context.Context, in *StorageComingPrimaryKey, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	Restore(ctx context.Context, in *StorageComingPrimaryKey, opts ...grpc.CallOption) (*StorageComing, error)
+	// Export streams every receipt matching the filters of the request, its
+	// offset and limit are ignored.
+	Export(ctx context.Context, in *StorageComingGetListRequest, opts ...grpc.CallOption) (StorageComingService_ExportClient, error)
+}
+
+type storageComingServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewStorageComingServiceClient(cc grpc.ClientConnInterface) StorageComingServiceClient {
+	return &storageComingServiceClient{cc}
+}
+
+func (c *storageComingServiceClient) Create(ctx context.Context, in *CreateStorageComing, opts ...grpc.CallOption) (*StorageComing, error) {
+	out := new(StorageComing)
+	err := c.cc.Invoke(ctx, StorageComingService_Create_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageComingServiceClient) GetByID(ctx context.Context, in *StorageComingPrimaryKey, opts ...grpc.CallOption) (*StorageComing, error) {
+	out := new(StorageComing)
+	err := c.cc.Invoke(ctx, StorageComingService_GetByID_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageComingServiceClient) GetList(ctx context.Context, in *StorageComingGetListRequest, opts ...grpc.CallOption) (*StorageComingGetListResponse, error) {
+	out := new(StorageComingGetListResponse)
+	err := c.cc.Invoke(ctx, StorageComingService_GetList_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageComingServiceClient) Update(ctx context.Context, in *UpdateStorageComing, opts ...grpc.CallOption) (*StorageComing, error) {
+	out := new(StorageComing)
+	err := c.cc.Invoke(ctx, StorageComingService_Update_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageComingServiceClient) Transition(ctx context.Context, in *TransitionStorageComing, opts ...grpc.CallOption) (*StorageComing, error) {
+	out := new(StorageComing)
+	err := c.cc.Invoke(ctx, StorageComingService_Transition_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageComingServiceClient) Delete(ctx context.Context, in *StorageComingPrimaryKey, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, StorageComingService_Delete_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageComingServiceClient) Restore(ctx context.Context, in *StorageComingPrimaryKey, opts ...grpc.CallOption) (*StorageComing, error) {
+	out := new(StorageComing)
+	err := c.cc.Invoke(ctx, StorageComingService_Restore_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageComingServiceClient) Export(ctx context.Context, in *StorageComingGetListRequest, opts ...grpc.CallOption) (StorageComingService_ExportClient, error) {
+	stream, err := c.cc.NewStream(ctx, &StorageComingService_ServiceDesc.Streams[0], StorageComingService_Export_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &storageComingServiceExportClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type StorageComingService_ExportClient interface {
+	Recv() (*StorageComing, error)
+	grpc.ClientStream
+}
+
+type storageComingServiceExportClient struct {
+	grpc.ClientStream
+}
+
+func (x *storageComingServiceExportClient) Recv() (*StorageComing, error) {
+	m := new(StorageComing)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// StorageComingServiceServer is the server API for StorageComingService service.
+// All implementations must embed UnimplementedStorageComingServiceServer
+// for forward compatibility
+type StorageComingServiceServer interface {
+	Create(context.Context, *CreateStorageComing) (*StorageComing, error)
+	GetByID(context.Context, *StorageComingPrimaryKey) (*StorageComing, error)
+	GetList(context.Context, *StorageComingGetListRequest) (*StorageComingGetListResponse, error)
+	Update(context.Context, *UpdateStorageComing) (*StorageComing, error)
+	// Transition moves a receipt along its lifecycle: draft, in process,
+	// received, finished or cancelled.
+	Transition(context.Context, *TransitionStorageComing) (*StorageComing, error)
+	Delete(context.Context, *StorageComingPrimaryKey) (*emptypb.Empty, error)
+	Restore(context.Context, *StorageComingPrimaryKey) (*StorageComing, error)
+	// Export streams every receipt matching the filters of the request, its
+	// offset and limit are ignored.
+	Export(*StorageComingGetListRequest, StorageComingService_ExportServer) error
+	mustEmbedUnimplementedStorageComingServiceServer()
+}
+
+// UnimplementedStorageComingServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedStorageComingServiceServer struct {
+}
+
+func (UnimplementedStorageComingServiceServer) Create(context.Context, *CreateStorageComing) (*StorageComing, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Create not implemented")
+}
+func (UnimplementedStorageComingServiceServer) GetByID(context.Context, *StorageComingPrimaryKey) (*StorageComing, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetByID not implemented")
+}
+func (UnimplementedStorageComingServiceServer) GetList(context.Context, *StorageComingGetListRequest) (*StorageComingGetListResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetList not implemented")
+}
+func (UnimplementedStorageComingServiceServer) Update(context.Context, *UpdateStorageComing) (*StorageComing, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Update not implemented")
+}
+func (UnimplementedStorageComingServiceServer) Transition(context.Context, *TransitionStorageComing) (*StorageComing, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Transition not implemented")
+}
+func (UnimplementedStorageComingServiceServer) Delete(context.Context, *StorageComingPrimaryKey) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Delete not implemented")
+}
+func (UnimplementedStorageComingServiceServer) Restore(context.Context, *StorageComingPrimaryKey) (*StorageComing, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Restore not implemented")
+}
+func (UnimplementedStorageComingServiceServer) Export(*StorageComingGetListRequest, StorageComingService_ExportServer) error {
+	return status.Errorf(codes.Unimplemented, "method Export not implemented")
+}
+func (UnimplementedStorageComingServiceServer) mustEmbedUnimplementedStorageComingServiceServer() {}
+
+// UnsafeStorageComingServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to StorageComingServiceServer will
+// result in compilation errors.
+type UnsafeStorageComingServiceServer interface {
+	mustEmbedUnimplementedStorageComingServiceServer()
+}
+
+func RegisterStorageComingServiceServer(s grpc.ServiceRegistrar, srv StorageComingServiceServer) {
+	s.RegisterService(&StorageComingService_ServiceDesc, srv)
+}
+
+func _StorageComingService_Create_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateStorageComing)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageComingServiceServer).Create(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StorageComingService_Create_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageComingServiceServer).Create(ctx, req.(*CreateStorageComing))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StorageComingService_GetByID_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StorageComingPrimaryKey)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageComingServiceServer).GetByID(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StorageComingService_GetByID_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageComingServiceServer).GetByID(ctx, req.(*StorageComingPrimaryKey))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StorageComingService_GetList_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StorageComingGetListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageComingServiceServer).GetList(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StorageComingService_GetList_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageComingServiceServer).GetList(ctx, req.(*StorageComingGetListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StorageComingService_Update_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateStorageComing)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageComingServiceServer).Update(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StorageComingService_Update_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageComingServiceServer).Update(ctx, req.(*UpdateStorageComing))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StorageComingService_Transition_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TransitionStorageComing)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageComingServiceServer).Transition(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StorageComingService_Transition_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageComingServiceServer).Transition(ctx, req.(*TransitionStorageComing))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StorageComingService_Delete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StorageComingPrimaryKey)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageComingServiceServer).Delete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StorageComingService_Delete_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageComingServiceServer).Delete(ctx, req.(*StorageComingPrimaryKey))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StorageComingService_Restore_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StorageComingPrimaryKey)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageComingServiceServer).Restore(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StorageComingService_Restore_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageComingServiceServer).Restore(ctx, req.(*StorageComingPrimaryKey))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StorageComingService_Export_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StorageComingGetListRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(StorageComingServiceServer).Export(m, &storageComingServiceExportServer{stream})
+}
+
+type StorageComingService_ExportServer interface {
+	Send(*StorageComing) error
+	grpc.ServerStream
+}
+
+type storageComingServiceExportServer struct {
+	grpc.ServerStream
+}
+
+func (x *storageComingServiceExportServer) Send(m *StorageComing) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// StorageComingService_ServiceDesc is the grpc.ServiceDesc for StorageComingService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var StorageComingService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "market_service.StorageComingService",
+	HandlerType: (*StorageComingServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Create",
+			Handler:    _StorageComingService_Create_Handler,
+		},
+		{
+			MethodName: "GetByID",
+			Handler:    _StorageComingService_GetByID_Handler,
+		},
+		{
+			MethodName: "GetList",
+			Handler:    _StorageComingService_GetList_Handler,
+		},
+		{
+			MethodName: "Update",
+			Handler:    _StorageComingService_Update_Handler,
+		},
+		{
+			MethodName: "Transition",
+			Handler:    _StorageComingService_Transition_Handler,
+		},
+		{
+			MethodName: "Delete",
+			Handler:    _StorageComingService_Delete_Handler,
+		},
+		{
+			MethodName: "Restore",
+			Handler:    _StorageComingService_Restore_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Export",
+			Handler:       _StorageComingService_Export_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "market_service/storage_coming.proto",
+}
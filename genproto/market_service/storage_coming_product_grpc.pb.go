@@ -0,0 +1,365 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             v3.5.1-go
+// source: market_service/storage_coming_product.proto
+
+package market_service
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	StorageComingProductService_Create_FullMethodName  = "/market_service.StorageComingProductService/Create"
+	StorageComingProductService_GetByID_FullMethodName = "/market_service.StorageComingProductService/GetByID"
+	StorageComingProductService_GetList_FullMethodName = "/market_service.StorageComingProductService/GetList"
+	StorageComingProductService_Update_FullMethodName  = "/market_service.StorageComingProductService/Update"
+	StorageComingProductService_Delete_FullMethodName  = "/market_service.StorageComingProductService/Delete"
+	StorageComingProductService_Restore_FullMethodName = "/market_service.StorageComingProductService/Restore"
+	StorageComingProductService_Export_FullMethodName  = "/market_service.StorageComingProductService/Export"
+)
+
+// StorageComingProductServiceClient is the client API for StorageComingProductService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type StorageComingProductServiceClient interface {
+	Create(ctx context.Context, in *CreateStorageComingProduct, opts ...grpc.CallOption) (*StorageComingProduct, error)
+	GetByID(ctx context.Context, in *StorageComingProductPrimaryKey, opts ...grpc.CallOption) (*StorageComingProduct, error)
+	GetList(ctx context.Context, in *StorageComingProductGetListRequest, opts ...grpc.CallOption) (*StorageComingProductGetListResponse, error)
+	Update(ctx context.Context, in *UpdateStorageComingProduct, opts ...grpc.CallOption) (*StorageComingProduct, error)
+	Delete(ctx context.Context, in *StorageComingProductPrimaryKey, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	Restore(ctx context.Context, in *StorageComingProductPrimaryKey, opts ...grpc.CallOption) (*StorageComingProduct, error)
+	// Export streams every line matching the filters of the request, its
+	// offset and limit are ignored.
+	Export(ctx context.Context, in *StorageComingProductGetListRequest, opts ...grpc.CallOption) (StorageComingProductService_ExportClient, error)
+}
+
+type storageComingProductServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewStorageComingProductServiceClient(cc grpc.ClientConnInterface) StorageComingProductServiceClient {
+	return &storageComingProductServiceClient{cc}
+}
+
+func (c *storageComingProductServiceClient) Create(ctx context.Context, in *CreateStorageComingProduct, opts ...grpc.CallOption) (*StorageComingProduct, error) {
+	out := new(StorageComingProduct)
+	err := c.cc.Invoke(ctx, StorageComingProductService_Create_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageComingProductServiceClient) GetByID(ctx context.Context, in *StorageComingProductPrimaryKey, opts ...grpc.CallOption) (*StorageComingProduct, error) {
+	out := new(StorageComingProduct)
+	err := c.cc.Invoke(ctx, StorageComingProductService_GetByID_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageComingProductServiceClient) GetList(ctx context.Context, in *StorageComingProductGetListRequest, opts ...grpc.CallOption) (*StorageComingProductGetListResponse, error) {
+	out := new(StorageComingProductGetListResponse)
+	err := c.cc.Invoke(ctx, StorageComingProductService_GetList_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageComingProductServiceClient) Update(ctx context.Context, in *UpdateStorageComingProduct, opts ...grpc.CallOption) (*StorageComingProduct, error) {
+	out := new(StorageComingProduct)
+	err := c.cc.Invoke(ctx, StorageComingProductService_Update_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageComingProductServiceClient) Delete(ctx context.Context, in *StorageComingProductPrimaryKey, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, StorageComingProductService_Delete_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageComingProductServiceClient) Restore(ctx context.Context, in *StorageComingProductPrimaryKey, opts ...grpc.CallOption) (*StorageComingProduct, error) {
+	out := new(StorageComingProduct)
+	err := c.cc.Invoke(ctx, StorageComingProductService_Restore_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageComingProductServiceClient) Export(ctx context.Context, in *StorageComingProductGetListRequest, opts ...grpc.CallOption) (StorageComingProductService_ExportClient, error) {
+	stream, err := c.cc.NewStream(ctx, &StorageComingProductService_ServiceDesc.Streams[0], StorageComingProductService_Export_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &storageComingProductServiceExportClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type StorageComingProductService_ExportClient interface {
+	Recv() (*StorageComingProduct, error)
+	grpc.ClientStream
+}
+
+type storageComingProductServiceExportClient struct {
+	grpc.ClientStream
+}
+
+func (x *storageComingProductServiceExportClient) Recv() (*StorageComingProduct, error) {
+	m := new(StorageComingProduct)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// StorageComingProductServiceServer is the server API for StorageComingProductService service.
+// All implementations must embed UnimplementedStorageComingProductServiceServer
+// for forward compatibility
+type StorageComingProductServiceServer interface {
+	Create(context.Context, *CreateStorageComingProduct) (*StorageComingProduct, error)
+	GetByID(context.Context, *StorageComingProductPrimaryKey) (*StorageComingProduct, error)
+	GetList(context.Context, *StorageComingProductGetListRequest) (*StorageComingProductGetListResponse, error)
+	Update(context.Context, *UpdateStorageComingProduct) (*StorageComingProduct, error)
+	Delete(context.Context, *StorageComingProductPrimaryKey) (*emptypb.Empty, error)
+	Restore(context.Context, *StorageComingProductPrimaryKey) (*StorageComingProduct, error)
+	// Export streams every line matching the filters of the request, its
+	// offset and limit are ignored.
+	Export(*StorageComingProductGetListRequest, StorageComingProductService_ExportServer) error
+	mustEmbedUnimplementedStorageComingProductServiceServer()
+}
+
+// UnimplementedStorageComingProductServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedStorageComingProductServiceServer struct {
+}
+
+func (UnimplementedStorageComingProductServiceServer) Create(context.Context, *CreateStorageComingProduct) (*StorageComingProduct, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Create not implemented")
+}
+func (UnimplementedStorageComingProductServiceServer) GetByID(context.Context, *StorageComingProductPrimaryKey) (*StorageComingProduct, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetByID not implemented")
+}
+func (UnimplementedStorageComingProductServiceServer) GetList(context.Context, *StorageComingProductGetListRequest) (*StorageComingProductGetListResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetList not implemented")
+}
+func (UnimplementedStorageComingProductServiceServer) Update(context.Context, *UpdateStorageComingProduct) (*StorageComingProduct, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Update not implemented")
+}
+func (UnimplementedStorageComingProductServiceServer) Delete(context.Context, *StorageComingProductPrimaryKey) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Delete not implemented")
+}
+func (UnimplementedStorageComingProductServiceServer) Restore(context.Context, *StorageComingProductPrimaryKey) (*StorageComingProduct, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Restore not implemented")
+}
+func (UnimplementedStorageComingProductServiceServer) Export(*StorageComingProductGetListRequest, StorageComingProductService_ExportServer) error {
+	return status.Errorf(codes.Unimplemented, "method Export not implemented")
+}
+func (UnimplementedStorageComingProductServiceServer) mustEmbedUnimplementedStorageComingProductServiceServer() {
+}
+
+// UnsafeStorageComingProductServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to StorageComingProductServiceServer will
+// result in compilation errors.
+type UnsafeStorageComingProductServiceServer interface {
+	mustEmbedUnimplementedStorageComingProductServiceServer()
+}
+
+func RegisterStorageComingProductServiceServer(s grpc.ServiceRegistrar, srv StorageComingProductServiceServer) {
+	s.RegisterService(&StorageComingProductService_ServiceDesc, srv)
+}
+
+func _StorageComingProductService_Create_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateStorageComingProduct)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageComingProductServiceServer).Create(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StorageComingProductService_Create_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageComingProductServiceServer).Create(ctx, req.(*CreateStorageComingProduct))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StorageComingProductService_GetByID_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StorageComingProductPrimaryKey)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageComingProductServiceServer).GetByID(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StorageComingProductService_GetByID_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageComingProductServiceServer).GetByID(ctx, req.(*StorageComingProductPrimaryKey))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StorageComingProductService_GetList_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StorageComingProductGetListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageComingProductServiceServer).GetList(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StorageComingProductService_GetList_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageComingProductServiceServer).GetList(ctx, req.(*StorageComingProductGetListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StorageComingProductService_Update_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateStorageComingProduct)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageComingProductServiceServer).Update(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StorageComingProductService_Update_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageComingProductServiceServer).Update(ctx, req.(*UpdateStorageComingProduct))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StorageComingProductService_Delete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StorageComingProductPrimaryKey)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageComingProductServiceServer).Delete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StorageComingProductService_Delete_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageComingProductServiceServer).Delete(ctx, req.(*StorageComingProductPrimaryKey))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StorageComingProductService_Restore_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StorageComingProductPrimaryKey)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageComingProductServiceServer).Restore(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StorageComingProductService_Restore_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageComingProductServiceServer).Restore(ctx, req.(*StorageComingProductPrimaryKey))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StorageComingProductService_Export_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StorageComingProductGetListRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(StorageComingProductServiceServer).Export(m, &storageComingProductServiceExportServer{stream})
+}
+
+type StorageComingProductService_ExportServer interface {
+	Send(*StorageComingProduct) error
+	grpc.ServerStream
+}
+
+type storageComingProductServiceExportServer struct {
+	grpc.ServerStream
+}
+
+func (x *storageComingProductServiceExportServer) Send(m *StorageComingProduct) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// StorageComingProductService_ServiceDesc is the grpc.ServiceDesc for StorageComingProductService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var StorageComingProductService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "market_service.StorageComingProductService",
+	HandlerType: (*StorageComingProductServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Create",
+			Handler:    _StorageComingProductService_Create_Handler,
+		},
+		{
+			MethodName: "GetByID",
+			Handler:    _StorageComingProductService_GetByID_Handler,
+		},
+		{
+			MethodName: "GetList",
+			Handler:    _StorageComingProductService_GetList_Handler,
+		},
+		{
+			MethodName: "Update",
+			Handler:    _StorageComingProductService_Update_Handler,
+		},
+		{
+			MethodName: "Delete",
+			Handler:    _StorageComingProductService_Delete_Handler,
+		},
+		{
+			MethodName: "Restore",
+			Handler:    _StorageComingProductService_Restore_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Export",
+			Handler:       _StorageComingProductService_Export_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "market_service/storage_coming_product.proto",
+}
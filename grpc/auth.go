@@ -0,0 +1,193 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"market/api/models"
+	"market/config"
+	"market/pkg/audit"
+	"market/pkg/logger"
+	"market/pkg/ratelimit"
+	"market/pkg/rbac"
+	"market/pkg/security"
+	"market/storage"
+)
+
+// serviceEntities maps the services of market_service to the entities their
+// API key scopes are granted on.
+var serviceEntities = map[string]string{
+	"market_service.BranchService":               "branch",
+	"market_service.CategoryService":             "category",
+	"market_service.ProductService":              "product",
+	"market_service.StorageComingService":        "storage_coming",
+	"market_service.StorageComingProductService": "storage_coming_product",
+}
+
+// authorizer checks the x-api-key metadata of every call the way the REST
+// API checks the X-API-Key header. Reflection is left open so clients can
+// list the services before they have a key.
+type authorizer struct {
+	cfg     *config.Config
+	strg    storage.StorageI
+	log     logger.LoggerI
+	limiter *ratelimit.Limiter
+}
+
+func newAuthorizer(cfg *config.Config, strg storage.StorageI, limiter *ratelimit.Limiter, log logger.LoggerI) *authorizer {
+	return &authorizer{
+		cfg:     cfg,
+		strg:    strg,
+		log:     log,
+		limiter: limiter,
+	}
+}
+
+func (a *authorizer) unary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+
+	ctx, err := a.authorize(ctx, info.FullMethod)
+	if err != nil {
+		a.log.Info(info.FullMethod, logger.Error(err))
+		return nil, err
+	}
+
+	resp, err := handler(ctx, req)
+	a.logResult(info.FullMethod, err)
+
+	return resp, err
+}
+
+func (a *authorizer) stream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+
+	ctx, err := a.authorize(ss.Context(), info.FullMethod)
+	if err != nil {
+		a.log.Info(info.FullMethod, logger.Error(err))
+		return err
+	}
+
+	err = handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+	a.logResult(info.FullMethod, err)
+
+	return err
+}
+
+func (a *authorizer) authorize(ctx context.Context, fullMethod string) (context.Context, error) {
+
+	service, method := splitMethod(fullMethod)
+
+	entity, ok := serviceEntities[service]
+	if !ok {
+		return ctx, nil
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	requestId := first(md.Get("x-request-id"))
+	if requestId == "" || len(requestId) > 64 {
+		requestId = uuid.New().String()
+	}
+	ctx = audit.WithRequestId(ctx, requestId)
+
+	key := first(md.Get("x-api-key"))
+	if key == "" {
+		return nil, status.Error(codes.Unauthenticated, "x-api-key metadata is required")
+	}
+
+	apiKey, err := a.strg.ApiKey().GetByHash(ctx, &models.ApiKeyHash{KeyHash: security.HashToken(key)})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, status.Error(codes.Unauthenticated, "invalid api key")
+	} else if err != nil {
+		a.log.Error("storage.api_key.getByHash", logger.Error(err))
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	limit := apiKey.RateLimit
+	if limit <= 0 {
+		limit = a.cfg.ApiKeyRateLimit
+	}
+
+	allowed, retryAfter := a.limiter.Allow(apiKey.Id, limit)
+	if !allowed {
+		return nil, status.Error(codes.ResourceExhausted, "rate limit of "+strconv.Itoa(limit)+" requests per minute exceeded, retry in "+strconv.Itoa(int(math.Ceil(retryAfter.Seconds())))+"s")
+	}
+
+	err = a.strg.ApiKey().Touch(ctx, &models.ApiKeyPrimaryKey{Id: apiKey.Id})
+	if err != nil {
+		a.log.Error("storage.api_key.touch", logger.Error(err))
+	}
+
+	if !rbac.ScopeAllowed(apiKey.Scopes, entity, methodAction(method)) {
+		return nil, status.Error(codes.PermissionDenied, "api key scopes do not allow this")
+	}
+
+	return audit.WithActor(ctx, audit.Actor{Type: audit.ActorApiKey, Id: apiKey.Id}), nil
+}
+
+func (a *authorizer) logResult(fullMethod string, err error) {
+
+	switch status.Code(err) {
+	case codes.OK:
+		a.log.Info(fullMethod)
+	case codes.Internal, codes.Unknown:
+		a.log.Error(fullMethod, logger.Error(err))
+	default:
+		a.log.Warn(fullMethod, logger.Error(err))
+	}
+}
+
+// methodAction is the rbac action of an RPC. Restoring needs the same right
+// as deleting, as it does over REST.
+func methodAction(method string) string {
+
+	switch {
+	case strings.HasPrefix(method, "Get"), method == "Export":
+		return rbac.Read
+	case method == "Delete", method == "Restore":
+		return rbac.Delete
+	}
+
+	return rbac.Write
+}
+
+// splitMethod splits /market_service.BranchService/GetByID into the service
+// and the method.
+func splitMethod(fullMethod string) (string, string) {
+
+	fullMethod = strings.TrimPrefix(fullMethod, "/")
+
+	i := strings.LastIndex(fullMethod, "/")
+	if i < 0 {
+		return fullMethod, ""
+	}
+
+	return fullMethod[:i], fullMethod[i+1:]
+}
+
+func first(values []string) string {
+
+	if len(values) <= 0 {
+		return ""
+	}
+
+	return values[0]
+}
+
+// serverStream carries the context of an authorized call to stream handlers.
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
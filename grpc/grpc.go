@@ -0,0 +1,45 @@
+// Package grpc serves the storage repos over gRPC, next to the REST API and
+// with the same rules: API keys, scopes, validation and errors.
+package grpc
+
+import (
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
+
+	"market/config"
+	"market/genproto/market_service"
+	"market/grpc/service"
+	"market/pkg/helper"
+	"market/pkg/logger"
+	"market/pkg/ratelimit"
+	"market/storage"
+)
+
+// SetUpServer builds the gRPC server. API key calls count against limiter,
+// shared with the REST API.
+func SetUpServer(cfg *config.Config, strg storage.StorageI, limiter *ratelimit.Limiter, log logger.LoggerI) *grpc.Server {
+
+	// Requests are checked with the binding rules of api/models, as in gin.
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		helper.RegisterValidations(v)
+	}
+
+	auth := newAuthorizer(cfg, strg, limiter, log)
+
+	grpcServer := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(auth.unary),
+		grpc.ChainStreamInterceptor(auth.stream),
+	)
+
+	market_service.RegisterBranchServiceServer(grpcServer, service.NewBranchService(cfg, log, strg))
+	market_service.RegisterCategoryServiceServer(grpcServer, service.NewCategoryService(cfg, log, strg))
+	market_service.RegisterProductServiceServer(grpcServer, service.NewProductService(cfg, log, strg))
+	market_service.RegisterStorageComingServiceServer(grpcServer, service.NewStorageComingService(cfg, log, strg))
+	market_service.RegisterStorageComingProductServiceServer(grpcServer, service.NewStorageComingProductService(cfg, log, strg))
+
+	reflection.Register(grpcServer)
+
+	return grpcServer
+}
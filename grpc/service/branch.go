@@ -0,0 +1,183 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"market/api/models"
+	"market/config"
+	"market/export"
+	"market/genproto/market_service"
+	"market/pkg/logger"
+	"market/storage"
+)
+
+type BranchService struct {
+	cfg  *config.Config
+	log  logger.LoggerI
+	strg storage.StorageI
+	market_service.UnimplementedBranchServiceServer
+}
+
+func NewBranchService(cfg *config.Config, log logger.LoggerI, strg storage.StorageI) *BranchService {
+	return &BranchService{
+		cfg:  cfg,
+		log:  log,
+		strg: strg,
+	}
+}
+
+func (b *BranchService) Create(ctx context.Context, req *market_service.CreateBranch) (*market_service.Branch, error) {
+
+	createBranch := models.CreateBranch{
+		Name:        req.GetName(),
+		Address:     req.GetAddress(),
+		PhoneNumber: req.GetPhoneNumber(),
+	}
+
+	err := validate(&createBranch)
+	if err != nil {
+		return nil, err
+	}
+
+	return idempotent(ctx, b.cfg, b.log, b.strg, req, &market_service.Branch{}, func() (*market_service.Branch, error) {
+
+		id, err := b.strg.Branch().Create(ctx, &createBranch)
+		if err != nil {
+			return nil, statusError(err)
+		}
+
+		return b.get(ctx, id)
+	})
+}
+
+func (b *BranchService) GetByID(ctx context.Context, req *market_service.BranchPrimaryKey) (*market_service.Branch, error) {
+
+	err := checkIncludeDeleted(req.GetIncludeDeleted())
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := b.strg.Branch().GetByID(ctx, &models.BranchPrimaryKey{Id: req.GetId()})
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	return branchToProto(resp), nil
+}
+
+func (b *BranchService) GetList(ctx context.Context, req *market_service.BranchGetListRequest) (*market_service.BranchGetListResponse, error) {
+
+	err := checkIncludeDeleted(req.GetIncludeDeleted())
+	if err != nil {
+		return nil, err
+	}
+
+	offset, limit := page(b.cfg, req.GetOffset(), req.GetLimit())
+
+	resp, err := b.strg.Branch().GetList(ctx, &models.BranchGetListRequest{
+		Offset: offset,
+		Limit:  limit,
+		Search: req.GetSearch(),
+	})
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	branches := make([]*market_service.Branch, 0, len(resp.Branches))
+	for _, branch := range resp.Branches {
+		branches = append(branches, branchToProto(branch))
+	}
+
+	return &market_service.BranchGetListResponse{
+		Count:    int64(resp.Count),
+		Branches: branches,
+	}, nil
+}
+
+func (b *BranchService) Update(ctx context.Context, req *market_service.UpdateBranch) (*market_service.Branch, error) {
+
+	updateBranch := models.UpdateBranch{
+		Id:          req.GetId(),
+		Name:        req.GetName(),
+		Address:     req.GetAddress(),
+		PhoneNumber: req.GetPhoneNumber(),
+		Version:     int(req.GetVersion()),
+	}
+
+	err := validate(&updateBranch)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = b.strg.Branch().Update(ctx, &updateBranch)
+	if errors.Is(err, storage.ErrVersionConflict) {
+		return nil, versionConflict(ctx, err, b.get, updateBranch.Id)
+	} else if err != nil {
+		return nil, statusError(err)
+	}
+
+	return b.get(ctx, updateBranch.Id)
+}
+
+func (b *BranchService) Delete(ctx context.Context, req *market_service.BranchPrimaryKey) (*emptypb.Empty, error) {
+
+	err := b.strg.Branch().Delete(ctx, &models.BranchPrimaryKey{Id: req.GetId()})
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (b *BranchService) Restore(ctx context.Context, req *market_service.BranchPrimaryKey) (*market_service.Branch, error) {
+
+	_, err := b.strg.Branch().Restore(ctx, &models.BranchPrimaryKey{Id: req.GetId()})
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	return b.get(ctx, req.GetId())
+}
+
+func (b *BranchService) Export(req *market_service.BranchGetListRequest, stream market_service.BranchService_ExportServer) error {
+
+	err := checkIncludeDeleted(req.GetIncludeDeleted())
+	if err != nil {
+		return err
+	}
+
+	err = export.Each(stream.Context(), b.strg, "branch", export.Filters{"search": req.GetSearch()}, nil, func(item interface{}) error {
+		return stream.Send(branchToProto(item.(*models.Branch)))
+	})
+	if err != nil {
+		return statusError(err)
+	}
+
+	return nil
+}
+
+func (b *BranchService) get(ctx context.Context, id string) (*market_service.Branch, error) {
+
+	resp, err := b.strg.Branch().GetByID(ctx, &models.BranchPrimaryKey{Id: id})
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	return branchToProto(resp), nil
+}
+
+func branchToProto(branch *models.Branch) *market_service.Branch {
+	return &market_service.Branch{
+		Id:          branch.Id,
+		Name:        branch.Name,
+		Address:     branch.Address,
+		PhoneNumber: branch.PhoneNumber,
+		Version:     int64(branch.Version),
+		CreatedAt:   branch.CreatedAt,
+		UpdatedAt:   branch.UpdatedAt,
+		DeletedAt:   branch.DeletedAt,
+	}
+}
@@ -0,0 +1,180 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"market/api/models"
+	"market/config"
+	"market/export"
+	"market/genproto/market_service"
+	"market/pkg/logger"
+	"market/storage"
+)
+
+type CategoryService struct {
+	cfg  *config.Config
+	log  logger.LoggerI
+	strg storage.StorageI
+	market_service.UnimplementedCategoryServiceServer
+}
+
+func NewCategoryService(cfg *config.Config, log logger.LoggerI, strg storage.StorageI) *CategoryService {
+	return &CategoryService{
+		cfg:  cfg,
+		log:  log,
+		strg: strg,
+	}
+}
+
+func (c *CategoryService) Create(ctx context.Context, req *market_service.CreateCategory) (*market_service.Category, error) {
+
+	createCategory := models.CreateCategory{
+		Title:    req.GetTitle(),
+		ParentID: req.GetParentId(),
+	}
+
+	err := validate(&createCategory)
+	if err != nil {
+		return nil, err
+	}
+
+	return idempotent(ctx, c.cfg, c.log, c.strg, req, &market_service.Category{}, func() (*market_service.Category, error) {
+
+		id, err := c.strg.Category().Create(ctx, &createCategory)
+		if err != nil {
+			return nil, statusError(err)
+		}
+
+		return c.get(ctx, id)
+	})
+}
+
+func (c *CategoryService) GetByID(ctx context.Context, req *market_service.CategoryPrimaryKey) (*market_service.Category, error) {
+
+	err := checkIncludeDeleted(req.GetIncludeDeleted())
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.strg.Category().GetByID(ctx, &models.CategoryPrimaryKey{Id: req.GetId()})
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	return categoryToProto(resp), nil
+}
+
+func (c *CategoryService) GetList(ctx context.Context, req *market_service.CategoryGetListRequest) (*market_service.CategoryGetListResponse, error) {
+
+	err := checkIncludeDeleted(req.GetIncludeDeleted())
+	if err != nil {
+		return nil, err
+	}
+
+	offset, limit := page(c.cfg, req.GetOffset(), req.GetLimit())
+
+	resp, err := c.strg.Category().GetList(ctx, &models.CategoryGetListRequest{
+		Offset: offset,
+		Limit:  limit,
+		Search: req.GetSearch(),
+	})
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	categories := make([]*market_service.Category, 0, len(resp.Categories))
+	for _, category := range resp.Categories {
+		categories = append(categories, categoryToProto(category))
+	}
+
+	return &market_service.CategoryGetListResponse{
+		Count:      int64(resp.Count),
+		Categories: categories,
+	}, nil
+}
+
+func (c *CategoryService) Update(ctx context.Context, req *market_service.UpdateCategory) (*market_service.Category, error) {
+
+	updateCategory := models.UpdateCategory{
+		Id:       req.GetId(),
+		Title:    req.GetTitle(),
+		ParentID: req.GetParentId(),
+		Version:  int(req.GetVersion()),
+	}
+
+	err := validate(&updateCategory)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = c.strg.Category().Update(ctx, &updateCategory)
+	if errors.Is(err, storage.ErrVersionConflict) {
+		return nil, versionConflict(ctx, err, c.get, updateCategory.Id)
+	} else if err != nil {
+		return nil, statusError(err)
+	}
+
+	return c.get(ctx, updateCategory.Id)
+}
+
+func (c *CategoryService) Delete(ctx context.Context, req *market_service.CategoryPrimaryKey) (*emptypb.Empty, error) {
+
+	err := c.strg.Category().Delete(ctx, &models.CategoryPrimaryKey{Id: req.GetId()})
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (c *CategoryService) Restore(ctx context.Context, req *market_service.CategoryPrimaryKey) (*market_service.Category, error) {
+
+	_, err := c.strg.Category().Restore(ctx, &models.CategoryPrimaryKey{Id: req.GetId()})
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	return c.get(ctx, req.GetId())
+}
+
+func (c *CategoryService) Export(req *market_service.CategoryGetListRequest, stream market_service.CategoryService_ExportServer) error {
+
+	err := checkIncludeDeleted(req.GetIncludeDeleted())
+	if err != nil {
+		return err
+	}
+
+	err = export.Each(stream.Context(), c.strg, "category", export.Filters{"search": req.GetSearch()}, nil, func(item interface{}) error {
+		return stream.Send(categoryToProto(item.(*models.Category)))
+	})
+	if err != nil {
+		return statusError(err)
+	}
+
+	return nil
+}
+
+func (c *CategoryService) get(ctx context.Context, id string) (*market_service.Category, error) {
+
+	resp, err := c.strg.Category().GetByID(ctx, &models.CategoryPrimaryKey{Id: id})
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	return categoryToProto(resp), nil
+}
+
+func categoryToProto(category *models.Category) *market_service.Category {
+	return &market_service.Category{
+		Id:        category.Id,
+		Title:     category.Title,
+		ParentId:  category.ParentID,
+		Version:   int64(category.Version),
+		CreatedAt: category.CreatedAt,
+		UpdatedAt: category.UpdatedAt,
+		DeletedAt: category.DeletedAt,
+	}
+}
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"market/api/models"
+	"market/config"
+	"market/pkg/audit"
+	"market/pkg/logger"
+	"market/storage"
+)
+
+const protobufContentType = "application/x-protobuf"
+
+// idempotent runs call once for each idempotency-key metadata value of the
+// caller, as the Idempotent middleware of api/handler does for REST
+// requests. A retry with the same key and request gets the response of the
+// first call back, with idempotent-replayed metadata. Failed calls free the
+// key, so they can be retried. Calls without the metadata just run.
+func idempotent[T proto.Message](ctx context.Context, cfg *config.Config, log logger.LoggerI, strg storage.StorageI, req proto.Message, replay T, call func() (T, error)) (T, error) {
+
+	var (
+		zero T
+		keys = metadata.ValueFromIncomingContext(ctx, "idempotency-key")
+	)
+
+	if len(keys) <= 0 || keys[0] == "" {
+		return call()
+	}
+
+	if len(keys[0]) > 255 {
+		return zero, status.Error(codes.InvalidArgument, "invalid idempotency-key metadata, at most 255 characters")
+	}
+
+	body, err := proto.MarshalOptions{Deterministic: true}.Marshal(req)
+	if err != nil {
+		return zero, status.Error(codes.Internal, err.Error())
+	}
+
+	method, _ := grpc.Method(ctx)
+
+	hash := sha256.New()
+	hash.Write([]byte(method + "\n"))
+	hash.Write(body)
+
+	var (
+		requestHash = hex.EncodeToString(hash.Sum(nil))
+		actor       = audit.ActorFrom(ctx)
+		id          = models.IdempotencyKeyPrimaryKey{Key: keys[0], ActorId: actor.Type + ":" + actor.Id}
+	)
+
+	stored, reserved, err := strg.IdempotencyKey().Reserve(ctx, &models.CreateIdempotencyKey{
+		Key:         id.Key,
+		ActorId:     id.ActorId,
+		RequestHash: requestHash,
+		TTL:         cfg.IdempotencyKeyTTL,
+	})
+	if err != nil {
+		return zero, statusError(err)
+	}
+
+	if !reserved {
+		switch {
+		case stored.RequestHash != requestHash:
+			return zero, status.Error(codes.InvalidArgument, "idempotency-key was already used for a different request")
+		case stored.ResponseCode == 0:
+			return zero, status.Error(codes.Aborted, "a request with this idempotency-key is still in progress")
+		}
+
+		err = proto.Unmarshal(stored.ResponseBody, replay)
+		if err != nil {
+			return zero, status.Error(codes.Internal, err.Error())
+		}
+
+		_ = grpc.SetHeader(ctx, metadata.Pairs("idempotent-replayed", "true"))
+
+		return replay, nil
+	}
+
+	var saved bool
+
+	// The key would stay reserved until it expires when the call fails,
+	// panics or its response cannot be stored.
+	defer func() {
+		if saved {
+			return
+		}
+
+		err := strg.IdempotencyKey().Release(context.Background(), &id)
+		if err != nil {
+			log.Error("storage.idempotency_key.release", logger.Error(err))
+		}
+	}()
+
+	resp, err := call()
+	if err != nil {
+		return zero, err
+	}
+
+	body, err = proto.Marshal(resp)
+	if err != nil {
+		log.Error("idempotency_key.marshal", logger.Error(err))
+		return resp, nil
+	}
+
+	// The client may be gone by now, which is when the key matters most.
+	err = strg.IdempotencyKey().Save(context.Background(), &models.SaveIdempotencyKey{
+		Key:          id.Key,
+		ActorId:      id.ActorId,
+		ResponseCode: http.StatusOK,
+		ResponseType: protobufContentType,
+		ResponseBody: body,
+	})
+	if err != nil {
+		log.Error("storage.idempotency_key.save", logger.Error(err))
+		return resp, nil
+	}
+
+	saved = true
+
+	return resp, nil
+}
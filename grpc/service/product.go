@@ -0,0 +1,336 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/jackc/pgx/v4"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"market/api/models"
+	"market/config"
+	"market/export"
+	"market/genproto/market_service"
+	"market/pkg/barcode"
+	"market/pkg/helper"
+	"market/pkg/logger"
+	"market/storage"
+)
+
+type ProductService struct {
+	cfg  *config.Config
+	log  logger.LoggerI
+	strg storage.StorageI
+	market_service.UnimplementedProductServiceServer
+}
+
+func NewProductService(cfg *config.Config, log logger.LoggerI, strg storage.StorageI) *ProductService {
+	return &ProductService{
+		cfg:  cfg,
+		log:  log,
+		strg: strg,
+	}
+}
+
+func (p *ProductService) Create(ctx context.Context, req *market_service.CreateProduct) (*market_service.Product, error) {
+
+	createProduct := models.CreateProduct{
+		Name:       req.GetName(),
+		Barcode:    req.GetBarcode(),
+		Price:      req.GetPrice(),
+		CategoryId: req.GetCategoryId(),
+		ParentId:   req.GetParentId(),
+		Size:       req.GetSize(),
+		Color:      req.GetColor(),
+		Volume:     req.GetVolume(),
+		Serialized: req.GetSerialized(),
+	}
+
+	for _, productBarcode := range req.GetBarcodes() {
+		createProduct.Barcodes = append(createProduct.Barcodes, &models.CreateProductBarcode{
+			Barcode:  productBarcode.GetBarcode(),
+			Type:     productBarcode.GetType(),
+			Quantity: productBarcode.GetQuantity(),
+		})
+	}
+
+	err := validate(&createProduct)
+	if err != nil {
+		return nil, err
+	}
+
+	return idempotent(ctx, p.cfg, p.log, p.strg, req, &market_service.Product{}, func() (*market_service.Product, error) {
+
+		id, err := p.strg.Product().Create(ctx, &createProduct)
+		if err != nil {
+			return nil, statusError(err)
+		}
+
+		return p.get(ctx, id)
+	})
+}
+
+func (p *ProductService) GetByID(ctx context.Context, req *market_service.ProductPrimaryKey) (*market_service.Product, error) {
+
+	err := checkIncludeDeleted(req.GetIncludeDeleted())
+	if err != nil {
+		return nil, err
+	}
+
+	return p.get(ctx, req.GetId())
+}
+
+func (p *ProductService) GetList(ctx context.Context, req *market_service.ProductGetListRequest) (*market_service.ProductGetListResponse, error) {
+
+	err := checkIncludeDeleted(req.GetIncludeDeleted())
+	if err != nil {
+		return nil, err
+	}
+
+	offset, limit := page(p.cfg, req.GetOffset(), req.GetLimit())
+
+	resp, err := p.strg.Product().GetList(ctx, &models.ProductGetListRequest{
+		Offset:        offset,
+		Limit:         limit,
+		Search:        req.GetSearch(),
+		CategoryId:    req.GetCategoryId(),
+		ParentId:      req.GetParentId(),
+		GroupVariants: req.GetGroupVariants(),
+	})
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	products := make([]*market_service.Product, 0, len(resp.Products))
+	for _, product := range resp.Products {
+		products = append(products, p.productToProto(product))
+	}
+
+	return &market_service.ProductGetListResponse{
+		Count:    int64(resp.Count),
+		Products: products,
+	}, nil
+}
+
+func (p *ProductService) Update(ctx context.Context, req *market_service.UpdateProduct) (*market_service.Product, error) {
+
+	updateProduct := models.UpdateProduct{
+		Id:         req.GetId(),
+		Name:       req.GetName(),
+		Barcode:    req.GetBarcode(),
+		Price:      req.GetPrice(),
+		CategoryId: req.GetCategoryId(),
+		ParentId:   req.GetParentId(),
+		Size:       req.GetSize(),
+		Color:      req.GetColor(),
+		Volume:     req.GetVolume(),
+		Serialized: req.GetSerialized(),
+		Version:    int(req.GetVersion()),
+	}
+
+	err := validate(&updateProduct)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = p.strg.Product().Update(ctx, &updateProduct)
+	if errors.Is(err, storage.ErrVersionConflict) {
+		return nil, versionConflict(ctx, err, p.get, updateProduct.Id)
+	} else if err != nil {
+		return nil, statusError(err)
+	}
+
+	return p.get(ctx, updateProduct.Id)
+}
+
+func (p *ProductService) Patch(ctx context.Context, req *market_service.PatchProduct) (*market_service.Product, error) {
+
+	patchProduct := models.PatchRequest{
+		ID:      req.GetId(),
+		Version: int(req.GetVersion()),
+		Fields:  req.GetFields().AsMap(),
+	}
+
+	// The version travels in its own field, as the If-Match header does over
+	// REST, and is never patched.
+	delete(patchProduct.Fields, "version")
+
+	engine, _ := binding.Validator.Engine().(*validator.Validate)
+
+	err := helper.ValidateProductPatch(engine, patchProduct.Fields)
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	_, err = p.strg.Product().Patch(ctx, &patchProduct)
+	if errors.Is(err, storage.ErrVersionConflict) {
+		return nil, versionConflict(ctx, err, p.get, patchProduct.ID)
+	} else if err != nil {
+		return nil, statusError(err)
+	}
+
+	return p.get(ctx, patchProduct.ID)
+}
+
+func (p *ProductService) Delete(ctx context.Context, req *market_service.ProductPrimaryKey) (*emptypb.Empty, error) {
+
+	err := p.strg.Product().Delete(ctx, &models.ProductPrimaryKey{Id: req.GetId()})
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (p *ProductService) Restore(ctx context.Context, req *market_service.ProductPrimaryKey) (*market_service.Product, error) {
+
+	_, err := p.strg.Product().Restore(ctx, &models.ProductPrimaryKey{Id: req.GetId()})
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	return p.get(ctx, req.GetId())
+}
+
+func (p *ProductService) GetByBarcode(ctx context.Context, req *market_service.ProductBarcodeLookup) (*market_service.ProductBarcodeLookupResponse, error) {
+
+	var code = req.GetBarcode()
+
+	resp, err := p.strg.Product().GetByBarcode(ctx, &models.ProductBarcodeLookup{Barcode: code})
+	if err == nil {
+		return p.lookupToProto(resp), nil
+	}
+
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, statusError(err)
+	}
+
+	// Scale labels carry weight or price inside the code, so resolve them by
+	// the base code the weighed item was registered with.
+	embedded, decodeErr := barcode.DecodeEmbedded(code, p.cfg.WeightBarcodePrefixes, p.cfg.PriceBarcodePrefixes)
+	if decodeErr != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+
+	resp, err = p.strg.Product().GetByBarcode(ctx, &models.ProductBarcodeLookup{Barcode: embedded.BaseCode})
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+
+	switch embedded.Kind {
+	case barcode.EmbeddedWeight:
+		resp.Weight = embedded.Value
+	case barcode.EmbeddedPrice:
+		resp.Price = embedded.Value
+		if resp.Product.Price > 0 {
+			resp.Weight = int32(int64(embedded.Value) * 1000 / int64(resp.Product.Price))
+		}
+	}
+
+	return p.lookupToProto(resp), nil
+}
+
+func (p *ProductService) Export(req *market_service.ProductGetListRequest, stream market_service.ProductService_ExportServer) error {
+
+	err := checkIncludeDeleted(req.GetIncludeDeleted())
+	if err != nil {
+		return err
+	}
+
+	filters := export.Filters{
+		"search":      req.GetSearch(),
+		"category_id": req.GetCategoryId(),
+		"parent_id":   req.GetParentId(),
+	}
+
+	err = export.Each(stream.Context(), p.strg, "product", filters, nil, func(item interface{}) error {
+		return stream.Send(p.productToProto(item.(*models.Product)))
+	})
+	if err != nil {
+		return statusError(err)
+	}
+
+	return nil
+}
+
+func (p *ProductService) get(ctx context.Context, id string) (*market_service.Product, error) {
+
+	resp, err := p.strg.Product().GetByID(ctx, &models.ProductPrimaryKey{Id: id})
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	return p.productToProto(resp), nil
+}
+
+func (p *ProductService) lookupToProto(lookup *models.ProductBarcodeLookupResponse) *market_service.ProductBarcodeLookupResponse {
+	return &market_service.ProductBarcodeLookupResponse{
+		Product:  p.productToProto(lookup.Product),
+		Type:     lookup.Type,
+		Quantity: lookup.Quantity,
+		Weight:   lookup.Weight,
+		Price:    lookup.Price,
+	}
+}
+
+// productToProto converts a product with its variants, barcodes and images,
+// whose URLs point at the REST API as they are served from there.
+func (p *ProductService) productToProto(product *models.Product) *market_service.Product {
+
+	if product == nil {
+		return nil
+	}
+
+	resp := &market_service.Product{
+		Id:         product.Id,
+		Name:       product.Name,
+		Barcode:    product.Barcode,
+		Price:      product.Price,
+		CategoryId: product.CategoryId,
+		ParentId:   product.ParentId,
+		Size:       product.Size,
+		Color:      product.Color,
+		Volume:     product.Volume,
+		Serialized: product.Serialized,
+		Version:    int64(product.Version),
+		CreatedAt:  product.CreatedAt,
+		UpdatedAt:  product.UpdatedAt,
+		DeletedAt:  product.DeletedAt,
+	}
+
+	for _, variant := range product.Variants {
+		resp.Variants = append(resp.Variants, p.productToProto(variant))
+	}
+
+	for _, productBarcode := range product.Barcodes {
+		resp.Barcodes = append(resp.Barcodes, &market_service.ProductBarcode{
+			Id:        productBarcode.Id,
+			ProductId: productBarcode.ProductId,
+			Barcode:   productBarcode.Barcode,
+			Type:      productBarcode.Type,
+			Quantity:  productBarcode.Quantity,
+			CreatedAt: productBarcode.CreatedAt,
+			UpdatedAt: productBarcode.UpdatedAt,
+		})
+	}
+
+	for _, image := range product.Images {
+		resp.Images = append(resp.Images, &market_service.ProductImage{
+			Id:           image.Id,
+			ProductId:    image.ProductId,
+			ContentType:  image.ContentType,
+			Size:         image.Size,
+			Position:     image.Position,
+			Url:          p.cfg.PublicBaseURL + "/product_image/" + image.Id + "/file",
+			ThumbnailUrl: p.cfg.PublicBaseURL + "/product_image/" + image.Id + "/thumbnail",
+			CreatedAt:    image.CreatedAt,
+			UpdatedAt:    image.UpdatedAt,
+		})
+	}
+
+	return resp
+}
@@ -0,0 +1,142 @@
+// Package service implements the gRPC services of market_service on top of
+// the storage repos the REST handlers use.
+package service
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin/binding"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"market/config"
+	"market/pkg/apperr"
+	"market/pkg/helper"
+	"market/storage"
+)
+
+var errIncludeDeleted = status.Error(codes.PermissionDenied, "include_deleted is allowed to admins only")
+
+// statusError turns an error of the storage layer into a gRPC status, the way
+// handleError of api/handler picks the HTTP code.
+func statusError(err error) error {
+
+	appErr, ok := apperr.As(err)
+	if !ok {
+		if _, ok := status.FromError(err); ok {
+			return err
+		}
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	switch appErr.Kind {
+	case apperr.KindNotFound:
+		return status.Error(codes.NotFound, appErr.Error())
+	case apperr.KindConflict:
+		switch {
+		case errors.Is(err, storage.ErrVersionConflict):
+			return status.Error(codes.Aborted, appErr.Error())
+		case appErr.Code == apperr.CodeConflict, appErr.Code == "already_exists":
+			return status.Error(codes.AlreadyExists, appErr.Error())
+		}
+		return status.Error(codes.FailedPrecondition, appErr.Error())
+	case apperr.KindInvalidStateTransition:
+		return status.Error(codes.FailedPrecondition, appErr.Error())
+	case apperr.KindValidation:
+		return validationStatus(appErr)
+	case apperr.KindForbidden:
+		return status.Error(codes.PermissionDenied, appErr.Error())
+	}
+
+	return status.Error(codes.Internal, appErr.Error())
+}
+
+// validationStatus lists the fields of a validation error as BadRequest
+// details, so clients need not parse the message.
+func validationStatus(appErr *apperr.Error) error {
+
+	st := status.New(codes.InvalidArgument, appErr.Error())
+
+	if len(appErr.Fields) <= 0 {
+		return st.Err()
+	}
+
+	var details errdetails.BadRequest
+	for _, field := range appErr.Fields {
+		details.FieldViolations = append(details.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       field.Field,
+			Description: field.Message,
+		})
+	}
+
+	withDetails, err := st.WithDetails(&details)
+	if err != nil {
+		return st.Err()
+	}
+
+	return withDetails.Err()
+}
+
+// versionConflict is the Aborted status of a write that lost to another one.
+// It carries the current version of the record with id in an ErrorInfo, as
+// REST answers with the current record and its ETag.
+func versionConflict[T interface{ GetVersion() int64 }](ctx context.Context, err error, get func(context.Context, string) (T, error), id string) error {
+
+	current, getErr := get(ctx, id)
+	if getErr != nil {
+		return getErr
+	}
+
+	st := status.New(codes.Aborted, err.Error())
+
+	withDetails, detailsErr := st.WithDetails(&errdetails.ErrorInfo{
+		Reason:   "VERSION_CONFLICT",
+		Domain:   "market",
+		Metadata: map[string]string{"current_version": strconv.FormatInt(current.GetVersion(), 10)},
+	})
+	if detailsErr != nil {
+		return st.Err()
+	}
+
+	return withDetails.Err()
+}
+
+// validate checks obj against its binding rules, as gin does for the bodies
+// of REST requests.
+func validate(obj interface{}) error {
+
+	err := binding.Validator.ValidateStruct(obj)
+	if err != nil {
+		return statusError(helper.ValidationError(err))
+	}
+
+	return nil
+}
+
+// checkIncludeDeleted refuses deleted records, which only admin users may
+// see. The gRPC API is for API keys, which are never admins.
+func checkIncludeDeleted(includeDeleted bool) error {
+
+	if includeDeleted {
+		return errIncludeDeleted
+	}
+
+	return nil
+}
+
+// page falls back to the default offset and limit of cfg for zero values.
+func page(cfg *config.Config, offset, limit int64) (int, int) {
+
+	if offset <= 0 {
+		offset = int64(cfg.DefaultOffset)
+	}
+
+	if limit <= 0 {
+		limit = int64(cfg.DefaultLimit)
+	}
+
+	return int(offset), int(limit)
+}
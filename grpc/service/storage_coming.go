@@ -0,0 +1,225 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"market/api/models"
+	"market/config"
+	"market/export"
+	"market/genproto/market_service"
+	"market/pkg/apperr"
+	"market/pkg/logger"
+	"market/storage"
+)
+
+type StorageComingService struct {
+	cfg  *config.Config
+	log  logger.LoggerI
+	strg storage.StorageI
+	market_service.UnimplementedStorageComingServiceServer
+}
+
+func NewStorageComingService(cfg *config.Config, log logger.LoggerI, strg storage.StorageI) *StorageComingService {
+	return &StorageComingService{
+		cfg:  cfg,
+		log:  log,
+		strg: strg,
+	}
+}
+
+func (s *StorageComingService) Create(ctx context.Context, req *market_service.CreateStorageComing) (*market_service.StorageComing, error) {
+
+	createStorageComing := models.CreateStorageComing{
+		ComingId: req.GetComingId(),
+		BranchId: req.GetBranchId(),
+	}
+
+	err := validate(&createStorageComing)
+	if err != nil {
+		return nil, err
+	}
+
+	return idempotent(ctx, s.cfg, s.log, s.strg, req, &market_service.StorageComing{}, func() (*market_service.StorageComing, error) {
+
+		id, err := s.strg.StorageComing().Create(ctx, &createStorageComing)
+		if err != nil {
+			return nil, statusError(err)
+		}
+
+		return s.get(ctx, id)
+	})
+}
+
+func (s *StorageComingService) GetByID(ctx context.Context, req *market_service.StorageComingPrimaryKey) (*market_service.StorageComing, error) {
+
+	err := checkIncludeDeleted(req.GetIncludeDeleted())
+	if err != nil {
+		return nil, err
+	}
+
+	return s.get(ctx, req.GetId())
+}
+
+func (s *StorageComingService) GetList(ctx context.Context, req *market_service.StorageComingGetListRequest) (*market_service.StorageComingGetListResponse, error) {
+
+	err := checkIncludeDeleted(req.GetIncludeDeleted())
+	if err != nil {
+		return nil, err
+	}
+
+	offset, limit := page(s.cfg, req.GetOffset(), req.GetLimit())
+
+	resp, err := s.strg.StorageComing().GetList(ctx, &models.StorageComingGetListRequest{
+		Offset: offset,
+		Limit:  limit,
+		Search: req.GetSearch(),
+	})
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	storageComings := make([]*market_service.StorageComing, 0, len(resp.StorageComings))
+	for _, storageComing := range resp.StorageComings {
+		storageComings = append(storageComings, storageComingToProto(storageComing))
+	}
+
+	return &market_service.StorageComingGetListResponse{
+		Count:          int64(resp.Count),
+		StorageComings: storageComings,
+	}, nil
+}
+
+func (s *StorageComingService) Update(ctx context.Context, req *market_service.UpdateStorageComing) (*market_service.StorageComing, error) {
+
+	updateStorageComing := models.UpdateStorageComing{
+		Id:       req.GetId(),
+		ComingId: req.GetComingId(),
+		BranchId: req.GetBranchId(),
+		Status:   req.GetStatus(),
+		Version:  int(req.GetVersion()),
+	}
+
+	err := validate(&updateStorageComing)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.strg.StorageComing().Update(ctx, &updateStorageComing)
+	if errors.Is(err, storage.ErrVersionConflict) {
+		return nil, versionConflict(ctx, err, s.get, updateStorageComing.Id)
+	} else if err != nil {
+		return nil, statusError(err)
+	}
+
+	return s.get(ctx, updateStorageComing.Id)
+}
+
+func (s *StorageComingService) Transition(ctx context.Context, req *market_service.TransitionStorageComing) (*market_service.StorageComing, error) {
+
+	transition := models.TransitionStorageComing{
+		Id:      req.GetId(),
+		Status:  req.GetStatus(),
+		Note:    req.GetNote(),
+		Version: int(req.GetVersion()),
+	}
+
+	if _, ok := models.StorageComingTransitions[transition.Status]; !ok {
+		return nil, statusError(apperr.Validation("invalid request", apperr.FieldError{Field: "status", Message: "unknown status"}))
+	}
+
+	err := validate(&transition)
+	if err != nil {
+		return nil, err
+	}
+
+	return idempotent(ctx, s.cfg, s.log, s.strg, req, &market_service.StorageComing{}, func() (*market_service.StorageComing, error) {
+
+		_, err := s.strg.StorageComing().Transition(ctx, &transition)
+		if errors.Is(err, storage.ErrVersionConflict) {
+			return nil, versionConflict(ctx, err, s.get, transition.Id)
+		} else if err != nil {
+			return nil, statusError(err)
+		}
+
+		return s.get(ctx, transition.Id)
+	})
+}
+
+func (s *StorageComingService) Delete(ctx context.Context, req *market_service.StorageComingPrimaryKey) (*emptypb.Empty, error) {
+
+	err := s.strg.StorageComing().Delete(ctx, &models.StorageComingPrimaryKey{Id: req.GetId()})
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *StorageComingService) Restore(ctx context.Context, req *market_service.StorageComingPrimaryKey) (*market_service.StorageComing, error) {
+
+	_, err := s.strg.StorageComing().Restore(ctx, &models.StorageComingPrimaryKey{Id: req.GetId()})
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	return s.get(ctx, req.GetId())
+}
+
+func (s *StorageComingService) Export(req *market_service.StorageComingGetListRequest, stream market_service.StorageComingService_ExportServer) error {
+
+	err := checkIncludeDeleted(req.GetIncludeDeleted())
+	if err != nil {
+		return err
+	}
+
+	err = export.Each(stream.Context(), s.strg, "storage_coming", export.Filters{"search": req.GetSearch()}, nil, func(item interface{}) error {
+		return stream.Send(storageComingToProto(item.(*models.StorageComing)))
+	})
+	if err != nil {
+		return statusError(err)
+	}
+
+	return nil
+}
+
+func (s *StorageComingService) get(ctx context.Context, id string) (*market_service.StorageComing, error) {
+
+	resp, err := s.strg.StorageComing().GetByID(ctx, &models.StorageComingPrimaryKey{Id: id})
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	return storageComingToProto(resp), nil
+}
+
+func storageComingToProto(storageComing *models.StorageComing) *market_service.StorageComing {
+
+	resp := &market_service.StorageComing{
+		Id:        storageComing.Id,
+		ComingId:  storageComing.ComingId,
+		BranchId:  storageComing.BranchId,
+		Status:    storageComing.Status,
+		DateTime:  storageComing.DateTime,
+		Version:   int64(storageComing.Version),
+		CreatedAt: storageComing.CreatedAt,
+		UpdatedAt: storageComing.UpdatedAt,
+		DeletedAt: storageComing.DeletedAt,
+	}
+
+	for _, transition := range storageComing.History {
+		resp.History = append(resp.History, &market_service.StorageComingTransition{
+			Id:         transition.Id,
+			FromStatus: transition.FromStatus,
+			ToStatus:   transition.ToStatus,
+			ActorType:  transition.ActorType,
+			ActorId:    transition.ActorId,
+			Note:       transition.Note,
+			CreatedAt:  transition.CreatedAt,
+		})
+	}
+
+	return resp
+}
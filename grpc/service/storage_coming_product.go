@@ -0,0 +1,216 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"market/api/models"
+	"market/config"
+	"market/export"
+	"market/genproto/market_service"
+	"market/pkg/logger"
+	"market/storage"
+)
+
+type StorageComingProductService struct {
+	cfg  *config.Config
+	log  logger.LoggerI
+	strg storage.StorageI
+	market_service.UnimplementedStorageComingProductServiceServer
+}
+
+func NewStorageComingProductService(cfg *config.Config, log logger.LoggerI, strg storage.StorageI) *StorageComingProductService {
+	return &StorageComingProductService{
+		cfg:  cfg,
+		log:  log,
+		strg: strg,
+	}
+}
+
+func (s *StorageComingProductService) Create(ctx context.Context, req *market_service.CreateStorageComingProduct) (*market_service.StorageComingProduct, error) {
+
+	createStorageComingProduct := models.CreateStorageComingProduct{
+		Name:            req.GetName(),
+		Quantity:        req.GetQuantity(),
+		Price:           req.GetPrice(),
+		CategoryId:      req.GetCategoryId(),
+		ProductId:       req.GetProductId(),
+		Barcode:         req.GetBarcode(),
+		Batch:           req.GetBatch(),
+		ExpiryDate:      req.GetExpiryDate(),
+		Serials:         req.GetSerials(),
+		StorageComingId: req.GetStorageComingId(),
+	}
+
+	err := validate(&createStorageComingProduct)
+	if err != nil {
+		return nil, err
+	}
+
+	err = storage.CheckSerials(ctx, s.strg, createStorageComingProduct.ProductId, createStorageComingProduct.Quantity, createStorageComingProduct.Serials)
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	return idempotent(ctx, s.cfg, s.log, s.strg, req, &market_service.StorageComingProduct{}, func() (*market_service.StorageComingProduct, error) {
+
+		id, err := s.strg.StorageComingProduct().Create(ctx, &createStorageComingProduct)
+		if err != nil {
+			return nil, statusError(err)
+		}
+
+		return s.get(ctx, id)
+	})
+}
+
+func (s *StorageComingProductService) GetByID(ctx context.Context, req *market_service.StorageComingProductPrimaryKey) (*market_service.StorageComingProduct, error) {
+
+	err := checkIncludeDeleted(req.GetIncludeDeleted())
+	if err != nil {
+		return nil, err
+	}
+
+	return s.get(ctx, req.GetId())
+}
+
+func (s *StorageComingProductService) GetList(ctx context.Context, req *market_service.StorageComingProductGetListRequest) (*market_service.StorageComingProductGetListResponse, error) {
+
+	err := checkIncludeDeleted(req.GetIncludeDeleted())
+	if err != nil {
+		return nil, err
+	}
+
+	offset, limit := page(s.cfg, req.GetOffset(), req.GetLimit())
+
+	resp, err := s.strg.StorageComingProduct().GetList(ctx, &models.StorageComingProductGetListRequest{
+		Offset:          offset,
+		Limit:           limit,
+		Search:          req.GetSearch(),
+		StorageComingId: req.GetStorageComingId(),
+	})
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	storageComingProducts := make([]*market_service.StorageComingProduct, 0, len(resp.StorageComingProducts))
+	for _, storageComingProduct := range resp.StorageComingProducts {
+		storageComingProducts = append(storageComingProducts, storageComingProductToProto(storageComingProduct))
+	}
+
+	return &market_service.StorageComingProductGetListResponse{
+		Count:                 int64(resp.Count),
+		StorageComingProducts: storageComingProducts,
+	}, nil
+}
+
+func (s *StorageComingProductService) Update(ctx context.Context, req *market_service.UpdateStorageComingProduct) (*market_service.StorageComingProduct, error) {
+
+	updateStorageComingProduct := models.UpdateStorageComingProduct{
+		Id:              req.GetId(),
+		Name:            req.GetName(),
+		Quantity:        req.GetQuantity(),
+		Price:           req.GetPrice(),
+		CategoryId:      req.GetCategoryId(),
+		ProductId:       req.GetProductId(),
+		Barcode:         req.GetBarcode(),
+		Batch:           req.GetBatch(),
+		ExpiryDate:      req.GetExpiryDate(),
+		Serials:         req.GetSerials(),
+		StorageComingId: req.GetStorageComingId(),
+		Version:         int(req.GetVersion()),
+	}
+
+	err := validate(&updateStorageComingProduct)
+	if err != nil {
+		return nil, err
+	}
+
+	err = storage.CheckSerials(ctx, s.strg, updateStorageComingProduct.ProductId, updateStorageComingProduct.Quantity, updateStorageComingProduct.Serials)
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	_, err = s.strg.StorageComingProduct().Update(ctx, &updateStorageComingProduct)
+	if errors.Is(err, storage.ErrVersionConflict) {
+		return nil, versionConflict(ctx, err, s.get, updateStorageComingProduct.Id)
+	} else if err != nil {
+		return nil, statusError(err)
+	}
+
+	return s.get(ctx, updateStorageComingProduct.Id)
+}
+
+func (s *StorageComingProductService) Delete(ctx context.Context, req *market_service.StorageComingProductPrimaryKey) (*emptypb.Empty, error) {
+
+	err := s.strg.StorageComingProduct().Delete(ctx, &models.StorageComingProductPrimaryKey{Id: req.GetId()})
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *StorageComingProductService) Restore(ctx context.Context, req *market_service.StorageComingProductPrimaryKey) (*market_service.StorageComingProduct, error) {
+
+	_, err := s.strg.StorageComingProduct().Restore(ctx, &models.StorageComingProductPrimaryKey{Id: req.GetId()})
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	return s.get(ctx, req.GetId())
+}
+
+func (s *StorageComingProductService) Export(req *market_service.StorageComingProductGetListRequest, stream market_service.StorageComingProductService_ExportServer) error {
+
+	err := checkIncludeDeleted(req.GetIncludeDeleted())
+	if err != nil {
+		return err
+	}
+
+	filters := export.Filters{
+		"search":            req.GetSearch(),
+		"storage_coming_id": req.GetStorageComingId(),
+	}
+
+	err = export.Each(stream.Context(), s.strg, "storage_coming_product", filters, nil, func(item interface{}) error {
+		return stream.Send(storageComingProductToProto(item.(*models.StorageComingProduct)))
+	})
+	if err != nil {
+		return statusError(err)
+	}
+
+	return nil
+}
+
+func (s *StorageComingProductService) get(ctx context.Context, id string) (*market_service.StorageComingProduct, error) {
+
+	resp, err := s.strg.StorageComingProduct().GetByID(ctx, &models.StorageComingProductPrimaryKey{Id: id})
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	return storageComingProductToProto(resp), nil
+}
+
+func storageComingProductToProto(storageComingProduct *models.StorageComingProduct) *market_service.StorageComingProduct {
+	return &market_service.StorageComingProduct{
+		Id:              storageComingProduct.Id,
+		Name:            storageComingProduct.Name,
+		Quantity:        storageComingProduct.Quantity,
+		Price:           storageComingProduct.Price,
+		TotalPrice:      storageComingProduct.TotalPrice,
+		CategoryId:      storageComingProduct.CategoryId,
+		ProductId:       storageComingProduct.ProductId,
+		Barcode:         storageComingProduct.Barcode,
+		Batch:           storageComingProduct.Batch,
+		ExpiryDate:      storageComingProduct.ExpiryDate,
+		Serials:         storageComingProduct.Serials,
+		StorageComingId: storageComingProduct.StorageComingId,
+		Version:         int64(storageComingProduct.Version),
+		CreatedAt:       storageComingProduct.CreatedAt,
+		UpdatedAt:       storageComingProduct.UpdatedAt,
+		DeletedAt:       storageComingProduct.DeletedAt,
+	}
+}
@@ -0,0 +1,139 @@
+package job
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"market/api/models"
+	"market/config"
+	"market/pkg/logger"
+	"market/storage"
+)
+
+// LowStock reports products that fell below their minimum stock level to the
+// configured webhook.
+type LowStock struct {
+	cfg    *config.Config
+	strg   storage.StorageI
+	log    logger.LoggerI
+	client *http.Client
+
+	// notified holds branch/product pairs already sent, so an item is
+	// reported once until it is restocked above its minimum.
+	notified map[string]bool
+}
+
+type lowStockEvent struct {
+	Event       string                      `json:"event"`
+	CreatedAt   string                      `json:"created_at"`
+	Suggestions []*models.ReorderSuggestion `json:"suggestions"`
+}
+
+func NewLowStock(cfg *config.Config, strg storage.StorageI, log logger.LoggerI) *LowStock {
+	return &LowStock{
+		cfg:      cfg,
+		strg:     strg,
+		log:      log,
+		client:   &http.Client{Timeout: 10 * time.Second},
+		notified: map[string]bool{},
+	}
+}
+
+// Run checks stock every cfg.LowStockCheckInterval minutes until ctx is done.
+func (j *LowStock) Run(ctx context.Context) {
+
+	if j.cfg.LowStockCheckInterval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(time.Duration(j.cfg.LowStockCheckInterval) * time.Minute)
+	defer ticker.Stop()
+
+	for {
+		err := j.Check(ctx)
+		if err != nil {
+			j.log.Error("job.low_stock", logger.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (j *LowStock) Check(ctx context.Context) error {
+
+	report, err := j.strg.Report().Reorder(ctx, &models.ReorderReportRequest{
+		WindowDays: j.cfg.ReorderWindowDays,
+		LeadDays:   j.cfg.ReorderLeadDays,
+	})
+	if err != nil {
+		return err
+	}
+
+	var (
+		below = map[string]bool{}
+		fresh []*models.ReorderSuggestion
+	)
+
+	for _, suggestion := range report.Suggestions {
+		key := suggestion.BranchId + "/" + suggestion.ProductId
+
+		below[key] = true
+		if !j.notified[key] {
+			fresh = append(fresh, suggestion)
+		}
+	}
+
+	if len(fresh) > 0 {
+		j.log.Info("job.low_stock", logger.Int("below_minimum", len(report.Suggestions)), logger.Int("new", len(fresh)))
+
+		if j.cfg.LowStockWebhookURL != "" {
+			err = j.notify(ctx, fresh)
+			if err != nil {
+				// Keep the previous state so the items are sent again next time.
+				return err
+			}
+		}
+	}
+
+	j.notified = below
+
+	return nil
+}
+
+func (j *LowStock) notify(ctx context.Context, suggestions []*models.ReorderSuggestion) error {
+
+	body, err := json.Marshal(&lowStockEvent{
+		Event:       "low_stock",
+		CreatedAt:   time.Now().Format(time.RFC3339),
+		Suggestions: suggestions,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, j.cfg.LowStockWebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := j.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("low stock webhook: %s", resp.Status)
+	}
+
+	return nil
+}
@@ -0,0 +1,89 @@
+package job
+
+import (
+	"context"
+	"time"
+
+	"market/config"
+	"market/pkg/logger"
+	"market/storage"
+)
+
+// Purge removes records soft deleted more than cfg.PurgeAfterDays ago and
+// expired idempotency keys.
+type Purge struct {
+	cfg  *config.Config
+	strg storage.StorageI
+	log  logger.LoggerI
+}
+
+func NewPurge(cfg *config.Config, strg storage.StorageI, log logger.LoggerI) *Purge {
+	return &Purge{
+		cfg:  cfg,
+		strg: strg,
+		log:  log,
+	}
+}
+
+// Run purges once a day until ctx is done.
+func (j *Purge) Run(ctx context.Context) {
+
+	ticker := time.NewTicker(24 * time.Hour)
+	defer ticker.Stop()
+
+	for {
+		err := j.Check(ctx)
+		if err != nil {
+			j.log.Error("job.purge", logger.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check purges every entity, lines before their storage comings and
+// products before their categories, so parents are free to go in the same
+// run.
+func (j *Purge) Check(ctx context.Context) error {
+
+	count, err := j.strg.IdempotencyKey().Purge(ctx)
+	if err != nil {
+		return err
+	}
+
+	if count > 0 {
+		j.log.Info("job.purge", logger.String("entity", "idempotency_key"), logger.Int("count", int(count)))
+	}
+
+	if j.cfg.PurgeAfterDays <= 0 {
+		return nil
+	}
+
+	var purges = []struct {
+		entity string
+		purge  func(ctx context.Context, days int) (int64, error)
+	}{
+		{"storage_coming_product", j.strg.StorageComingProduct().Purge},
+		{"storage_coming", j.strg.StorageComing().Purge},
+		{"product", j.strg.Product().Purge},
+		{"category", j.strg.Category().Purge},
+		{"branch", j.strg.Branch().Purge},
+	}
+
+	for _, p := range purges {
+		count, err := p.purge(ctx, j.cfg.PurgeAfterDays)
+		if err != nil {
+			return err
+		}
+
+		if count > 0 {
+			j.log.Info("job.purge", logger.String("entity", p.entity), logger.Int("count", int(count)))
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,58 @@
+package job
+
+import (
+	"context"
+	"time"
+
+	"market/config"
+	"market/pkg/logger"
+	"market/storage"
+)
+
+// Snapshot records the remaining counts of the day, the history past stock
+// valuation and reorder velocity are computed from. It runs on its own, so
+// turning the low-stock check off does not leave gaps in the history.
+type Snapshot struct {
+	cfg  *config.Config
+	strg storage.StorageI
+	log  logger.LoggerI
+}
+
+func NewSnapshot(cfg *config.Config, strg storage.StorageI, log logger.LoggerI) *Snapshot {
+	return &Snapshot{
+		cfg:  cfg,
+		strg: strg,
+		log:  log,
+	}
+}
+
+// Run snapshots every cfg.StockSnapshotInterval minutes until ctx is done.
+func (j *Snapshot) Run(ctx context.Context) {
+
+	if j.cfg.StockSnapshotInterval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(time.Duration(j.cfg.StockSnapshotInterval) * time.Minute)
+	defer ticker.Stop()
+
+	for {
+		err := j.Check(ctx)
+		if err != nil {
+			j.log.Error("job.snapshot", logger.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (j *Snapshot) Check(ctx context.Context) error {
+
+	_, err := j.strg.Remaining().Snapshot(ctx)
+
+	return err
+}
@@ -0,0 +1,8 @@
+ALTER TABLE "product"
+    ADD COLUMN "parent_id" UUID REFERENCES "product"("id"),
+    ADD COLUMN "size" VARCHAR(20),
+    ADD COLUMN "color" VARCHAR(30),
+    ADD COLUMN "volume" VARCHAR(20);
+
+ALTER TABLE "remaining"
+    ADD COLUMN "product_id" UUID REFERENCES "product"("id");
@@ -0,0 +1,11 @@
+CREATE TABLE "product_barcode"(
+    "id" UUID NOT NULL PRIMARY KEY,
+    "product_id" UUID NOT NULL REFERENCES "product"("id") ON DELETE CASCADE,
+    "barcode" VARCHAR(48) UNIQUE NOT NULL,
+    "type" VARCHAR(20) NOT NULL DEFAULT 'manufacturer',
+    "quantity" NUMERIC NOT NULL DEFAULT 1,
+    "created_at" TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" TIMESTAMP
+);
+
+CREATE INDEX "product_barcode_product_id_idx" ON "product_barcode"("product_id");
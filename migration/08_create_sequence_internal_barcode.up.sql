@@ -0,0 +1,3 @@
+CREATE SEQUENCE "internal_barcode_seq" START WITH 1 MINVALUE 1;
+
+CREATE SEQUENCE "weighed_barcode_seq" START WITH 1 MINVALUE 1 MAXVALUE 99999;
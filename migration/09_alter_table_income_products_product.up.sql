@@ -0,0 +1,2 @@
+ALTER TABLE "income_products"
+    ADD COLUMN "product_id" UUID REFERENCES "product"("id");
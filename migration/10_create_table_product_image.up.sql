@@ -0,0 +1,13 @@
+CREATE TABLE "product_image"(
+    "id" UUID NOT NULL PRIMARY KEY,
+    "product_id" UUID NOT NULL REFERENCES "product"("id") ON DELETE CASCADE,
+    "file_key" VARCHAR NOT NULL,
+    "thumbnail_key" VARCHAR NOT NULL,
+    "content_type" VARCHAR(50) NOT NULL,
+    "size" BIGINT NOT NULL,
+    "position" INT NOT NULL DEFAULT 0,
+    "created_at" TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" TIMESTAMP
+);
+
+CREATE INDEX "product_image_product_id_idx" ON "product_image"("product_id", "position");
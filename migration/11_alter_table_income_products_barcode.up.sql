@@ -0,0 +1,2 @@
+ALTER TABLE "income_products"
+    ADD COLUMN "barcode" VARCHAR(48);
@@ -0,0 +1,17 @@
+CREATE TABLE "stock_level"(
+    "id" UUID NOT NULL PRIMARY KEY,
+    "branch_id" UUID NOT NULL REFERENCES "branch"("id") ON DELETE CASCADE,
+    "product_id" UUID NOT NULL REFERENCES "product"("id") ON DELETE CASCADE,
+    "min_count" NUMERIC NOT NULL DEFAULT 0,
+    "max_count" NUMERIC NOT NULL DEFAULT 0,
+    "created_at" TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" TIMESTAMP,
+    UNIQUE ("branch_id", "product_id")
+);
+
+CREATE TABLE "remaining_snapshot"(
+    "remaining_id" UUID NOT NULL REFERENCES "remaining"("id") ON DELETE CASCADE,
+    "count" NUMERIC NOT NULL,
+    "taken_on" DATE NOT NULL DEFAULT CURRENT_DATE,
+    PRIMARY KEY ("remaining_id", "taken_on")
+);
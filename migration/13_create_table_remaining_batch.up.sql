@@ -0,0 +1,17 @@
+ALTER TABLE "income_products"
+    ADD COLUMN "batch" VARCHAR(50),
+    ADD COLUMN "expiry_date" DATE;
+
+CREATE TABLE "remaining_batch"(
+    "id" UUID NOT NULL PRIMARY KEY,
+    "branch_id" UUID NOT NULL REFERENCES "branch"("id"),
+    "product_id" UUID NOT NULL REFERENCES "product"("id"),
+    "income_product_id" UUID REFERENCES "income_products"("id") ON DELETE SET NULL,
+    "batch" VARCHAR(50),
+    "expiry_date" DATE,
+    "count" NUMERIC NOT NULL,
+    "created_at" TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" TIMESTAMP
+);
+
+CREATE INDEX "remaining_batch_branch_product_idx" ON "remaining_batch"("branch_id", "product_id", "expiry_date");
@@ -0,0 +1,28 @@
+ALTER TABLE "product"
+    ADD COLUMN "serialized" BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE "serial_number"(
+    "id" UUID NOT NULL PRIMARY KEY,
+    "product_id" UUID NOT NULL REFERENCES "product"("id"),
+    "serial" VARCHAR(64) NOT NULL,
+    "status" VARCHAR(20) NOT NULL DEFAULT 'pending',
+    "branch_id" UUID REFERENCES "branch"("id"),
+    "income_product_id" UUID REFERENCES "income_products"("id") ON DELETE CASCADE,
+    "created_at" TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" TIMESTAMP,
+    UNIQUE ("product_id", "serial")
+);
+
+CREATE INDEX "serial_number_serial_idx" ON "serial_number"("serial");
+
+CREATE TABLE "serial_number_event"(
+    "id" UUID NOT NULL PRIMARY KEY,
+    "serial_number_id" UUID NOT NULL REFERENCES "serial_number"("id") ON DELETE CASCADE,
+    "action" VARCHAR(20) NOT NULL,
+    "status" VARCHAR(20) NOT NULL,
+    "branch_id" UUID REFERENCES "branch"("id"),
+    "note" VARCHAR(255),
+    "created_at" TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX "serial_number_event_serial_number_id_idx" ON "serial_number_event"("serial_number_id", "created_at");
@@ -0,0 +1,21 @@
+CREATE TABLE "users"(
+    "id" UUID NOT NULL PRIMARY KEY,
+    "login" VARCHAR(50) UNIQUE NOT NULL,
+    "password_hash" VARCHAR(100) NOT NULL,
+    "full_name" VARCHAR(100),
+    "active" BOOLEAN NOT NULL DEFAULT TRUE,
+    "created_at" TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" TIMESTAMP
+);
+
+CREATE TABLE "refresh_token"(
+    "id" UUID NOT NULL PRIMARY KEY,
+    "user_id" UUID NOT NULL REFERENCES "users"("id") ON DELETE CASCADE,
+    "family_id" UUID NOT NULL,
+    "token_hash" VARCHAR(64) UNIQUE NOT NULL,
+    "expires_at" TIMESTAMP NOT NULL,
+    "revoked_at" TIMESTAMP,
+    "created_at" TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX "refresh_token_family_id_idx" ON "refresh_token"("family_id");
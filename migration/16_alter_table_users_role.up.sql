@@ -0,0 +1,11 @@
+ALTER TABLE "users"
+    ADD COLUMN "role" VARCHAR(20) NOT NULL DEFAULT 'cashier';
+
+-- Users created so far had full access.
+UPDATE "users" SET "role" = 'admin';
+
+CREATE TABLE "user_branch"(
+    "user_id" UUID NOT NULL REFERENCES "users"("id") ON DELETE CASCADE,
+    "branch_id" UUID NOT NULL REFERENCES "branch"("id") ON DELETE CASCADE,
+    PRIMARY KEY ("user_id", "branch_id")
+);
@@ -0,0 +1,13 @@
+CREATE TABLE "api_key"(
+    "id" UUID PRIMARY KEY,
+    "name" VARCHAR(100) NOT NULL,
+    "prefix" VARCHAR(16) NOT NULL,
+    "key_hash" VARCHAR(64) NOT NULL UNIQUE,
+    "scopes" VARCHAR(30)[] NOT NULL DEFAULT '{}',
+    "rate_limit" INT NOT NULL DEFAULT 0,
+    "created_by" UUID REFERENCES "users"("id") ON DELETE SET NULL,
+    "last_used_at" TIMESTAMP,
+    "revoked_at" TIMESTAMP,
+    "created_at" TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" TIMESTAMP
+);
@@ -0,0 +1,29 @@
+CREATE TABLE "audit_log"(
+    "id" UUID PRIMARY KEY,
+    "actor_type" VARCHAR(20) NOT NULL,
+    "actor_id" VARCHAR(64),
+    "entity" VARCHAR(50) NOT NULL,
+    "entity_id" UUID NOT NULL,
+    "action" VARCHAR(10) NOT NULL,
+    "before" JSONB,
+    "after" JSONB,
+    "request_id" VARCHAR(64),
+    "created_at" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX "audit_log_entity_idx" ON "audit_log"("entity", "created_at");
+CREATE INDEX "audit_log_entity_id_idx" ON "audit_log"("entity_id", "created_at");
+
+CREATE FUNCTION "audit_log_append_only"() RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER "audit_log_no_change"
+    BEFORE UPDATE OR DELETE ON "audit_log"
+    FOR EACH ROW EXECUTE FUNCTION "audit_log_append_only"();
+
+CREATE TRIGGER "audit_log_no_truncate"
+    BEFORE TRUNCATE ON "audit_log"
+    FOR EACH STATEMENT EXECUTE FUNCTION "audit_log_append_only"();
@@ -0,0 +1,5 @@
+ALTER TABLE "branch" ADD COLUMN "deleted_at" TIMESTAMP;
+ALTER TABLE "category" ADD COLUMN "deleted_at" TIMESTAMP;
+ALTER TABLE "product" ADD COLUMN "deleted_at" TIMESTAMP;
+ALTER TABLE "storage_coming" ADD COLUMN "deleted_at" TIMESTAMP;
+ALTER TABLE "income_products" ADD COLUMN "deleted_at" TIMESTAMP;
@@ -0,0 +1,5 @@
+ALTER TABLE "branch" ADD COLUMN "version" INT NOT NULL DEFAULT 1;
+ALTER TABLE "category" ADD COLUMN "version" INT NOT NULL DEFAULT 1;
+ALTER TABLE "product" ADD COLUMN "version" INT NOT NULL DEFAULT 1;
+ALTER TABLE "storage_coming" ADD COLUMN "version" INT NOT NULL DEFAULT 1;
+ALTER TABLE "income_products" ADD COLUMN "version" INT NOT NULL DEFAULT 1;
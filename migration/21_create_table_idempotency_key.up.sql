@@ -0,0 +1,13 @@
+CREATE TABLE "idempotency_key"(
+    "key" VARCHAR(255) NOT NULL,
+    "actor_id" VARCHAR(64) NOT NULL,
+    "request_hash" VARCHAR(64) NOT NULL,
+    "response_code" INT,
+    "response_type" VARCHAR(100),
+    "response_body" BYTEA,
+    "created_at" TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    "expires_at" TIMESTAMP NOT NULL,
+    PRIMARY KEY ("key", "actor_id")
+);
+
+CREATE INDEX "idempotency_key_expires_at_idx" ON "idempotency_key"("expires_at");
@@ -0,0 +1,22 @@
+UPDATE "storage_coming" SET "status" = 'finished' WHERE "status" = 'fineshed';
+UPDATE "storage_coming" SET "status" = 'in process' WHERE "status" IS NULL;
+
+ALTER TABLE "storage_coming"
+    ALTER COLUMN "status" TYPE VARCHAR(20),
+    ALTER COLUMN "status" SET DEFAULT 'draft',
+    ALTER COLUMN "status" SET NOT NULL,
+    ADD CONSTRAINT "storage_coming_status_check"
+        CHECK ("status" IN ('draft', 'in process', 'received', 'finished', 'cancelled'));
+
+CREATE TABLE "storage_coming_transition"(
+    "id" UUID PRIMARY KEY,
+    "storage_coming_id" UUID NOT NULL REFERENCES "storage_coming"("id") ON DELETE CASCADE,
+    "from_status" VARCHAR(20),
+    "to_status" VARCHAR(20) NOT NULL,
+    "actor_type" VARCHAR(20) NOT NULL,
+    "actor_id" VARCHAR(64),
+    "note" VARCHAR(255),
+    "created_at" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX "storage_coming_transition_storage_coming_id_idx" ON "storage_coming_transition"("storage_coming_id", "created_at");
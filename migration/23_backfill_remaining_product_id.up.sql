@@ -0,0 +1,9 @@
+UPDATE "remaining" AS r
+SET "product_id" = p."id"
+FROM "product" AS p
+WHERE r."product_id" IS NULL AND p."barcode" = r."barcode";
+
+UPDATE "remaining" AS r
+SET "product_id" = pb."product_id"
+FROM "product_barcode" AS pb
+WHERE r."product_id" IS NULL AND pb."barcode" = r."barcode";
@@ -0,0 +1,39 @@
+-- Finishing two receipts of a branch and product at the same time could
+-- insert two rows for the pair. Merge them into the oldest row before making
+-- the pair unique.
+CREATE TEMPORARY TABLE "remaining_duplicate" AS
+    SELECT
+        "id",
+        FIRST_VALUE("id") OVER (PARTITION BY "branch_id", "product_id" ORDER BY "created_at", "id") AS "keep_id"
+    FROM "remaining"
+    WHERE "branch_id" IS NOT NULL AND "product_id" IS NOT NULL;
+
+DELETE FROM "remaining_duplicate" WHERE "id" = "keep_id";
+
+UPDATE "remaining" AS r
+SET
+    "count" = r."count" + d."count",
+    "total_price" = (r."count" + d."count") * r."price",
+    "updated_at" = NOW()
+FROM (
+    SELECT rd."keep_id", SUM(dr."count") AS "count"
+    FROM "remaining_duplicate" AS rd
+    JOIN "remaining" AS dr ON dr."id" = rd."id"
+    GROUP BY rd."keep_id"
+) AS d
+WHERE r."id" = d."keep_id";
+
+INSERT INTO "remaining_snapshot"("remaining_id", "count", "taken_on")
+SELECT rd."keep_id", SUM(rs."count"), rs."taken_on"
+FROM "remaining_snapshot" AS rs
+JOIN "remaining_duplicate" AS rd ON rd."id" = rs."remaining_id"
+GROUP BY rd."keep_id", rs."taken_on"
+ON CONFLICT ("remaining_id", "taken_on") DO UPDATE
+SET "count" = "remaining_snapshot"."count" + EXCLUDED."count";
+
+DELETE FROM "remaining" WHERE "id" IN (SELECT "id" FROM "remaining_duplicate");
+
+DROP TABLE "remaining_duplicate";
+
+ALTER TABLE "remaining"
+    ADD CONSTRAINT "remaining_branch_id_product_id_key" UNIQUE ("branch_id", "product_id");
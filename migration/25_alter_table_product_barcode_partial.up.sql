@@ -0,0 +1,9 @@
+-- A deleted product gives its barcode up, so a new product can take it.
+ALTER TABLE "product" DROP CONSTRAINT "product_barcode_key";
+CREATE UNIQUE INDEX "product_barcode_key" ON "product"("barcode") WHERE "deleted_at" IS NULL;
+
+-- Purging a deleted receipt line must not take the serial numbers it
+-- brought in with it, the line is kept while they refer to it.
+ALTER TABLE "serial_number" DROP CONSTRAINT "serial_number_income_product_id_fkey";
+ALTER TABLE "serial_number"
+    ADD CONSTRAINT "serial_number_income_product_id_fkey" FOREIGN KEY ("income_product_id") REFERENCES "income_products"("id");
@@ -0,0 +1,12 @@
+-- The additional barcodes of a deleted product are given up along with its
+-- main barcode. The row keeps the deleted_at of its product, so uniqueness
+-- only holds among live products.
+ALTER TABLE "product_barcode" ADD COLUMN "deleted_at" TIMESTAMP;
+
+UPDATE "product_barcode" AS pb
+SET "deleted_at" = p."deleted_at"
+FROM "product" AS p
+WHERE p."id" = pb."product_id" AND p."deleted_at" IS NOT NULL;
+
+ALTER TABLE "product_barcode" DROP CONSTRAINT "product_barcode_barcode_key";
+CREATE UNIQUE INDEX "product_barcode_barcode_key" ON "product_barcode"("barcode") WHERE "deleted_at" IS NULL;
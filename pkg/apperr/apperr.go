@@ -0,0 +1,112 @@
+// Package apperr holds the domain errors the storage layer returns and the
+// API renders, each with a kind that decides the HTTP status and a machine
+// readable code for clients.
+package apperr
+
+import (
+	"errors"
+	"strings"
+)
+
+type Kind int
+
+const (
+	KindNotFound Kind = iota + 1
+	KindConflict
+	KindValidation
+	KindInvalidStateTransition
+	KindForbidden
+)
+
+const (
+	CodeNotFound               = "not_found"
+	CodeConflict               = "conflict"
+	CodeValidation             = "validation_failed"
+	CodeInvalidStateTransition = "invalid_state_transition"
+	CodeForbidden              = "forbidden"
+)
+
+// FieldError is what is wrong with one field of a request.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+type Error struct {
+	Kind    Kind
+	Code    string
+	Message string
+	Fields  []FieldError
+
+	// Err is the cause, kept for errors.Is and logs.
+	Err error
+}
+
+func (e *Error) Error() string {
+
+	if len(e.Fields) <= 0 {
+		return e.Message
+	}
+
+	var fields []string
+	for _, field := range e.Fields {
+		fields = append(fields, field.Field+": "+field.Message)
+	}
+
+	return e.Message + ": " + strings.Join(fields, "; ")
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// New returns an error of kind with its own code, for errors clients tell
+// apart from others of the same kind.
+func New(kind Kind, code, message string) *Error {
+	return &Error{Kind: kind, Code: code, Message: message}
+}
+
+func NotFound(message string) *Error {
+	return New(KindNotFound, CodeNotFound, message)
+}
+
+func Conflict(message string) *Error {
+	return New(KindConflict, CodeConflict, message)
+}
+
+// Validation returns an error listing every field that is wrong.
+func Validation(message string, fields ...FieldError) *Error {
+
+	err := New(KindValidation, CodeValidation, message)
+	err.Fields = fields
+
+	return err
+}
+
+func InvalidStateTransition(from, to string) *Error {
+	return New(KindInvalidStateTransition, CodeInvalidStateTransition, "cannot go from "+from+" to "+to)
+}
+
+func Forbidden(message string) *Error {
+	return New(KindForbidden, CodeForbidden, message)
+}
+
+// Wrap returns err with cause attached.
+func Wrap(err *Error, cause error) *Error {
+
+	wrapped := *err
+	wrapped.Err = cause
+
+	return &wrapped
+}
+
+// As finds the first *Error in the chain of err.
+func As(err error) (*Error, bool) {
+
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr, true
+	}
+
+	return nil, false
+}
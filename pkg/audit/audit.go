@@ -0,0 +1,119 @@
+// Package audit carries who made a request through its context and computes
+// what a change did to a row.
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+)
+
+const (
+	ActorUser   = "user"
+	ActorApiKey = "api_key"
+	ActorSystem = "system"
+)
+
+const (
+	ActionCreate  = "create"
+	ActionUpdate  = "update"
+	ActionDelete  = "delete"
+	ActionRestore = "restore"
+	ActionPurge   = "purge"
+)
+
+// Actor is who a change is recorded for.
+type Actor struct {
+	Type string
+	Id   string
+}
+
+type contextKey int
+
+const (
+	actorKey contextKey = iota
+	requestIdKey
+)
+
+func WithActor(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, actorKey, actor)
+}
+
+// ActorFrom returns the actor of ctx, the system when there is none, as for
+// jobs and command line tools.
+func ActorFrom(ctx context.Context) Actor {
+
+	actor, ok := ctx.Value(actorKey).(Actor)
+	if !ok {
+		return Actor{Type: ActorSystem}
+	}
+
+	return actor
+}
+
+func WithRequestId(ctx context.Context, requestId string) context.Context {
+	return context.WithValue(ctx, requestIdKey, requestId)
+}
+
+func RequestId(ctx context.Context) string {
+	requestId, _ := ctx.Value(requestIdKey).(string)
+	return requestId
+}
+
+// Diff reduces the JSON objects of a row before and after a change to the
+// keys whose values differ. A missing row, nil, is kept as is, so a create
+// has only an after and a delete only a before. Both are nil when nothing
+// changed.
+func Diff(before, after []byte) ([]byte, []byte, error) {
+
+	if before == nil || after == nil {
+		return before, after, nil
+	}
+
+	var oldRow, newRow map[string]interface{}
+
+	err := json.Unmarshal(before, &oldRow)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = json.Unmarshal(after, &newRow)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var (
+		oldValues = map[string]interface{}{}
+		newValues = map[string]interface{}{}
+	)
+
+	for key, value := range newRow {
+		if !reflect.DeepEqual(oldRow[key], value) {
+			oldValues[key] = oldRow[key]
+			newValues[key] = value
+		}
+	}
+
+	for key, value := range oldRow {
+		if _, ok := newRow[key]; !ok {
+			oldValues[key] = value
+			newValues[key] = nil
+		}
+	}
+
+	if len(newValues) <= 0 {
+		return nil, nil, nil
+	}
+
+	before, err = json.Marshal(oldValues)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	after, err = json.Marshal(newValues)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return before, after, nil
+}
@@ -0,0 +1,86 @@
+package barcode
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	EAN8    = "EAN-8"
+	EAN13   = "EAN-13"
+	UPCA    = "UPC-A"
+	Code128 = "Code128"
+)
+
+const code128MaxLength = 48
+
+// Validate detects the symbology of code and verifies its check digit.
+// Numeric codes of GTIN length must carry a valid GS1 check digit, anything
+// else printable is accepted as Code128.
+func Validate(code string) (string, error) {
+
+	if len(code) <= 0 {
+		return "", errors.New("barcode is required")
+	}
+
+	if isDigits(code) {
+		var symbology string
+
+		switch len(code) {
+		case 8:
+			symbology = EAN8
+		case 12:
+			symbology = UPCA
+		case 13:
+			symbology = EAN13
+		}
+
+		if symbology != "" {
+			if CheckDigit(code[:len(code)-1]) != int(code[len(code)-1]-'0') {
+				return "", fmt.Errorf("barcode %s has invalid %s check digit", code, symbology)
+			}
+
+			return symbology, nil
+		}
+	}
+
+	if len(code) > code128MaxLength {
+		return "", fmt.Errorf("barcode %s is longer than %d characters", code, code128MaxLength)
+	}
+
+	for _, ch := range code {
+		if ch < 32 || ch > 126 {
+			return "", fmt.Errorf("barcode %s contains characters not encodable in %s", code, Code128)
+		}
+	}
+
+	return Code128, nil
+}
+
+// CheckDigit computes the GS1 modulo-10 check digit for the given payload
+// (the code without its last digit).
+func CheckDigit(payload string) int {
+
+	var sum int
+
+	for i := 0; i < len(payload); i++ {
+		digit := int(payload[len(payload)-1-i] - '0')
+		if i%2 == 0 {
+			digit *= 3
+		}
+		sum += digit
+	}
+
+	return (10 - sum%10) % 10
+}
+
+func isDigits(code string) bool {
+
+	for i := 0; i < len(code); i++ {
+		if code[i] < '0' || code[i] > '9' {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,172 @@
+package barcode
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckDigit(t *testing.T) {
+
+	tests := []struct {
+		payload string
+		want    int
+	}{
+		{"400638133393", 1},
+		{"9638507", 4},
+		{"03600029145", 2},
+		{"478000000001", 4},
+		{"211234500000", 8},
+		{"", 0},
+	}
+
+	for _, tt := range tests {
+		if got := CheckDigit(tt.payload); got != tt.want {
+			t.Errorf("CheckDigit(%q) = %d, want %d", tt.payload, got, tt.want)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+
+	tests := []struct {
+		code    string
+		want    string
+		wantErr bool
+	}{
+		{"4006381333931", EAN13, false},
+		{"96385074", EAN8, false},
+		{"036000291452", UPCA, false},
+		{"4006381333932", "", true},
+		{"96385075", "", true},
+		{"036000291453", "", true},
+		{"ABC-123", Code128, false},
+		{"12345", Code128, false},
+		{strings.Repeat("A", 48), Code128, false},
+		{strings.Repeat("A", 49), "", true},
+		{"AB\x01C", "", true},
+		{"штрих", "", true},
+		{"", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := Validate(tt.code)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Validate(%q) error = %v, want error %v", tt.code, err, tt.wantErr)
+			continue
+		}
+
+		if got != tt.want {
+			t.Errorf("Validate(%q) = %q, want %q", tt.code, got, tt.want)
+		}
+	}
+}
+
+func TestGenerateEAN13(t *testing.T) {
+
+	tests := []struct {
+		prefix  int
+		item    int64
+		want    string
+		wantErr bool
+	}{
+		{478, 1, "4780000000014", false},
+		{478, 123456, "4780001234562", false},
+		{-1, 1, "", true},
+		{1000, 1, "", true},
+		{478, -1, "", true},
+		{478, 1000000000, "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := GenerateEAN13(tt.prefix, tt.item)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("GenerateEAN13(%d, %d) error = %v, want error %v", tt.prefix, tt.item, err, tt.wantErr)
+			continue
+		}
+
+		if got != tt.want {
+			t.Errorf("GenerateEAN13(%d, %d) = %q, want %q", tt.prefix, tt.item, got, tt.want)
+		}
+
+		if got != "" {
+			if symbology, err := Validate(got); err != nil || symbology != EAN13 {
+				t.Errorf("GenerateEAN13(%d, %d) = %q, which is not a valid EAN-13", tt.prefix, tt.item, got)
+			}
+		}
+	}
+}
+
+func TestGenerateWeighed(t *testing.T) {
+
+	tests := []struct {
+		prefix  string
+		item    int64
+		want    string
+		wantErr bool
+	}{
+		{"21", 12345, "2112345000008", false},
+		{"22", 12345, "2212345000005", false},
+		{"31", 12345, "", true},
+		{"2", 12345, "", true},
+		{"2a", 12345, "", true},
+		{"21", 100000, "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := GenerateWeighed(tt.prefix, tt.item)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("GenerateWeighed(%q, %d) error = %v, want error %v", tt.prefix, tt.item, err, tt.wantErr)
+			continue
+		}
+
+		if got != tt.want {
+			t.Errorf("GenerateWeighed(%q, %d) = %q, want %q", tt.prefix, tt.item, got, tt.want)
+		}
+	}
+}
+
+func TestDecodeEmbedded(t *testing.T) {
+
+	var (
+		weightPrefixes = []string{"21"}
+		pricePrefixes  = []string{"22"}
+	)
+
+	tests := []struct {
+		code    string
+		want    *Embedded
+		wantErr bool
+	}{
+		{
+			code: "2112345012506",
+			want: &Embedded{Prefix: "21", ItemCode: "12345", Kind: EmbeddedWeight, Value: 1250, BaseCode: "2112345000008"},
+		},
+		{
+			code: "2212345123452",
+			want: &Embedded{Prefix: "22", ItemCode: "12345", Kind: EmbeddedPrice, Value: 12345, BaseCode: "2212345000005"},
+		},
+		// Wrong check digit.
+		{code: "2112345012507", wantErr: true},
+		// Not a variable measure prefix.
+		{code: "4006381333931", wantErr: true},
+		// Variable measure, but the prefix is not configured.
+		{code: "2000042000004", wantErr: true},
+		{code: "96385074", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := DecodeEmbedded(tt.code, weightPrefixes, pricePrefixes)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("DecodeEmbedded(%q) error = %v, want error %v", tt.code, err, tt.wantErr)
+			continue
+		}
+
+		if tt.wantErr {
+			continue
+		}
+
+		if *got != *tt.want {
+			t.Errorf("DecodeEmbedded(%q) = %+v, want %+v", tt.code, *got, *tt.want)
+		}
+	}
+}
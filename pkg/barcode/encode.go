@@ -0,0 +1,157 @@
+package barcode
+
+import (
+	"errors"
+)
+
+// Bars is an encoded symbol: one entry per module, true for a dark bar.
+type Bars struct {
+	Symbology string
+	Text      string
+	Modules   []bool
+}
+
+var (
+	eanL = [10]string{"0001101", "0011001", "0010011", "0111101", "0100011", "0110001", "0101111", "0111011", "0110111", "0001011"}
+	eanG = [10]string{"0100111", "0110011", "0011011", "0100001", "0011101", "0111001", "0000101", "0010001", "0001001", "0010111"}
+	eanR = [10]string{"1110010", "1100110", "1101100", "1000010", "1011100", "1001110", "1010000", "1000100", "1001000", "1110100"}
+
+	eanParity = [10]string{"LLLLLL", "LLGLGG", "LLGGLG", "LLGGGL", "LGLLGG", "LGGLLG", "LGGGLL", "LGLGLG", "LGLGGL", "LGGLGL"}
+)
+
+var code128Patterns = [107]string{
+	"212222", "222122", "222221", "121223", "121322", "131222", "122213", "122312", "132212", "221213",
+	"221312", "231212", "112232", "122132", "122231", "113222", "123122", "123221", "223211", "221132",
+	"221231", "213212", "223112", "312131", "311222", "321122", "321221", "312212", "322112", "322211",
+	"212123", "212321", "232121", "111323", "131123", "131321", "112313", "132113", "132311", "211313",
+	"231113", "231311", "112133", "112331", "132131", "113123", "113321", "133121", "313121", "211331",
+	"231131", "213113", "213311", "213131", "311123", "311321", "331121", "312113", "312311", "332111",
+	"314111", "221411", "431111", "111224", "111422", "121124", "121421", "141122", "141221", "112214",
+	"112412", "122114", "122411", "142112", "142211", "241211", "221114", "413111", "241112", "134111",
+	"111242", "121142", "121241", "114212", "124112", "124211", "411212", "421112", "421211", "212141",
+	"214121", "412121", "111143", "111341", "131141", "114113", "114311", "411113", "411311", "113141",
+	"114131", "311141", "411131", "211412", "211214", "211232", "2331112",
+}
+
+const (
+	code128StartB = 104
+	code128Stop   = 106
+)
+
+// Encode validates code and turns it into bars. EAN-13 and UPC-A (as EAN-13
+// with a leading zero) are drawn as EAN-13, everything else as Code128.
+func Encode(code string) (*Bars, error) {
+
+	symbology, err := Validate(code)
+	if err != nil {
+		return nil, err
+	}
+
+	switch symbology {
+	case EAN13:
+		return encodeEAN13(code), nil
+	case UPCA:
+		return encodeEAN13("0" + code), nil
+	default:
+		return encodeCode128(code)
+	}
+}
+
+func encodeEAN13(code string) *Bars {
+
+	var (
+		pattern = "101"
+		parity  = eanParity[code[0]-'0']
+	)
+
+	for i := 1; i <= 6; i++ {
+		digit := code[i] - '0'
+		if parity[i-1] == 'L' {
+			pattern += eanL[digit]
+		} else {
+			pattern += eanG[digit]
+		}
+	}
+
+	pattern += "01010"
+
+	for i := 7; i <= 12; i++ {
+		pattern += eanR[code[i]-'0']
+	}
+
+	pattern += "101"
+
+	return &Bars{
+		Symbology: EAN13,
+		Text:      code,
+		Modules:   patternModules(pattern),
+	}
+}
+
+func encodeCode128(code string) (*Bars, error) {
+
+	var (
+		values   = []int{code128StartB}
+		checksum = code128StartB
+		modules  []bool
+	)
+
+	for i := 0; i < len(code); i++ {
+		if code[i] < 32 || code[i] > 126 {
+			return nil, errors.New("barcode " + code + " is not encodable in Code128 set B")
+		}
+
+		value := int(code[i]) - 32
+		values = append(values, value)
+		checksum += value * (i + 1)
+	}
+
+	values = append(values, checksum%103, code128Stop)
+
+	for _, value := range values {
+		dark := true
+		for _, width := range code128Patterns[value] {
+			for n := 0; n < int(width-'0'); n++ {
+				modules = append(modules, dark)
+			}
+			dark = !dark
+		}
+	}
+
+	return &Bars{
+		Symbology: Code128,
+		Text:      code,
+		Modules:   modules,
+	}, nil
+}
+
+func patternModules(pattern string) []bool {
+
+	modules := make([]bool, len(pattern))
+	for i := range pattern {
+		modules[i] = pattern[i] == '1'
+	}
+
+	return modules
+}
+
+// Runs returns the dark bars as (start module, width) pairs.
+func (b *Bars) Runs() [][2]int {
+
+	var runs [][2]int
+
+	for i := 0; i < len(b.Modules); i++ {
+		if !b.Modules[i] {
+			continue
+		}
+
+		start := i
+		for i < len(b.Modules) && b.Modules[i] {
+			i++
+		}
+
+		runs = append(runs, [2]int{start, i - start})
+	}
+
+	return runs
+}
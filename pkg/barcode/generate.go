@@ -0,0 +1,109 @@
+package barcode
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	EmbeddedWeight = "weight"
+	EmbeddedPrice  = "price"
+)
+
+// Embedded is a variable-measure EAN-13 (prefix 20-29) as printed by scales:
+// two prefix digits, a five digit item code, a five digit value and a check digit.
+type Embedded struct {
+	Prefix   string
+	ItemCode string
+	Kind     string
+	Value    int32
+	BaseCode string
+}
+
+// GenerateEAN13 builds an EAN-13 from a three digit prefix and a nine digit
+// item reference, appending the check digit.
+func GenerateEAN13(prefix int, item int64) (string, error) {
+
+	if prefix < 0 || prefix > 999 {
+		return "", fmt.Errorf("barcode prefix %d is out of range", prefix)
+	}
+
+	if item < 0 || item > 999999999 {
+		return "", fmt.Errorf("barcode item reference %d is out of range", item)
+	}
+
+	payload := fmt.Sprintf("%03d%09d", prefix, item)
+
+	return payload + fmt.Sprint(CheckDigit(payload)), nil
+}
+
+// GenerateWeighed builds the base code of a weighed item, i.e. the code a
+// scale label for it carries with a zero value.
+func GenerateWeighed(prefix string, item int64) (string, error) {
+
+	if len(prefix) != 2 || !isDigits(prefix) || prefix[0] != '2' {
+		return "", fmt.Errorf("weighed barcode prefix %s must be between 20 and 29", prefix)
+	}
+
+	if item < 0 || item > 99999 {
+		return "", fmt.Errorf("weighed item code %d is out of range", item)
+	}
+
+	payload := fmt.Sprintf("%s%05d00000", prefix, item)
+
+	return payload + fmt.Sprint(CheckDigit(payload)), nil
+}
+
+// DecodeEmbedded splits a scale label into its item code and embedded value.
+// Prefixes not listed in weightPrefixes or pricePrefixes are not decoded.
+func DecodeEmbedded(code string, weightPrefixes, pricePrefixes []string) (*Embedded, error) {
+
+	symbology, err := Validate(code)
+	if err != nil {
+		return nil, err
+	}
+
+	if symbology != EAN13 || code[0] != '2' {
+		return nil, errors.New("barcode " + code + " is not a variable measure code")
+	}
+
+	var (
+		prefix = code[:2]
+		kind   string
+		value  int32
+	)
+
+	switch {
+	case contains(weightPrefixes, prefix):
+		kind = EmbeddedWeight
+	case contains(pricePrefixes, prefix):
+		kind = EmbeddedPrice
+	default:
+		return nil, errors.New("barcode prefix " + prefix + " is not configured for embedded values")
+	}
+
+	for i := 7; i < 12; i++ {
+		value = value*10 + int32(code[i]-'0')
+	}
+
+	base := code[:7] + "00000"
+
+	return &Embedded{
+		Prefix:   prefix,
+		ItemCode: code[2:7],
+		Kind:     kind,
+		Value:    value,
+		BaseCode: base + fmt.Sprint(CheckDigit(base)),
+	}, nil
+}
+
+func contains(list []string, value string) bool {
+
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+
+	return false
+}
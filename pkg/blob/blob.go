@@ -0,0 +1,37 @@
+package blob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"market/config"
+)
+
+const (
+	BackendLocal = "local"
+	BackendS3    = "s3"
+)
+
+var ErrNotFound = errors.New("blob not found")
+
+// StorageI keeps binary objects such as product images under string keys.
+type StorageI interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// New returns the backend selected by cfg.BlobBackend.
+func New(cfg *config.Config) (StorageI, error) {
+
+	switch cfg.BlobBackend {
+	case "", BackendLocal:
+		return NewLocal(cfg.BlobLocalPath)
+	case BackendS3:
+		return NewS3(cfg.S3Endpoint, cfg.S3Region, cfg.S3Bucket, cfg.S3AccessKey, cfg.S3SecretKey)
+	default:
+		return nil, fmt.Errorf("unknown blob backend %q", cfg.BlobBackend)
+	}
+}
@@ -0,0 +1,116 @@
+package blob
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLocalRoundTrip(t *testing.T) {
+
+	var (
+		ctx  = context.Background()
+		root = t.TempDir()
+		key  = "product/1/image.jpg"
+	)
+
+	storage, err := NewLocal(root)
+	if err != nil {
+		t.Fatalf("NewLocal() error = %v", err)
+	}
+
+	err = storage.Put(ctx, key, strings.NewReader("jpeg"), 4, "image/jpeg")
+	if err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+
+	r, err := storage.Get(ctx, key)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+
+	body, _ := io.ReadAll(r)
+	r.Close()
+
+	if string(body) != "jpeg" {
+		t.Errorf("Get() = %q, want %q", body, "jpeg")
+	}
+
+	entries, _ := os.ReadDir(filepath.Join(root, "product", "1"))
+	if len(entries) != 1 {
+		t.Errorf("Put() left %d files behind, want the blob only", len(entries))
+	}
+
+	err = storage.Delete(ctx, key)
+	if err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+
+	if _, err = storage.Get(ctx, key); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get() after Delete() error = %v, want ErrNotFound", err)
+	}
+
+	if err = storage.Delete(ctx, key); err != nil {
+		t.Errorf("Delete() of a missing blob error = %v, want nil", err)
+	}
+}
+
+func TestLocalKeyTraversal(t *testing.T) {
+
+	var (
+		ctx    = context.Background()
+		parent = t.TempDir()
+		root   = filepath.Join(parent, "uploads")
+	)
+
+	storage, err := NewLocal(root)
+	if err != nil {
+		t.Fatalf("NewLocal() error = %v", err)
+	}
+
+	tests := []struct {
+		key     string
+		wantErr bool
+	}{
+		{key: "product/1/image.jpg"},
+		{key: "product/../image.jpg"},
+		{key: "/product/image.jpg"},
+		{key: "../secret", wantErr: true},
+		{key: "product/../../secret", wantErr: true},
+		{key: "../uploads-other/secret", wantErr: true},
+		{key: "..", wantErr: true},
+		{key: ".", wantErr: true},
+		{key: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+
+			err := storage.Put(ctx, tt.key, strings.NewReader("x"), 1, "text/plain")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Put(%q) error = %v, want error %v", tt.key, err, tt.wantErr)
+			}
+
+			if !tt.wantErr {
+				return
+			}
+
+			if _, err = storage.Get(ctx, tt.key); err == nil || errors.Is(err, ErrNotFound) {
+				t.Errorf("Get(%q) error = %v, want the key rejected", tt.key, err)
+			}
+
+			if err = storage.Delete(ctx, tt.key); err == nil {
+				t.Errorf("Delete(%q) error = nil, want the key rejected", tt.key)
+			}
+		})
+	}
+
+	entries, _ := os.ReadDir(parent)
+	if len(entries) != 1 {
+		t.Errorf("Put() wrote %d entries next to the root, want none", len(entries)-1)
+	}
+}
@@ -0,0 +1,251 @@
+package blob
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// s3 talks to any S3-compatible service (AWS, MinIO, Ceph) using path-style
+// addressing and Signature Version 4.
+type s3 struct {
+	client    *http.Client
+	endpoint  *url.URL
+	region    string
+	bucket    string
+	accessKey string
+	secretKey string
+}
+
+func NewS3(endpoint, region, bucket, accessKey, secretKey string) (StorageI, error) {
+
+	if endpoint == "" || bucket == "" {
+		return nil, errors.New("s3 blob backend requires endpoint and bucket")
+	}
+
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	return &s3{
+		client:    &http.Client{Timeout: time.Minute},
+		endpoint:  u,
+		region:    region,
+		bucket:    bucket,
+		accessKey: accessKey,
+		secretKey: secretKey,
+	}, nil
+}
+
+func (s *s3) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+
+	req, err := s.request(ctx, http.MethodPut, key, r)
+	if err != nil {
+		return err
+	}
+
+	req.ContentLength = size
+	req.Header.Set("Content-Type", contentType)
+
+	resp, err := s.do(req)
+	if err != nil {
+		return err
+	}
+
+	return resp.Body.Close()
+}
+
+func (s *s3) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+
+	req, err := s.request(ctx, http.MethodGet, key, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Body, nil
+}
+
+func (s *s3) Delete(ctx context.Context, key string) error {
+
+	req, err := s.request(ctx, http.MethodDelete, key, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.do(req)
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	return resp.Body.Close()
+}
+
+func (s *s3) request(ctx context.Context, method, key string, body io.Reader) (*http.Request, error) {
+
+	// RawPath keeps the SigV4 escaping, Path must be its unescaped form or
+	// net/url escapes the key a second time.
+	u := *s.endpoint
+	u.Path = "/" + s.bucket + "/" + key
+	u.RawPath = "/" + awsEscape(s.bucket) + "/" + awsEscapePath(key)
+
+	return http.NewRequestWithContext(ctx, method, u.String(), body)
+}
+
+func (s *s3) do(req *http.Request) (*http.Response, error) {
+
+	s.sign(req, time.Now().UTC())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return nil, ErrNotFound
+	}
+
+	if resp.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		resp.Body.Close()
+		return nil, fmt.Errorf("s3 %s %s: %s: %s", req.Method, req.URL.Path, resp.Status, message)
+	}
+
+	return resp, nil
+}
+
+// sign adds a SigV4 Authorization header. The payload is left unsigned so
+// uploads can be streamed without hashing them first.
+func (s *s3) sign(req *http.Request, now time.Time) {
+
+	var (
+		amzDate     = now.Format("20060102T150405Z")
+		payloadHash = "UNSIGNED-PAYLOAD"
+	)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	signature, scope, signedHeaders := signV4(&signRequest{
+		method: req.Method,
+		path:   req.URL.EscapedPath(),
+		query:  req.URL.RawQuery,
+		headers: [][2]string{
+			{"host", req.URL.Host},
+			{"x-amz-content-sha256", payloadHash},
+			{"x-amz-date", amzDate},
+		},
+		payloadHash: payloadHash,
+	}, now, s.region, "s3", s.secretKey)
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.accessKey, scope, signedHeaders, signature,
+	))
+}
+
+// signRequest is what SigV4 signs of a request. The path and query must be
+// escaped already, and the headers lower case and sorted by name.
+type signRequest struct {
+	method      string
+	path        string
+	query       string
+	headers     [][2]string
+	payloadHash string
+}
+
+// signV4 returns the signature of req at now, with the credential scope and
+// the signed header list the Authorization header carries alongside it.
+func signV4(req *signRequest, now time.Time, region, service, secretKey string) (string, string, string) {
+
+	var (
+		amzDate = now.Format("20060102T150405Z")
+		day     = now.Format("20060102")
+		scope   = day + "/" + region + "/" + service + "/aws4_request"
+
+		canonicalHeaders strings.Builder
+		names            []string
+	)
+
+	for _, header := range req.headers {
+		canonicalHeaders.WriteString(header[0] + ":" + header[1] + "\n")
+		names = append(names, header[0])
+	}
+
+	var (
+		signedHeaders    = strings.Join(names, ";")
+		canonicalRequest = strings.Join([]string{
+			req.method,
+			req.path,
+			req.query,
+			canonicalHeaders.String(),
+			signedHeaders,
+			req.payloadHash,
+		}, "\n")
+		requestHash  = sha256.Sum256([]byte(canonicalRequest))
+		stringToSign = "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+	)
+
+	signature := hex.EncodeToString(hmacSHA256(signingKey(secretKey, day, region, service), stringToSign))
+
+	return signature, scope, signedHeaders
+}
+
+// signingKey derives the key of one day, region and service from the secret.
+func signingKey(secretKey, day, region, service string) []byte {
+
+	key := hmacSHA256([]byte("AWS4"+secretKey), day)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, service)
+
+	return hmacSHA256(key, "aws4_request")
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func awsEscapePath(key string) string {
+
+	segments := strings.Split(key, "/")
+	for i := range segments {
+		segments[i] = awsEscape(segments[i])
+	}
+
+	return strings.Join(segments, "/")
+}
+
+// awsEscape percent-encodes everything except the RFC 3986 unreserved set.
+func awsEscape(value string) string {
+
+	var out strings.Builder
+
+	for i := 0; i < len(value); i++ {
+		ch := value[i]
+		if ch >= 'A' && ch <= 'Z' || ch >= 'a' && ch <= 'z' || ch >= '0' && ch <= '9' || ch == '-' || ch == '_' || ch == '.' || ch == '~' {
+			out.WriteByte(ch)
+		} else {
+			fmt.Fprintf(&out, "%%%02X", ch)
+		}
+	}
+
+	return out.String()
+}
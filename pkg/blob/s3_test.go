@@ -0,0 +1,129 @@
+package blob
+
+import (
+	"context"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// The vectors come from the AWS Signature Version 4 documentation and test
+// suite, signed with its example credentials.
+const (
+	exampleAccessKey = "AKIDEXAMPLE"
+	exampleSecretKey = "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY"
+	emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+)
+
+func TestSigningKey(t *testing.T) {
+
+	got := signingKey(exampleSecretKey, "20120215", "us-east-1", "iam")
+
+	if want := "f4780e2d9f65fa895f9c67b32ce1baf0b0d8a43505a000a1a9e090d414db404d"; hex.EncodeToString(got) != want {
+		t.Errorf("signingKey() = %s, want %s", hex.EncodeToString(got), want)
+	}
+}
+
+func TestSignV4(t *testing.T) {
+
+	now := time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{name: "get-vanilla", want: "5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31"},
+		{name: "get-vanilla-query-order-key-case", query: "Param1=value1&Param2=value2", want: "b97d918cfa904a5beff61c982a1b6f458b799221646efd99d3219ec94cdf2500"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			signature, scope, signedHeaders := signV4(&signRequest{
+				method: http.MethodGet,
+				path:   "/",
+				query:  tt.query,
+				headers: [][2]string{
+					{"host", "example.amazonaws.com"},
+					{"x-amz-date", "20150830T123600Z"},
+				},
+				payloadHash: emptyPayloadHash,
+			}, now, "us-east-1", "service", exampleSecretKey)
+
+			if signature != tt.want {
+				t.Errorf("signature = %s, want %s", signature, tt.want)
+			}
+
+			if want := "20150830/us-east-1/service/aws4_request"; scope != want {
+				t.Errorf("scope = %s, want %s", scope, want)
+			}
+
+			if want := "host;x-amz-date"; signedHeaders != want {
+				t.Errorf("signed headers = %s, want %s", signedHeaders, want)
+			}
+		})
+	}
+}
+
+func TestSign(t *testing.T) {
+
+	storage, err := NewS3("https://s3.example.com", "us-east-1", "market", exampleAccessKey, exampleSecretKey)
+	if err != nil {
+		t.Fatalf("NewS3() error = %v", err)
+	}
+
+	s := storage.(*s3)
+
+	req, err := s.request(context.Background(), http.MethodGet, "product/1/Nonvoy non+1.jpg", nil)
+	if err != nil {
+		t.Fatalf("request() error = %v", err)
+	}
+
+	if want := "/market/product/1/Nonvoy%20non%2B1.jpg"; req.URL.EscapedPath() != want {
+		t.Errorf("path = %s, want %s", req.URL.EscapedPath(), want)
+	}
+
+	s.sign(req, time.Date(2026, 10, 19, 8, 30, 0, 0, time.UTC))
+
+	if got := req.Header.Get("X-Amz-Date"); got != "20261019T083000Z" {
+		t.Errorf("X-Amz-Date = %s, want 20261019T083000Z", got)
+	}
+
+	if got := req.Header.Get("X-Amz-Content-Sha256"); got != "UNSIGNED-PAYLOAD" {
+		t.Errorf("X-Amz-Content-Sha256 = %s, want UNSIGNED-PAYLOAD", got)
+	}
+
+	auth := req.Header.Get("Authorization")
+	prefix := "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20261019/us-east-1/s3/aws4_request, SignedHeaders=host;x-amz-content-sha256;x-amz-date, Signature="
+
+	if !strings.HasPrefix(auth, prefix) || len(auth) != len(prefix)+64 {
+		t.Errorf("Authorization = %s, want %s and 64 hex digits", auth, prefix)
+	}
+}
+
+func TestAwsEscape(t *testing.T) {
+
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"photo-1_a.b~c", "photo-1_a.b~c"},
+		{"a b", "a%20b"},
+		{"a+b=c&d", "a%2Bb%3Dc%26d"},
+		{"a/b", "a%2Fb"},
+		{"qo‘y", "qo%E2%80%98y"},
+	}
+
+	for _, tt := range tests {
+		if got := awsEscape(tt.value); got != tt.want {
+			t.Errorf("awsEscape(%q) = %s, want %s", tt.value, got, tt.want)
+		}
+	}
+
+	if got := awsEscapePath("a b/c d"); got != "a%20b/c%20d" {
+		t.Errorf("awsEscapePath() = %s, want a%%20b/c%%20d", got)
+	}
+}
@@ -1,8 +1,18 @@
 package helper
 
 import (
+	"encoding/json"
 	"errors"
+	"math"
+	"reflect"
 	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+
+	"market/pkg/apperr"
+	"market/pkg/barcode"
 )
 
 func ValidPinfl(pinfl string) error {
@@ -49,12 +59,12 @@ func IsValidLogin(login string) bool {
 
 // IsValidUUID ...
 func IsValidUUID(uuid string) bool {
-	r := regexp.MustCompile("^[a-fA-F0-9]{8}-[a-fA-F0-9]{4}-4[a-fA-F0-9]{3}-[8|9|aA|bB][a-fA-F0-9]{3}-[a-fA-F0-9]{12}$")
+	r := regexp.MustCompile("^[a-fA-F0-9]{8}-[a-fA-F0-9]{4}-4[a-fA-F0-9]{3}-[89aAbB][a-fA-F0-9]{3}-[a-fA-F0-9]{12}$")
 	return r.MatchString(uuid)
 }
 
 func IsValidUUIDV1(uuid string) bool {
-	r := regexp.MustCompile("^[a-fA-F0-9]{8}-[a-fA-F0-9]{4}-[a-fA-F0-9]{4}-[8|9|aA|bB][a-fA-F0-9]{3}-[a-fA-F0-9]{12}$")
+	r := regexp.MustCompile("^[a-fA-F0-9]{8}-[a-fA-F0-9]{4}-[a-fA-F0-9]{4}-[89aAbB][a-fA-F0-9]{3}-[a-fA-F0-9]{12}$")
 	return r.MatchString(uuid)
 }
 
@@ -63,3 +73,198 @@ func IsValidPrice(price string) bool {
 	r := regexp.MustCompile(`^\d+$`)
 	return r.MatchString(price)
 }
+
+// RegisterValidations adds the rules of this package to v, for use in the
+// binding tags of api/models, and names fields after their json tags.
+func RegisterValidations(v *validator.Validate) {
+
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+
+		return name
+	})
+
+	_ = v.RegisterValidation("phone", func(fl validator.FieldLevel) bool {
+		return IsValidPhone(fl.Field().String())
+	})
+
+	_ = v.RegisterValidation("barcode", func(fl validator.FieldLevel) bool {
+		_, err := barcode.Validate(fl.Field().String())
+		return err == nil
+	})
+}
+
+// ValidationError turns an error of binding a request into a validation
+// error that lists every field that is wrong.
+func ValidationError(err error) error {
+
+	var (
+		fieldErrors validator.ValidationErrors
+		typeError   *json.UnmarshalTypeError
+	)
+
+	switch {
+	case errors.As(err, &fieldErrors):
+		var fields []apperr.FieldError
+
+		for _, fieldError := range fieldErrors {
+			fields = append(fields, apperr.FieldError{
+				Field:   fieldPath(fieldError),
+				Message: fieldMessage(fieldError),
+			})
+		}
+
+		return apperr.Validation("invalid request", fields...)
+
+	case errors.As(err, &typeError):
+		return apperr.Validation("invalid request", apperr.FieldError{
+			Field:   typeError.Field,
+			Message: "must be a " + typeError.Type.String(),
+		})
+	}
+
+	return apperr.Validation(err.Error())
+}
+
+// ValidateVar checks a single value against tag, as for the fields of a
+// patch, and returns what is wrong with it.
+func ValidateVar(v *validator.Validate, value interface{}, tag string) (string, bool) {
+
+	var fieldErrors validator.ValidationErrors
+
+	err := v.Var(value, tag)
+	if errors.As(err, &fieldErrors) && len(fieldErrors) > 0 {
+		return fieldMessage(fieldErrors[0]), false
+	} else if err != nil {
+		return err.Error(), false
+	}
+
+	return "", true
+}
+
+// productPatchRules are the binding rules of UpdateProduct for the fields a
+// patch may carry, along with the JSON type each value has to be.
+var productPatchRules = map[string]struct {
+	kind reflect.Kind
+	tag  string
+}{
+	"name":        {reflect.String, "required,max=55"},
+	"barcode":     {reflect.String, "barcode"},
+	"price":       {reflect.Float64, "min=0"},
+	"category_id": {reflect.String, "omitempty,uuid"},
+	"parent_id":   {reflect.String, "omitempty,uuid"},
+	"size":        {reflect.String, "max=20"},
+	"color":       {reflect.String, "max=30"},
+	"volume":      {reflect.String, "max=20"},
+	"serialized":  {reflect.Bool, ""},
+}
+
+// ValidateProductPatch checks every field of a product patch against the
+// rules of v and reports all that are wrong at once.
+func ValidateProductPatch(v *validator.Validate, fields map[string]interface{}) error {
+
+	var (
+		names []string
+		errs  []apperr.FieldError
+	)
+
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		rule, ok := productPatchRules[name]
+		if !ok {
+			continue
+		}
+
+		value := fields[name]
+		if value == nil || reflect.TypeOf(value).Kind() != rule.kind {
+			errs = append(errs, apperr.FieldError{Field: name, Message: "must be a " + rule.kind.String()})
+			continue
+		}
+
+		if rule.kind == reflect.Float64 && value.(float64) != math.Trunc(value.(float64)) {
+			errs = append(errs, apperr.FieldError{Field: name, Message: "must be a whole number"})
+			continue
+		}
+
+		if rule.tag == "" || v == nil {
+			continue
+		}
+
+		message, ok := ValidateVar(v, value, rule.tag)
+		if !ok {
+			errs = append(errs, apperr.FieldError{Field: name, Message: message})
+		}
+	}
+
+	if len(errs) > 0 {
+		return apperr.Validation("invalid request", errs...)
+	}
+
+	return nil
+}
+
+// fieldPath is the namespace of the field without the name of the struct,
+// like barcodes[0].barcode.
+func fieldPath(fieldError validator.FieldError) string {
+
+	path := strings.SplitN(fieldError.Namespace(), ".", 2)
+	if len(path) < 2 {
+		return fieldError.Field()
+	}
+
+	return path[1]
+}
+
+func fieldMessage(fieldError validator.FieldError) string {
+
+	var (
+		param = fieldError.Param()
+		kind  = fieldError.Kind()
+	)
+
+	switch fieldError.Tag() {
+	case "required":
+		return "is required"
+	case "min", "max", "gt", "gte", "lt", "lte":
+		comparison := map[string]string{
+			"min": "at least",
+			"gte": "at least",
+			"max": "at most",
+			"lte": "at most",
+			"gt":  "greater than",
+			"lt":  "less than",
+		}[fieldError.Tag()]
+
+		switch kind {
+		case reflect.String:
+			return "must be " + comparison + " " + param + " characters long"
+		case reflect.Slice, reflect.Map:
+			return "must have " + comparison + " " + param + " items"
+		}
+
+		return "must be " + comparison + " " + param
+	case "uuid":
+		return "must be a UUID"
+	case "phone":
+		return "must be a phone number like +998901234567"
+	case "barcode":
+		value, _ := fieldError.Value().(string)
+		_, err := barcode.Validate(value)
+		if err != nil {
+			return err.Error()
+		}
+	case "datetime":
+		return "must be a date like " + param
+	case "oneof":
+		return "must be one of " + strings.Join(strings.Fields(param), ", ")
+	}
+
+	return "is invalid"
+}
@@ -0,0 +1,105 @@
+package invoice
+
+import (
+	"encoding/xml"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Line is one position of a supplier invoice. Price is per invoiced unit.
+type Line struct {
+	Row      int
+	Barcode  string
+	Name     string
+	Quantity float64
+	Price    float64
+}
+
+type Invoice struct {
+	Number string
+	Lines  []*Line
+}
+
+type ublIdentifier struct {
+	Value    string `xml:",chardata"`
+	SchemeId string `xml:"schemeID,attr"`
+}
+
+type ublInvoice struct {
+	XMLName xml.Name
+	Id      string `xml:"ID"`
+	Lines   []struct {
+		Quantity string `xml:"InvoicedQuantity"`
+		Item     struct {
+			Name       string        `xml:"Name"`
+			StandardId ublIdentifier `xml:"StandardItemIdentification>ID"`
+			SellersId  ublIdentifier `xml:"SellersItemIdentification>ID"`
+		} `xml:"Item"`
+		Price struct {
+			Amount       string `xml:"PriceAmount"`
+			BaseQuantity string `xml:"BaseQuantity"`
+		} `xml:"Price"`
+	} `xml:"InvoiceLine"`
+}
+
+// ParseUBL reads an OASIS UBL 2.x Invoice. The barcode is taken from the
+// standard (GTIN) item identification and falls back to the seller's id.
+func ParseUBL(r io.Reader) (*Invoice, error) {
+
+	var doc ublInvoice
+
+	err := xml.NewDecoder(r).Decode(&doc)
+	if err != nil {
+		return nil, err
+	}
+
+	if doc.XMLName.Local != "Invoice" {
+		return nil, errors.New("ubl: root element must be Invoice, got " + doc.XMLName.Local)
+	}
+
+	var resp = &Invoice{
+		Number: strings.TrimSpace(doc.Id),
+	}
+
+	for i, line := range doc.Lines {
+		var (
+			code = strings.TrimSpace(line.Item.StandardId.Value)
+			item = &Line{
+				Row:  i + 1,
+				Name: strings.TrimSpace(line.Item.Name),
+			}
+		)
+
+		if code == "" {
+			code = strings.TrimSpace(line.Item.SellersId.Value)
+		}
+		item.Barcode = code
+
+		item.Quantity, err = parseAmount(line.Quantity)
+		if err != nil {
+			return nil, errors.New("ubl: line " + strconv.Itoa(i+1) + ": invalid quantity")
+		}
+
+		price, err := parseAmount(line.Price.Amount)
+		if err != nil {
+			return nil, errors.New("ubl: line " + strconv.Itoa(i+1) + ": invalid price")
+		}
+
+		// PriceAmount is given per BaseQuantity units, which defaults to one.
+		if base, err := parseAmount(line.Price.BaseQuantity); err == nil && base > 0 {
+			price /= base
+		}
+
+		item.Price = price
+
+		resp.Lines = append(resp.Lines, item)
+	}
+
+	return resp, nil
+}
+
+func parseAmount(value string) (float64, error) {
+	return strconv.ParseFloat(strings.TrimSpace(value), 64)
+}
@@ -0,0 +1,81 @@
+package invoice
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseUBL(t *testing.T) {
+
+	file, err := os.Open(filepath.Join("testdata", "invoice.xml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	got, err := ParseUBL(file)
+	if err != nil {
+		t.Fatalf("ParseUBL() error = %v", err)
+	}
+
+	want := &Invoice{
+		Number: "INV-2026-0142",
+		Lines: []*Line{
+			{Row: 1, Barcode: "4780001000017", Name: "Sut 1L", Quantity: 24, Price: 12500},
+			{Row: 2, Barcode: "CHEESE-KG", Name: "Pishloq", Quantity: 2.5, Price: 90000},
+			{Row: 3, Barcode: "", Name: "Paket", Quantity: 10, Price: 500},
+		},
+	}
+
+	if got.Number != want.Number {
+		t.Errorf("Number = %q, want %q", got.Number, want.Number)
+	}
+
+	if len(got.Lines) != len(want.Lines) {
+		t.Fatalf("got %d lines, want %d", len(got.Lines), len(want.Lines))
+	}
+
+	for i := range want.Lines {
+		if !reflect.DeepEqual(got.Lines[i], want.Lines[i]) {
+			t.Errorf("line %d = %+v, want %+v", i+1, *got.Lines[i], *want.Lines[i])
+		}
+	}
+}
+
+func TestParseUBLErrors(t *testing.T) {
+
+	tests := []struct {
+		file    string
+		wantErr string
+	}{
+		{file: "credit_note.xml", wantErr: "root element must be Invoice, got CreditNote"},
+		{file: "bad_quantity.xml", wantErr: "line 1: invalid quantity"},
+		{file: "bad_price.xml", wantErr: "line 1: invalid price"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+
+			file, err := os.Open(filepath.Join("testdata", tt.file))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer file.Close()
+
+			_, err = ParseUBL(file)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ParseUBL() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseUBLNotXML(t *testing.T) {
+
+	if _, err := ParseUBL(strings.NewReader("barcode;quantity\n")); err == nil {
+		t.Error("ParseUBL() error = nil, want an error")
+	}
+}
@@ -0,0 +1,63 @@
+package jwt
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSignParse(t *testing.T) {
+
+	var (
+		secret = []byte("secret")
+		now    = time.Now().Unix()
+		claims = &Claims{Subject: "user", SessionId: "session", IssuedAt: now, ExpiresAt: now + 60}
+	)
+
+	token, err := Sign(claims, secret)
+	if err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+
+	parts := strings.Split(token, ".")
+
+	tamperedPayload, _ := Sign(&Claims{Subject: "admin", ExpiresAt: now + 60}, secret)
+	expired, _ := Sign(&Claims{Subject: "user", IssuedAt: now - 120, ExpiresAt: now - 60}, secret)
+	otherHeader := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none","typ":"JWT"}`))
+	badPayload := base64.RawURLEncoding.EncodeToString([]byte("not json"))
+
+	tests := []struct {
+		name    string
+		token   string
+		secret  []byte
+		want    *Claims
+		wantErr error
+	}{
+		{name: "valid", token: token, secret: secret, want: claims},
+		{name: "other secret", token: token, secret: []byte("other"), wantErr: ErrSignature},
+		{name: "payload swapped", token: parts[0] + "." + strings.Split(tamperedPayload, ".")[1] + "." + parts[2], secret: secret, wantErr: ErrSignature},
+		{name: "signature cut", token: parts[0] + "." + parts[1] + ".", secret: secret, wantErr: ErrSignature},
+		{name: "expired", token: expired, secret: secret, wantErr: ErrExpired},
+		{name: "other header", token: otherHeader + "." + parts[1] + "." + parts[2], secret: secret, wantErr: ErrMalformed},
+		{name: "payload not json", token: header + "." + badPayload + "." + signature(header+"."+badPayload, secret), secret: secret, wantErr: ErrMalformed},
+		{name: "payload not base64", token: header + ".!!!." + signature(header+".!!!", secret), secret: secret, wantErr: ErrMalformed},
+		{name: "two parts", token: parts[0] + "." + parts[1], secret: secret, wantErr: ErrMalformed},
+		{name: "empty", token: "", secret: secret, wantErr: ErrMalformed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			got, err := Parse(tt.token, tt.secret)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Parse() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if tt.want != nil && *got != *tt.want {
+				t.Errorf("Parse() = %+v, want %+v", *got, *tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package label
+
+import (
+	"fmt"
+	"html"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+
+	"market/pkg/barcode"
+)
+
+const (
+	quietModules = 10
+	barHeight    = 60
+	textHeight   = 14
+)
+
+// WriteSVG draws the barcode with its human readable text underneath.
+func WriteSVG(w io.Writer, code string) error {
+
+	bars, err := barcode.Encode(code)
+	if err != nil {
+		return err
+	}
+
+	var (
+		width  = len(bars.Modules) + 2*quietModules
+		height = barHeight + textHeight
+	)
+
+	_, err = fmt.Fprintf(w, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`, width*2, height*2, width, height)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, `<rect width="%d" height="%d" fill="#fff"/>`, width, height)
+
+	for _, run := range bars.Runs() {
+		fmt.Fprintf(w, `<rect x="%d" y="0" width="%d" height="%d" fill="#000"/>`, quietModules+run[0], run[1], barHeight)
+	}
+
+	_, err = fmt.Fprintf(w, `<text x="%d" y="%d" font-family="monospace" font-size="11" text-anchor="middle">%s</text></svg>`,
+		width/2, height-2, html.EscapeString(bars.Text))
+
+	return err
+}
+
+// WritePNG draws the barcode bars only, scale pixels per module.
+func WritePNG(w io.Writer, code string, scale int) error {
+
+	bars, err := barcode.Encode(code)
+	if err != nil {
+		return err
+	}
+
+	if scale <= 0 {
+		scale = 2
+	}
+
+	var (
+		width = (len(bars.Modules) + 2*quietModules) * scale
+		img   = image.NewGray(image.Rect(0, 0, width, barHeight*scale))
+	)
+
+	for i := range img.Pix {
+		img.Pix[i] = 0xff
+	}
+
+	for _, run := range bars.Runs() {
+		for x := (quietModules + run[0]) * scale; x < (quietModules+run[0]+run[1])*scale; x++ {
+			for y := 0; y < barHeight*scale; y++ {
+				img.SetGray(x, y, color.Gray{Y: 0})
+			}
+		}
+	}
+
+	return png.Encode(w, img)
+}
@@ -0,0 +1,98 @@
+package label
+
+import (
+	"fmt"
+	"strconv"
+)
+
+const (
+	FormatSVG = "svg"
+	FormatPNG = "png"
+	FormatPDF = "pdf"
+	FormatZPL = "zpl"
+)
+
+// MaxLabels caps the labels of one print job, copies included, so a single
+// request cannot make the server render without bound.
+const MaxLabels = 5000
+
+// Label is a single price tag: what is printed on it and how many copies.
+type Label struct {
+	Name    string
+	Price   int32
+	Barcode string
+	Copies  int
+}
+
+// ContentType returns the MIME type of the given output format.
+func ContentType(format string) string {
+
+	switch format {
+	case FormatSVG:
+		return "image/svg+xml"
+	case FormatPNG:
+		return "image/png"
+	case FormatPDF:
+		return "application/pdf"
+	case FormatZPL:
+		return "application/zpl"
+	default:
+		return "application/octet-stream"
+	}
+}
+
+// FormatPrice groups the price digits by thousands, e.g. 12500 -> "12 500".
+func FormatPrice(price int32) string {
+
+	var (
+		digits = strconv.FormatInt(int64(price), 10)
+		sign   string
+		out    string
+	)
+
+	if price < 0 {
+		sign, digits = "-", digits[1:]
+	}
+
+	for len(digits) > 3 {
+		out = " " + digits[len(digits)-3:] + out
+		digits = digits[:len(digits)-3]
+	}
+
+	return fmt.Sprint(sign, digits, out)
+}
+
+// Count is the number of labels printed for labels, copies included.
+func Count(labels ...Label) int {
+
+	var count int
+
+	for _, l := range labels {
+		if l.Copies <= 0 {
+			count++
+		} else {
+			count += l.Copies
+		}
+	}
+
+	return count
+}
+
+// expand repeats every label by its copy count.
+func expand(labels []Label) []Label {
+
+	var out []Label
+
+	for _, l := range labels {
+		copies := l.Copies
+		if copies <= 0 {
+			copies = 1
+		}
+
+		for i := 0; i < copies; i++ {
+			out = append(out, l)
+		}
+	}
+
+	return out
+}
@@ -0,0 +1,220 @@
+package label
+
+import (
+	"bytes"
+	"image/png"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestFormatPrice(t *testing.T) {
+
+	tests := []struct {
+		price int32
+		want  string
+	}{
+		{0, "0"},
+		{999, "999"},
+		{1000, "1 000"},
+		{12500, "12 500"},
+		{1234567, "1 234 567"},
+		{-12500, "-12 500"},
+		{-999, "-999"},
+	}
+
+	for _, tt := range tests {
+		if got := FormatPrice(tt.price); got != tt.want {
+			t.Errorf("FormatPrice(%d) = %q, want %q", tt.price, got, tt.want)
+		}
+	}
+}
+
+func TestCount(t *testing.T) {
+
+	labels := []Label{{Copies: 3}, {Copies: 0}, {Copies: -2}, {Copies: 1}}
+
+	if got := Count(labels...); got != 6 {
+		t.Errorf("Count() = %d, want 6", got)
+	}
+
+	if got := len(expand(labels)); got != 6 {
+		t.Errorf("len(expand()) = %d, want 6", got)
+	}
+}
+
+func TestWriteZPL(t *testing.T) {
+
+	tests := []struct {
+		name      string
+		label     Label
+		wantField string
+		wantErr   bool
+	}{
+		{name: "ean-13", label: Label{Name: "Milk", Price: 12500, Barcode: "4006381333931"}, wantField: "^BEN,80,Y,N^FD400638133393^FS"},
+		{name: "upc-a", label: Label{Name: "Milk", Price: 12500, Barcode: "036000291452"}, wantField: "^BUN,80,Y,N,Y^FD03600029145^FS"},
+		{name: "code 128", label: Label{Name: "Milk", Price: 12500, Barcode: "ABC-123"}, wantField: "^BCN,80,Y,N,N^FDABC-123^FS"},
+		{name: "invalid barcode", label: Label{Name: "Milk", Barcode: "4006381333932"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			var buf bytes.Buffer
+
+			err := WriteZPL(&buf, []Label{tt.label})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("WriteZPL() error = %v, want error %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			out := buf.String()
+
+			for _, want := range []string{tt.wantField, "^FDMilk^FS", "^FD12 500^FS"} {
+				if !strings.Contains(out, want) {
+					t.Errorf("WriteZPL() = %q, want it to contain %q", out, want)
+				}
+			}
+		})
+	}
+}
+
+func TestWriteZPLCopiesAndEscaping(t *testing.T) {
+
+	var buf bytes.Buffer
+
+	err := WriteZPL(&buf, []Label{{Name: "Tea ^XZ~JA", Price: 100, Barcode: "4006381333931", Copies: 3}})
+	if err != nil {
+		t.Fatalf("WriteZPL() error = %v", err)
+	}
+
+	out := buf.String()
+
+	if got := strings.Count(out, "^XA"); got != 3 {
+		t.Errorf("WriteZPL() wrote %d formats, want 3", got)
+	}
+
+	if got := strings.Count(out, "^XZ"); got != 3 {
+		t.Errorf("WriteZPL() ends %d formats, want 3, the name must not end one", got)
+	}
+
+	if strings.Contains(out, "~JA") {
+		t.Errorf("WriteZPL() = %q, keeps a command prefix of the name", out)
+	}
+}
+
+func TestWritePDF(t *testing.T) {
+
+	var buf bytes.Buffer
+
+	// 25 labels are one full sheet and one more.
+	err := WritePDF(&buf, []Label{
+		{Name: "Non (sugar) \\ free", Price: 4500, Barcode: "4006381333931", Copies: 24},
+		{Name: "Qo‘y go‘shti", Price: 120000, Barcode: "ABC-123"},
+	})
+	if err != nil {
+		t.Fatalf("WritePDF() error = %v", err)
+	}
+
+	out := buf.String()
+
+	if !strings.HasPrefix(out, "%PDF-1.4\n") || !strings.HasSuffix(out, "%%EOF\n") {
+		t.Fatalf("WritePDF() is not a PDF document: %.40q...", out)
+	}
+
+	if !strings.Contains(out, "/Count 2 >>") {
+		t.Errorf("WritePDF() does not have 2 pages")
+	}
+
+	for _, want := range []string{`(Non \(sugar\) \\ free)`, "(Qo'y go'shti)", "(120 000)"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("WritePDF() does not contain %s", want)
+		}
+	}
+
+	// Every xref entry must point at the object it lists.
+	xref := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllStringSubmatch(out, -1)
+	if len(xref) != 8 {
+		t.Fatalf("WritePDF() has %d objects in its xref, want 8", len(xref))
+	}
+
+	for i, entry := range xref {
+		offset, _ := strconv.Atoi(entry[1])
+		if want := strconv.Itoa(i+1) + " 0 obj"; !strings.HasPrefix(out[offset:], want) {
+			t.Errorf("xref entry %d points at %.12q, want %q", i+1, out[offset:], want)
+		}
+	}
+}
+
+func TestWritePDFNoLabels(t *testing.T) {
+
+	if err := WritePDF(&bytes.Buffer{}, nil); err == nil {
+		t.Error("WritePDF() of no labels error = nil, want an error")
+	}
+}
+
+func TestWriteSVG(t *testing.T) {
+
+	var buf bytes.Buffer
+
+	err := WriteSVG(&buf, "4006381333931")
+	if err != nil {
+		t.Fatalf("WriteSVG() error = %v", err)
+	}
+
+	out := buf.String()
+
+	if !strings.HasPrefix(out, "<svg ") || !strings.HasSuffix(out, "</svg>") {
+		t.Errorf("WriteSVG() = %.40q..., not an svg element", out)
+	}
+
+	if !strings.Contains(out, "4006381333931") {
+		t.Errorf("WriteSVG() does not print the code under the bars")
+	}
+
+	if err := WriteSVG(&bytes.Buffer{}, "4006381333932"); err == nil {
+		t.Error("WriteSVG() of a wrong check digit error = nil, want an error")
+	}
+}
+
+func TestWritePNG(t *testing.T) {
+
+	tests := []struct {
+		name  string
+		scale int
+		want  int
+	}{
+		{name: "scale 3", scale: 3, want: 3},
+		{name: "default scale", scale: 0, want: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			var buf bytes.Buffer
+
+			err := WritePNG(&buf, "4006381333931", tt.scale)
+			if err != nil {
+				t.Fatalf("WritePNG() error = %v", err)
+			}
+
+			img, err := png.Decode(&buf)
+			if err != nil {
+				t.Fatalf("png.Decode() error = %v", err)
+			}
+
+			// An EAN-13 is 95 modules wide.
+			if got := img.Bounds().Dx(); got != (95+2*quietModules)*tt.want {
+				t.Errorf("width = %d, want %d", got, (95+2*quietModules)*tt.want)
+			}
+
+			if got := img.Bounds().Dy(); got != barHeight*tt.want {
+				t.Errorf("height = %d, want %d", got, barHeight*tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package label
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"market/pkg/barcode"
+)
+
+// A4 sheet with 3 x 8 labels of 70 x 37 mm, the most common label paper.
+const (
+	pageWidth   = 595.28
+	pageHeight  = 841.89
+	sheetCols   = 3
+	sheetRows   = 8
+	labelWidth  = pageWidth / sheetCols
+	labelHeight = pageHeight / sheetRows
+	labelMargin = 8.0
+)
+
+// WritePDF lays the labels out on as many A4 sheets as needed.
+func WritePDF(w io.Writer, labels []Label) error {
+
+	var (
+		all     = expand(labels)
+		perPage = sheetCols * sheetRows
+		pages   []string
+	)
+
+	for start := 0; start < len(all); start += perPage {
+		end := start + perPage
+		if end > len(all) {
+			end = len(all)
+		}
+
+		content, err := pdfPage(all[start:end])
+		if err != nil {
+			return err
+		}
+
+		pages = append(pages, content)
+	}
+
+	if len(pages) <= 0 {
+		return errors.New("no labels to print")
+	}
+
+	return writePDFDocument(w, pages)
+}
+
+func pdfPage(labels []Label) (string, error) {
+
+	var content strings.Builder
+
+	for i, l := range labels {
+		var (
+			x = float64(i%sheetCols) * labelWidth
+			y = pageHeight - float64(i/sheetCols+1)*labelHeight
+		)
+
+		bars, err := barcode.Encode(l.Barcode)
+		if err != nil {
+			return "", err
+		}
+
+		name := []rune(l.Name)
+		if len(name) > 36 {
+			name = append(name[:35], '.')
+		}
+
+		fmt.Fprintf(&content, "BT /F1 9 Tf %.2f %.2f Td (%s) Tj ET\n", x+labelMargin, y+labelHeight-18, pdfText(string(name)))
+		fmt.Fprintf(&content, "BT /F2 16 Tf %.2f %.2f Td (%s) Tj ET\n", x+labelMargin, y+labelHeight-38, pdfText(FormatPrice(l.Price)))
+
+		var (
+			module = (labelWidth - 2*labelMargin) / float64(len(bars.Modules))
+			left   = x + labelMargin
+			bottom = y + 18
+		)
+
+		if module > 1 {
+			module = 1
+			left = x + (labelWidth-float64(len(bars.Modules)))/2
+		}
+
+		for _, run := range bars.Runs() {
+			fmt.Fprintf(&content, "%.3f %.2f %.3f 36 re f\n", left+float64(run[0])*module, bottom, float64(run[1])*module)
+		}
+
+		fmt.Fprintf(&content, "BT /F1 8 Tf %.2f %.2f Td (%s) Tj ET\n", left, y+8, pdfText(bars.Text))
+	}
+
+	return content.String(), nil
+}
+
+func writePDFDocument(w io.Writer, pages []string) error {
+
+	var (
+		buf     bytes.Buffer
+		offsets []int
+		kids    []string
+	)
+
+	object := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	// Objects 1-4 are fixed, every page then takes a page and a content object.
+	for i := range pages {
+		kids = append(kids, fmt.Sprintf("%d 0 R", 5+2*i))
+	}
+
+	buf.WriteString("%PDF-1.4\n")
+
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+
+	for i, content := range pages {
+		object(fmt.Sprintf(
+			"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			pageWidth, pageHeight, 6+2*i,
+		))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(content), content))
+	}
+
+	xref := buf.Len()
+
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+// pdfText escapes a string for a PDF literal. The standard fonts only cover
+// Latin-1, so Uzbek apostrophes are normalised and other runes replaced.
+func pdfText(value string) string {
+
+	var out strings.Builder
+
+	for _, r := range value {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			out.WriteByte('\\')
+			out.WriteRune(r)
+		case r == '‘' || r == '’' || r == 'ʻ' || r == 'ʼ':
+			out.WriteByte('\'')
+		case r < 32:
+			out.WriteByte(' ')
+		case r < 256:
+			out.WriteByte(byte(r))
+		default:
+			out.WriteByte('?')
+		}
+	}
+
+	return out.String()
+}
@@ -0,0 +1,51 @@
+package label
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"market/pkg/barcode"
+)
+
+// WriteZPL renders one ^XA..^XZ format per label copy for 58x40 mm thermal
+// labels at 203 dpi.
+func WriteZPL(w io.Writer, labels []Label) error {
+
+	for _, l := range expand(labels) {
+		symbology, err := barcode.Validate(l.Barcode)
+		if err != nil {
+			return err
+		}
+
+		var field string
+
+		switch symbology {
+		case barcode.EAN13:
+			field = fmt.Sprintf("^BEN,80,Y,N^FD%s^FS", l.Barcode[:12])
+		case barcode.UPCA:
+			field = fmt.Sprintf("^BUN,80,Y,N,Y^FD%s^FS", l.Barcode[:11])
+		default:
+			field = fmt.Sprintf("^BCN,80,Y,N,N^FD%s^FS", zplEscape(l.Barcode))
+		}
+
+		_, err = fmt.Fprintf(w,
+			"^XA^CI28^PW464^LL320\n"+
+				"^FO20,16^A0N,26,26^FB424,2,0,L^FD%s^FS\n"+
+				"^FO20,76^A0N,44,44^FD%s^FS\n"+
+				"^FO40,140^BY2%s\n"+
+				"^XZ\n",
+			zplEscape(l.Name), FormatPrice(l.Price), field,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// zplEscape drops the command prefixes that would end a field early.
+func zplEscape(value string) string {
+	return strings.NewReplacer("^", " ", "~", " ").Replace(value)
+}
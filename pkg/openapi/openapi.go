@@ -0,0 +1,376 @@
+// Package openapi builds an OpenAPI 3 document from a table of routes and the
+// Go types their handlers bind and return, and checks that table against the
+// routes a router actually serves.
+package openapi
+
+import (
+	"net/http"
+	"reflect"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const Version = "3.0.3"
+
+type Document struct {
+	OpenAPI    string                `json:"openapi"`
+	Info       Info                  `json:"info"`
+	Paths      map[string]*PathItem  `json:"paths"`
+	Components Components            `json:"components"`
+	Security   []map[string][]string `json:"security,omitempty"`
+}
+
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+// PathItem holds the operations of one path by lower case method.
+type PathItem map[string]*Operation
+
+type Operation struct {
+	OperationId string                 `json:"operationId,omitempty"`
+	Summary     string                 `json:"summary,omitempty"`
+	Tags        []string               `json:"tags,omitempty"`
+	Parameters  []*Parameter           `json:"parameters,omitempty"`
+	RequestBody *RequestBody           `json:"requestBody,omitempty"`
+	Responses   map[string]*Response   `json:"responses"`
+	Security    *[]map[string][]string `json:"security,omitempty"`
+}
+
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                  `json:"required,omitempty"`
+	Content  map[string]*MediaType `json:"content"`
+}
+
+type Response struct {
+	Description string                `json:"description"`
+	Content     map[string]*MediaType `json:"content,omitempty"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+type Components struct {
+	Schemas         map[string]*Schema         `json:"schemas"`
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+type SecurityScheme struct {
+	Type         string `json:"type"`
+	Scheme       string `json:"scheme,omitempty"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+	Name         string `json:"name,omitempty"`
+	In           string `json:"in,omitempty"`
+}
+
+// Param is a query or form parameter of a route. Type is a JSON schema type,
+// or "file" for an uploaded file.
+type Param struct {
+	Name        string
+	Type        string
+	Description string
+	Required    bool
+}
+
+// Route describes one endpoint. Handler is the method expression of the
+// handler, like (*handler.Handler).CreateUser, and names the operation.
+// Request and Response are zero values of the types the handler binds and
+// puts in the data of the response envelope; ContentType is set instead of
+// Response for handlers that write a file.
+type Route struct {
+	Method      string
+	Path        string
+	Handler     interface{}
+	Tag         string
+	Summary     string
+	Public      bool
+	Headers     []string
+	Query       []Param
+	Form        []Param
+	Request     interface{}
+	Response    interface{}
+	Status      int
+	ContentType string
+}
+
+// Served is a route as registered on the router.
+type Served struct {
+	Method  string
+	Path    string
+	Handler string
+}
+
+// Builder turns routes into a document. Envelope is the type every JSON
+// response is wrapped in; its data field is narrowed per operation.
+type Builder struct {
+	Title       string
+	Description string
+	Version     string
+	Envelope    interface{}
+	Security    map[string]*SecurityScheme
+}
+
+func (b *Builder) Build(routes []Route) *Document {
+
+	var (
+		gen = newGenerator()
+		doc = &Document{
+			OpenAPI: Version,
+			Info: Info{
+				Title:       b.Title,
+				Description: b.Description,
+				Version:     b.Version,
+			},
+			Paths: map[string]*PathItem{},
+			Components: Components{
+				SecuritySchemes: b.Security,
+			},
+		}
+		envelope = gen.schema(reflect.TypeOf(b.Envelope))
+	)
+
+	for name := range b.Security {
+		doc.Security = append(doc.Security, map[string][]string{name: {}})
+	}
+	sort.Slice(doc.Security, func(i, j int) bool {
+		return firstKey(doc.Security[i]) < firstKey(doc.Security[j])
+	})
+
+	for _, route := range routes {
+		path := Path(route.Path)
+
+		item, ok := doc.Paths[path]
+		if !ok {
+			item = &PathItem{}
+			doc.Paths[path] = item
+		}
+
+		(*item)[strings.ToLower(route.Method)] = b.operation(gen, envelope, route)
+	}
+
+	doc.Components.Schemas = gen.schemas
+
+	return doc
+}
+
+func (b *Builder) operation(gen *generator, envelope *Schema, route Route) *Operation {
+
+	var (
+		status    = route.Status
+		operation = &Operation{
+			OperationId: HandlerName(route.Handler),
+			Summary:     route.Summary,
+			Responses:   map[string]*Response{},
+		}
+	)
+
+	if route.Tag != "" {
+		operation.Tags = []string{route.Tag}
+	}
+
+	if route.Public {
+		operation.Security = &[]map[string][]string{}
+	}
+
+	for _, segment := range strings.Split(route.Path, "/") {
+		if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			operation.Parameters = append(operation.Parameters, &Parameter{
+				Name:     segment[1:],
+				In:       "path",
+				Required: true,
+				Schema:   &Schema{Type: "string"},
+			})
+		}
+	}
+
+	for _, header := range route.Headers {
+		operation.Parameters = append(operation.Parameters, &Parameter{
+			Name:   header,
+			In:     "header",
+			Schema: &Schema{Type: "string"},
+		})
+	}
+
+	for _, param := range route.Query {
+		operation.Parameters = append(operation.Parameters, &Parameter{
+			Name:        param.Name,
+			In:          "query",
+			Description: param.Description,
+			Required:    param.Required,
+			Schema:      paramSchema(param),
+		})
+	}
+
+	switch {
+	case route.Request != nil:
+		operation.RequestBody = &RequestBody{
+			Required: true,
+			Content: map[string]*MediaType{
+				"application/json": {Schema: gen.schema(reflect.TypeOf(route.Request))},
+			},
+		}
+	case len(route.Form) > 0:
+		form := &Schema{Type: "object", Properties: map[string]*Schema{}}
+
+		for _, param := range route.Form {
+			form.Properties[param.Name] = paramSchema(param)
+			if param.Required {
+				form.Required = append(form.Required, param.Name)
+			}
+		}
+
+		operation.RequestBody = &RequestBody{
+			Required: true,
+			Content: map[string]*MediaType{
+				"multipart/form-data": {Schema: form},
+			},
+		}
+	}
+
+	if status == 0 {
+		status = http.StatusOK
+	}
+
+	response := &Response{Description: http.StatusText(status)}
+
+	switch {
+	case route.ContentType != "":
+		response.Content = map[string]*MediaType{
+			route.ContentType: {Schema: &Schema{Type: "string", Format: "binary"}},
+		}
+	case status != http.StatusNoContent:
+		data := &Schema{}
+		if route.Response != nil {
+			data = gen.schema(reflect.TypeOf(route.Response))
+		}
+
+		response.Content = map[string]*MediaType{
+			"application/json": {Schema: &Schema{AllOf: []*Schema{
+				envelope,
+				{Type: "object", Properties: map[string]*Schema{"data": data}},
+			}}},
+		}
+	}
+
+	operation.Responses[strconv.Itoa(status)] = response
+	operation.Responses["default"] = &Response{
+		Description: "Error, with the reason in error",
+		Content: map[string]*MediaType{
+			"application/json": {Schema: envelope},
+		},
+	}
+
+	return operation
+}
+
+// Diff lists the routes that are served but not documented and the other
+// way around, matching on method, path and handler.
+func (d *Document) Diff(served []Served) []string {
+
+	var (
+		drift      []string
+		documented = map[string]string{}
+	)
+
+	for path, item := range d.Paths {
+		for method, operation := range *item {
+			documented[strings.ToUpper(method)+" "+path] = operation.OperationId
+		}
+	}
+
+	for _, route := range served {
+		key := route.Method + " " + Path(route.Path)
+
+		operationId, ok := documented[key]
+		switch {
+		case !ok:
+			drift = append(drift, key+" is served but not documented")
+		case operationId != shortName(route.Handler):
+			drift = append(drift, key+" is served by "+shortName(route.Handler)+" but documented as "+operationId)
+		}
+
+		delete(documented, key)
+	}
+
+	for key := range documented {
+		drift = append(drift, key+" is documented but not served")
+	}
+
+	sort.Strings(drift)
+
+	return drift
+}
+
+// Path turns a gin path like /product/:id into /product/{id}.
+func Path(path string) string {
+
+	segments := strings.Split(path, "/")
+
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			segments[i] = "{" + segment[1:] + "}"
+		}
+	}
+
+	return strings.Join(segments, "/")
+}
+
+// HandlerName is the bare name of a handler func or method, like CreateUser.
+func HandlerName(handler interface{}) string {
+
+	if handler == nil {
+		return ""
+	}
+
+	fn := runtime.FuncForPC(reflect.ValueOf(handler).Pointer())
+	if fn == nil {
+		return ""
+	}
+
+	return shortName(fn.Name())
+}
+
+// shortName strips the package, receiver and the -fm suffix of method values
+// from a function name as the runtime reports it.
+func shortName(name string) string {
+
+	name = strings.TrimSuffix(name, "-fm")
+
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		name = name[i+1:]
+	}
+
+	return name
+}
+
+func paramSchema(param Param) *Schema {
+
+	switch param.Type {
+	case "":
+		return &Schema{Type: "string"}
+	case "file":
+		return &Schema{Type: "string", Format: "binary"}
+	}
+
+	return &Schema{Type: param.Type}
+}
+
+func firstKey(m map[string][]string) string {
+	for key := range m {
+		return key
+	}
+	return ""
+}
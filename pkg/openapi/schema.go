@@ -0,0 +1,277 @@
+package openapi
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	ExclusiveMinimum     bool               `json:"exclusiveMinimum,omitempty"`
+	ExclusiveMaximum     bool               `json:"exclusiveMaximum,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AllOf                []*Schema          `json:"allOf,omitempty"`
+}
+
+// phonePattern is what the phone binding rule of pkg/helper accepts.
+const phonePattern = `^\+998[0-9]{2}[0-9]{7}$`
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// generator collects the schemas of named struct types as components, so
+// each is described once and referenced everywhere else.
+type generator struct {
+	schemas map[string]*Schema
+	names   map[reflect.Type]string
+}
+
+func newGenerator() *generator {
+	return &generator{
+		schemas: map[string]*Schema{},
+		names:   map[reflect.Type]string{},
+	}
+}
+
+func (g *generator) schema(t reflect.Type) *Schema {
+
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t == timeType {
+		return &Schema{Type: "string", Format: "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: g.schema(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.object(t)
+		}
+		return &Schema{Ref: "#/components/schemas/" + g.component(t)}
+	}
+
+	return &Schema{}
+}
+
+// component registers a named struct type and returns its name, qualified
+// by the package when two packages use the same type name.
+func (g *generator) component(t reflect.Type) string {
+
+	if name, ok := g.names[t]; ok {
+		return name
+	}
+
+	name := t.Name()
+	if _, taken := g.schemas[name]; taken {
+		pkg := t.PkgPath()
+		name = pkg[strings.LastIndex(pkg, "/")+1:] + "." + name
+	}
+
+	// Register before describing the fields so that types referring to
+	// themselves end in a reference.
+	g.names[t] = name
+	g.schemas[name] = &Schema{}
+	*g.schemas[name] = *g.object(t)
+
+	return name
+}
+
+func (g *generator) object(t reflect.Type) *Schema {
+
+	var object = &Schema{Type: "object", Properties: map[string]*Schema{}}
+
+	g.fields(object, t)
+
+	return object
+}
+
+func (g *generator) fields(object *Schema, t reflect.Type) {
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		tag := field.Tag.Get("json")
+		name := strings.SplitN(tag, ",", 2)[0]
+
+		if name == "-" {
+			continue
+		}
+
+		if field.Anonymous && tag == "" {
+			embedded := field.Type
+			for embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+
+			if embedded.Kind() == reflect.Struct {
+				g.fields(object, embedded)
+				continue
+			}
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+
+		property := g.schema(field.Type)
+
+		if applyRules(property, field.Tag.Get("binding")) {
+			object.Required = append(object.Required, name)
+		}
+
+		object.Properties[name] = property
+	}
+}
+
+// applyRules describes the binding rules of a field on its schema and
+// reports whether the field is required. Rules after dive apply to the items.
+func applyRules(s *Schema, binding string) bool {
+
+	var required bool
+
+	if binding == "" {
+		return false
+	}
+
+	rules := strings.Split(binding, ",")
+	optional := rules[0] == "omitempty"
+
+	for i, rule := range rules {
+		name, param := rule, ""
+		if j := strings.Index(rule, "="); j >= 0 {
+			name, param = rule[:j], rule[j+1:]
+		}
+
+		if name == "dive" {
+			if s.Items != nil && s.Items.Ref == "" {
+				applyRules(s.Items, strings.Join(rules[i+1:], ","))
+			}
+			break
+		}
+
+		// Siblings of a reference are ignored, only requiredness counts.
+		if s.Ref != "" && name != "required" {
+			continue
+		}
+
+		switch name {
+		case "required":
+			required = true
+		case "min", "gte":
+			s.setBound(param, true, false)
+		case "max", "lte":
+			s.setBound(param, false, false)
+		case "gt":
+			s.setBound(param, true, true)
+		case "lt":
+			s.setBound(param, false, true)
+		case "uuid":
+			s.Format = "uuid"
+		case "email":
+			s.Format = "email"
+		case "datetime":
+			if param == "2006-01-02" {
+				s.Format = "date"
+			} else {
+				s.Description = "time in Go layout " + param
+			}
+		case "oneof":
+			s.Enum = strings.Fields(param)
+		case "phone":
+			s.Pattern = phonePattern
+		case "barcode":
+			// The rule rejects empty codes unless omitted.
+			required = required || !optional
+			s.Format = "barcode"
+			s.Description = "EAN-8, UPC-A or EAN-13 with a valid check digit, anything else is taken as Code 128"
+		}
+	}
+
+	return required
+}
+
+// setBound sets a lower or upper bound on the length of strings, the size of
+// arrays or the value of numbers, depending on the type of s.
+func (s *Schema) setBound(param string, lower, exclusive bool) {
+
+	value, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return
+	}
+
+	count := int(value)
+
+	switch s.Type {
+	case "string":
+		if exclusive {
+			count++
+			if !lower {
+				count -= 2
+			}
+		}
+		if lower {
+			s.MinLength = &count
+		} else {
+			s.MaxLength = &count
+		}
+	case "array":
+		if exclusive {
+			count++
+			if !lower {
+				count -= 2
+			}
+		}
+		if lower {
+			s.MinItems = &count
+		} else {
+			s.MaxItems = &count
+		}
+	case "integer", "number":
+		if lower {
+			s.Minimum = &value
+			s.ExclusiveMinimum = exclusive
+		} else {
+			s.Maximum = &value
+			s.ExclusiveMaximum = exclusive
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package openapi
+
+import (
+	"html"
+	"strconv"
+)
+
+// swaggerUIVersion pins the Swagger UI bundle loaded from the CDN.
+const swaggerUIVersion = "5.17.14"
+
+// SwaggerUI renders a page that loads the document at specURL into Swagger
+// UI, keeping the token a user authorizes with across reloads.
+func SwaggerUI(title, specURL string) []byte {
+
+	var base = "https://unpkg.com/swagger-ui-dist@" + swaggerUIVersion
+
+	return []byte(`<!DOCTYPE html>
+<html lang="en">
+<head>
+	<meta charset="utf-8">
+	<meta name="viewport" content="width=device-width, initial-scale=1">
+	<title>` + html.EscapeString(title) + `</title>
+	<link rel="stylesheet" href="` + base + `/swagger-ui.css">
+</head>
+<body>
+	<div id="swagger-ui"></div>
+	<script src="` + base + `/swagger-ui-bundle.js" crossorigin></script>
+	<script>
+		window.ui = SwaggerUIBundle({
+			url: ` + strconv.Quote(specURL) + `,
+			dom_id: "#swagger-ui",
+			deepLinking: true,
+			persistAuthorization: true
+		});
+	</script>
+</body>
+</html>
+`)
+}
@@ -0,0 +1,64 @@
+// Package ratelimit counts requests per key in fixed windows. Counts live in
+// the memory of the process, so limits hold per process: behind a load
+// balancer every replica allows the full limit, and a restart resets them.
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+type window struct {
+	start time.Time
+	count int
+}
+
+type Limiter struct {
+	mu      sync.Mutex
+	period  time.Duration
+	windows map[string]*window
+	now     func() time.Time
+}
+
+func New(period time.Duration) *Limiter {
+	return &Limiter{
+		period:  period,
+		windows: map[string]*window{},
+		now:     time.Now,
+	}
+}
+
+// Allow counts a request of key and reports whether it is within limit
+// requests per period. When it is not, the returned duration is how long
+// until the next window opens.
+func (l *Limiter) Allow(key string, limit int) (bool, time.Duration) {
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+
+	w, ok := l.windows[key]
+	if !ok || now.Sub(w.start) >= l.period {
+		l.expire(now)
+		w = &window{start: now}
+		l.windows[key] = w
+	}
+
+	if w.count >= limit {
+		return false, w.start.Add(l.period).Sub(now)
+	}
+
+	w.count++
+
+	return true, 0
+}
+
+// expire drops windows that are over so keys no longer in use are not kept.
+func (l *Limiter) expire(now time.Time) {
+	for key, w := range l.windows {
+		if now.Sub(w.start) >= l.period {
+			delete(l.windows, key)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAllow(t *testing.T) {
+
+	type step struct {
+		at        time.Duration
+		key       string
+		wantOk    bool
+		wantRetry time.Duration
+	}
+
+	tests := []struct {
+		name  string
+		limit int
+		steps []step
+	}{
+		{
+			name:  "within the limit",
+			limit: 3,
+			steps: []step{
+				{at: 0, key: "a", wantOk: true},
+				{at: time.Second, key: "a", wantOk: true},
+				{at: 2 * time.Second, key: "a", wantOk: true},
+			},
+		},
+		{
+			name:  "over the limit until the window ends",
+			limit: 2,
+			steps: []step{
+				{at: 0, key: "a", wantOk: true},
+				{at: 10 * time.Second, key: "a", wantOk: true},
+				{at: 20 * time.Second, key: "a", wantOk: false, wantRetry: 40 * time.Second},
+				{at: 59 * time.Second, key: "a", wantOk: false, wantRetry: time.Second},
+			},
+		},
+		{
+			name:  "window rolls over at the period",
+			limit: 1,
+			steps: []step{
+				{at: 0, key: "a", wantOk: true},
+				{at: 30 * time.Second, key: "a", wantOk: false, wantRetry: 30 * time.Second},
+				{at: time.Minute, key: "a", wantOk: true},
+				{at: 90 * time.Second, key: "a", wantOk: false, wantRetry: 30 * time.Second},
+				{at: 3 * time.Minute, key: "a", wantOk: true},
+			},
+		},
+		{
+			name:  "new window starts at the first request after rollover",
+			limit: 1,
+			steps: []step{
+				{at: 0, key: "a", wantOk: true},
+				{at: 70 * time.Second, key: "a", wantOk: true},
+				{at: 2 * time.Minute, key: "a", wantOk: false, wantRetry: 10 * time.Second},
+				{at: 130 * time.Second, key: "a", wantOk: true},
+			},
+		},
+		{
+			name:  "denied requests do not count",
+			limit: 1,
+			steps: []step{
+				{at: 0, key: "a", wantOk: true},
+				{at: time.Second, key: "a", wantOk: false, wantRetry: 59 * time.Second},
+				{at: 2 * time.Second, key: "a", wantOk: false, wantRetry: 58 * time.Second},
+				{at: time.Minute, key: "a", wantOk: true},
+			},
+		},
+		{
+			name:  "keys are counted apart",
+			limit: 1,
+			steps: []step{
+				{at: 0, key: "a", wantOk: true},
+				{at: time.Second, key: "b", wantOk: true},
+				{at: 2 * time.Second, key: "a", wantOk: false, wantRetry: 58 * time.Second},
+				{at: 3 * time.Second, key: "b", wantOk: false, wantRetry: 58 * time.Second},
+			},
+		},
+		{
+			name:  "zero limit allows nothing",
+			limit: 0,
+			steps: []step{
+				{at: 0, key: "a", wantOk: false, wantRetry: time.Minute},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			var (
+				start = time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+				now   time.Time
+				l     = New(time.Minute)
+			)
+
+			l.now = func() time.Time { return now }
+
+			for i, s := range tt.steps {
+				now = start.Add(s.at)
+
+				ok, retry := l.Allow(s.key, tt.limit)
+				if ok != s.wantOk || retry != s.wantRetry {
+					t.Errorf("step %d: Allow(%q) at %v = %v, %v, want %v, %v", i, s.key, s.at, ok, retry, s.wantOk, s.wantRetry)
+				}
+			}
+		})
+	}
+}
+
+func TestAllowExpiresIdleKeys(t *testing.T) {
+
+	var (
+		now = time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+		l   = New(time.Minute)
+	)
+
+	l.now = func() time.Time { return now }
+
+	l.Allow("a", 1)
+	l.Allow("b", 1)
+
+	// A new window of any key drops the windows that are over.
+	now = now.Add(time.Minute)
+	l.Allow("c", 1)
+
+	if _, ok := l.windows["a"]; ok || len(l.windows) != 1 {
+		t.Errorf("windows = %v, want only c left", l.windows)
+	}
+}
@@ -0,0 +1,161 @@
+// Package rbac holds the roles of the service and what each may do.
+package rbac
+
+import "strings"
+
+const (
+	RoleAdmin       = "admin"
+	RoleManager     = "manager"
+	RoleStorekeeper = "storekeeper"
+	RoleCashier     = "cashier"
+	RoleAuditor     = "auditor"
+)
+
+const (
+	Read   = "r"
+	Write  = "w"
+	Delete = "d"
+)
+
+// Scopes of API keys.
+const (
+	ScopeReadCatalog   = "catalog:read"
+	ScopeReadStock     = "stock:read"
+	ScopeWriteReceipts = "receipts:write"
+)
+
+// grants maps a role to the actions allowed per entity, "*" being the
+// default for entities not listed. Admins are allowed everything.
+var grants = map[string]map[string]string{
+	RoleManager: {
+		"*":                      Read,
+		"user":                   "",
+		"api_key":                "",
+		"category":               "rwd",
+		"product":                "rwd",
+		"product_barcode":        "rwd",
+		"product_image":          "rwd",
+		"barcode":                "rw",
+		"label":                  "rw",
+		"storage_coming":         "rwd",
+		"storage_coming_product": "rwd",
+		"remaining":              "rw",
+		"serial_number":          "rw",
+		"stock_level":            "rwd",
+	},
+	RoleStorekeeper: {
+		"*":                      Read,
+		"user":                   "",
+		"api_key":                "",
+		"audit_log":              "",
+		"product":                "rw",
+		"product_barcode":        "rw",
+		"product_image":          "rw",
+		"barcode":                "rw",
+		"label":                  "rw",
+		"storage_coming":         "rwd",
+		"storage_coming_product": "rwd",
+		"remaining":              "rw",
+		"serial_number":          "rw",
+		"stock_level":            "rw",
+	},
+	RoleCashier: {
+		"*":               "",
+		"branch":          Read,
+		"category":        Read,
+		"product":         Read,
+		"product_barcode": Read,
+		"product_image":   Read,
+		"barcode":         Read,
+		"label":           "rw",
+		"remaining":       "rw",
+		"serial_number":   "rw",
+	},
+	RoleAuditor: {
+		"*":       Read,
+		"user":    "",
+		"api_key": "",
+	},
+}
+
+// scopes maps an API key scope to the actions it allows per entity.
+var scopes = map[string]map[string]string{
+	ScopeReadCatalog: {
+		"branch":          Read,
+		"category":        Read,
+		"product":         Read,
+		"product_barcode": Read,
+		"product_image":   Read,
+		"barcode":         Read,
+	},
+	ScopeReadStock: {
+		"remaining":     Read,
+		"stock_level":   Read,
+		"serial_number": Read,
+	},
+	ScopeWriteReceipts: {
+		"storage_coming":         "rw",
+		"storage_coming_product": "rw",
+	},
+}
+
+func Roles() []string {
+	return []string{RoleAdmin, RoleManager, RoleStorekeeper, RoleCashier, RoleAuditor}
+}
+
+func ValidRole(role string) bool {
+	return role == RoleAdmin || grants[role] != nil
+}
+
+// Allowed reports whether role may perform action on entity.
+func Allowed(role, entity, action string) bool {
+
+	if role == RoleAdmin {
+		return true
+	}
+
+	entities, ok := grants[role]
+	if !ok {
+		return false
+	}
+
+	actions, ok := entities[entity]
+	if !ok {
+		actions = entities["*"]
+	}
+
+	return permits(actions, action)
+}
+
+// AllBranches reports whether role works across every branch instead of
+// the branches assigned to the user.
+func AllBranches(role string) bool {
+	return role == RoleAdmin
+}
+
+func Scopes() []string {
+	return []string{ScopeReadCatalog, ScopeReadStock, ScopeWriteReceipts}
+}
+
+func ValidScope(scope string) bool {
+	return scopes[scope] != nil
+}
+
+// ScopeAllowed reports whether any of the API key scopes allows action on
+// entity.
+func ScopeAllowed(keyScopes []string, entity, action string) bool {
+
+	for _, scope := range keyScopes {
+		if permits(scopes[scope][entity], action) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// permits reports whether actions holds the single action, so an empty or
+// combined action never passes.
+func permits(actions, action string) bool {
+	return len(action) == 1 && strings.Contains(actions, action)
+}
@@ -0,0 +1,148 @@
+package rbac
+
+import "testing"
+
+func TestAllowed(t *testing.T) {
+
+	tests := []struct {
+		role   string
+		entity string
+		want   string
+	}{
+		{RoleAdmin, "user", "rwd"},
+		{RoleAdmin, "api_key", "rwd"},
+		{RoleAdmin, "audit_log", "rwd"},
+		{RoleAdmin, "anything", "rwd"},
+
+		{RoleManager, "user", ""},
+		{RoleManager, "api_key", ""},
+		{RoleManager, "branch", "r"},
+		{RoleManager, "audit_log", "r"},
+		{RoleManager, "product", "rwd"},
+		{RoleManager, "barcode", "rw"},
+		{RoleManager, "storage_coming", "rwd"},
+		{RoleManager, "remaining", "rw"},
+		{RoleManager, "stock_level", "rwd"},
+		{RoleManager, "report", "r"},
+
+		{RoleStorekeeper, "user", ""},
+		{RoleStorekeeper, "audit_log", ""},
+		{RoleStorekeeper, "branch", "r"},
+		{RoleStorekeeper, "category", "r"},
+		{RoleStorekeeper, "product", "rw"},
+		{RoleStorekeeper, "storage_coming", "rwd"},
+		{RoleStorekeeper, "storage_coming_product", "rwd"},
+		{RoleStorekeeper, "stock_level", "rw"},
+
+		{RoleCashier, "user", ""},
+		{RoleCashier, "audit_log", ""},
+		{RoleCashier, "report", ""},
+		{RoleCashier, "storage_coming", ""},
+		{RoleCashier, "branch", "r"},
+		{RoleCashier, "product", "r"},
+		{RoleCashier, "label", "rw"},
+		{RoleCashier, "remaining", "rw"},
+		{RoleCashier, "serial_number", "rw"},
+
+		{RoleAuditor, "user", ""},
+		{RoleAuditor, "api_key", ""},
+		{RoleAuditor, "audit_log", "r"},
+		{RoleAuditor, "product", "r"},
+		{RoleAuditor, "storage_coming", "r"},
+
+		{"", "product", ""},
+		{"owner", "product", ""},
+	}
+
+	for _, tt := range tests {
+		for _, action := range []string{Read, Write, Delete} {
+			want := false
+			for _, allowed := range tt.want {
+				want = want || string(allowed) == action
+			}
+
+			if got := Allowed(tt.role, tt.entity, action); got != want {
+				t.Errorf("Allowed(%q, %q, %q) = %v, want %v", tt.role, tt.entity, action, got, want)
+			}
+		}
+	}
+}
+
+func TestAllowedNoAction(t *testing.T) {
+
+	for _, role := range Roles() {
+		if role != RoleAdmin && Allowed(role, "product", "") {
+			t.Errorf("Allowed(%q, product, \"\") = true, want false", role)
+		}
+	}
+
+	if ScopeAllowed([]string{ScopeReadCatalog}, "product", "") {
+		t.Error("ScopeAllowed(catalog:read, product, \"\") = true, want false")
+	}
+}
+
+func TestScopeAllowed(t *testing.T) {
+
+	tests := []struct {
+		scopes []string
+		entity string
+		want   string
+	}{
+		{[]string{ScopeReadCatalog}, "product", "r"},
+		{[]string{ScopeReadCatalog}, "barcode", "r"},
+		{[]string{ScopeReadCatalog}, "remaining", ""},
+		{[]string{ScopeReadCatalog}, "storage_coming", ""},
+		{[]string{ScopeReadStock}, "remaining", "r"},
+		{[]string{ScopeReadStock}, "serial_number", "r"},
+		{[]string{ScopeReadStock}, "product", ""},
+		{[]string{ScopeWriteReceipts}, "storage_coming", "rw"},
+		{[]string{ScopeWriteReceipts}, "storage_coming_product", "rw"},
+		{[]string{ScopeWriteReceipts}, "product", ""},
+		{[]string{ScopeReadCatalog, ScopeWriteReceipts}, "product", "r"},
+		{[]string{ScopeReadCatalog, ScopeWriteReceipts}, "storage_coming", "rw"},
+		{[]string{ScopeReadCatalog, ScopeReadStock, ScopeWriteReceipts}, "user", ""},
+		{[]string{ScopeReadCatalog, ScopeReadStock, ScopeWriteReceipts}, "api_key", ""},
+		{[]string{"catalog:write"}, "product", ""},
+		{nil, "product", ""},
+	}
+
+	for _, tt := range tests {
+		for _, action := range []string{Read, Write, Delete} {
+			want := false
+			for _, allowed := range tt.want {
+				want = want || string(allowed) == action
+			}
+
+			if got := ScopeAllowed(tt.scopes, tt.entity, action); got != want {
+				t.Errorf("ScopeAllowed(%q, %q, %q) = %v, want %v", tt.scopes, tt.entity, action, got, want)
+			}
+		}
+	}
+}
+
+func TestValid(t *testing.T) {
+
+	for _, role := range Roles() {
+		if !ValidRole(role) {
+			t.Errorf("ValidRole(%q) = false, want true", role)
+		}
+	}
+
+	for _, scope := range Scopes() {
+		if !ValidScope(scope) {
+			t.Errorf("ValidScope(%q) = false, want true", scope)
+		}
+	}
+
+	if ValidRole("owner") || ValidRole("") {
+		t.Error("ValidRole() of an unknown role = true, want false")
+	}
+
+	if ValidScope("catalog:write") || ValidScope("") {
+		t.Error("ValidScope() of an unknown scope = true, want false")
+	}
+
+	if !AllBranches(RoleAdmin) || AllBranches(RoleManager) {
+		t.Error("AllBranches() must hold for admins only")
+	}
+}
@@ -0,0 +1,99 @@
+package thumbnail
+
+import (
+	"bytes"
+	"image"
+	"image/draw"
+	_ "image/gif"
+	"image/jpeg"
+	_ "image/png"
+	"io"
+)
+
+const ContentType = "image/jpeg"
+
+// Make decodes a JPEG, PNG or GIF and returns a JPEG that fits into a
+// size x size box, keeping the aspect ratio. Smaller images are not upscaled.
+func Make(r io.Reader, size int) ([]byte, error) {
+
+	src, _, err := image.Decode(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		bounds = src.Bounds()
+		width  = bounds.Dx()
+		height = bounds.Dy()
+	)
+
+	if width > size || height > size {
+		if width >= height {
+			width, height = size, maxInt(1, height*size/width)
+		} else {
+			width, height = maxInt(1, width*size/height), size
+		}
+	}
+
+	// JPEG has no alpha channel, so flatten transparent images onto white.
+	rgba := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(rgba, rgba.Bounds(), image.White, image.Point{}, draw.Src)
+	draw.Draw(rgba, rgba.Bounds(), src, bounds.Min, draw.Over)
+
+	var buf bytes.Buffer
+
+	err = jpeg.Encode(&buf, scale(rgba, width, height), &jpeg.Options{Quality: 85})
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// scale box-filters src down to width x height by averaging every source
+// pixel that falls into a destination pixel.
+func scale(src *image.RGBA, width, height int) *image.RGBA {
+
+	var (
+		dst  = image.NewRGBA(image.Rect(0, 0, width, height))
+		srcW = src.Bounds().Dx()
+		srcH = src.Bounds().Dy()
+	)
+
+	for y := 0; y < height; y++ {
+		y0, y1 := y*srcH/height, maxInt((y+1)*srcH/height, y*srcH/height+1)
+
+		for x := 0; x < width; x++ {
+			x0, x1 := x*srcW/width, maxInt((x+1)*srcW/width, x*srcW/width+1)
+
+			var r, g, b, a, n int
+
+			for sy := y0; sy < y1; sy++ {
+				offset := src.PixOffset(x0, sy)
+				for sx := x0; sx < x1; sx++ {
+					r += int(src.Pix[offset])
+					g += int(src.Pix[offset+1])
+					b += int(src.Pix[offset+2])
+					a += int(src.Pix[offset+3])
+					offset += 4
+					n++
+				}
+			}
+
+			offset := dst.PixOffset(x, y)
+			dst.Pix[offset] = uint8(r / n)
+			dst.Pix[offset+1] = uint8(g / n)
+			dst.Pix[offset+2] = uint8(b / n)
+			dst.Pix[offset+3] = uint8(a / n)
+		}
+	}
+
+	return dst
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,105 @@
+package thumbnail
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+func TestMake(t *testing.T) {
+
+	tests := []struct {
+		name                  string
+		width, height, size   int
+		wantWidth, wantHeight int
+	}{
+		{name: "landscape", width: 800, height: 400, size: 256, wantWidth: 256, wantHeight: 128},
+		{name: "portrait", width: 300, height: 600, size: 256, wantWidth: 128, wantHeight: 256},
+		{name: "square", width: 512, height: 512, size: 256, wantWidth: 256, wantHeight: 256},
+		{name: "thin strip keeps a pixel", width: 1000, height: 2, size: 100, wantWidth: 100, wantHeight: 1},
+		{name: "small is not upscaled", width: 64, height: 32, size: 256, wantWidth: 64, wantHeight: 32},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			out, err := Make(encodePNG(t, image.NewRGBA(image.Rect(0, 0, tt.width, tt.height))), tt.size)
+			if err != nil {
+				t.Fatalf("Make() error = %v", err)
+			}
+
+			img, err := jpeg.Decode(bytes.NewReader(out))
+			if err != nil {
+				t.Fatalf("Make() is not a JPEG: %v", err)
+			}
+
+			if img.Bounds().Dx() != tt.wantWidth || img.Bounds().Dy() != tt.wantHeight {
+				t.Errorf("Make() = %dx%d, want %dx%d", img.Bounds().Dx(), img.Bounds().Dy(), tt.wantWidth, tt.wantHeight)
+			}
+		})
+	}
+}
+
+func TestMakeFlattensTransparency(t *testing.T) {
+
+	// Fully transparent pixels come out white rather than black.
+	out, err := Make(encodePNG(t, image.NewNRGBA(image.Rect(0, 0, 8, 8))), 8)
+	if err != nil {
+		t.Fatalf("Make() error = %v", err)
+	}
+
+	img, err := jpeg.Decode(bytes.NewReader(out))
+	if err != nil {
+		t.Fatalf("Make() is not a JPEG: %v", err)
+	}
+
+	if r, g, b, _ := img.At(4, 4).RGBA(); r>>8 < 250 || g>>8 < 250 || b>>8 < 250 {
+		t.Errorf("transparent pixel = %d,%d,%d, want white", r>>8, g>>8, b>>8)
+	}
+}
+
+func TestMakeNotAnImage(t *testing.T) {
+
+	if _, err := Make(strings.NewReader("not an image"), 256); err == nil {
+		t.Error("Make() error = nil, want an error")
+	}
+}
+
+func TestScale(t *testing.T) {
+
+	// Black and white columns average to grey.
+	src := image.NewRGBA(image.Rect(0, 0, 4, 2))
+	for y := 0; y < 2; y++ {
+		for x := 0; x < 4; x++ {
+			if x%2 == 0 {
+				src.Set(x, y, color.White)
+			} else {
+				src.Set(x, y, color.Black)
+			}
+		}
+	}
+
+	dst := scale(src, 2, 1)
+
+	for x := 0; x < 2; x++ {
+		if got := dst.RGBAAt(x, 0); got != (color.RGBA{R: 127, G: 127, B: 127, A: 255}) {
+			t.Errorf("scale() pixel %d = %v, want grey", x, got)
+		}
+	}
+}
+
+func encodePNG(t *testing.T, img image.Image) *bytes.Buffer {
+
+	var buf bytes.Buffer
+
+	err := png.Encode(&buf, img)
+	if err != nil {
+		t.Fatalf("png.Encode() error = %v", err)
+	}
+
+	return &buf
+}
@@ -0,0 +1,201 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"errors"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+)
+
+type sharedStrings struct {
+	Items []struct {
+		Text string `xml:"t"`
+		Runs []struct {
+			Text string `xml:"t"`
+		} `xml:"r"`
+	} `xml:"si"`
+}
+
+type workbook struct {
+	Sheets []struct {
+		Name string `xml:"name,attr"`
+		Id   string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+type relationships struct {
+	Items []struct {
+		Id     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+type worksheet struct {
+	Rows []struct {
+		Cells []struct {
+			Ref    string `xml:"r,attr"`
+			Type   string `xml:"t,attr"`
+			Value  string `xml:"v"`
+			Inline struct {
+				Text string `xml:"t"`
+			} `xml:"is"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+// ReadAll returns the cell text of the first worksheet, one slice per row.
+// Only what an import needs is supported: shared, inline and numeric cells.
+func ReadAll(r io.ReaderAt, size int64) ([][]string, error) {
+
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, err
+	}
+
+	files := map[string]*zip.File{}
+	for _, file := range archive.File {
+		files[file.Name] = file
+	}
+
+	var strs sharedStrings
+	if file, ok := files["xl/sharedStrings.xml"]; ok {
+		err = decode(file, &strs)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	sheetPath, err := firstSheet(files)
+	if err != nil {
+		return nil, err
+	}
+
+	var sheet worksheet
+
+	err = decode(files[sheetPath], &sheet)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows [][]string
+
+	for _, row := range sheet.Rows {
+		var cells []string
+
+		for i, cell := range row.Cells {
+			column := i
+			if cell.Ref != "" {
+				column = columnIndex(cell.Ref)
+			}
+
+			var value string
+
+			switch cell.Type {
+			case "s":
+				index, err := strconv.Atoi(cell.Value)
+				if err != nil || index >= len(strs.Items) {
+					return nil, errors.New("xlsx: invalid shared string index in cell " + cell.Ref)
+				}
+
+				value = strs.Items[index].Text
+				for _, run := range strs.Items[index].Runs {
+					value += run.Text
+				}
+			case "inlineStr":
+				value = cell.Inline.Text
+			case "", "n":
+				value = formatNumber(cell.Value)
+			default:
+				value = cell.Value
+			}
+
+			for len(cells) < column {
+				cells = append(cells, "")
+			}
+
+			cells = append(cells, value)
+		}
+
+		rows = append(rows, cells)
+	}
+
+	return rows, nil
+}
+
+func firstSheet(files map[string]*zip.File) (string, error) {
+
+	var (
+		book workbook
+		rels relationships
+	)
+
+	if files["xl/workbook.xml"] != nil && files["xl/_rels/workbook.xml.rels"] != nil {
+		if decode(files["xl/workbook.xml"], &book) == nil && decode(files["xl/_rels/workbook.xml.rels"], &rels) == nil && len(book.Sheets) > 0 {
+			for _, rel := range rels.Items {
+				if rel.Id != book.Sheets[0].Id {
+					continue
+				}
+
+				target := strings.TrimPrefix(rel.Target, "/")
+				if !strings.HasPrefix(target, "xl/") {
+					target = path.Join("xl", target)
+				}
+
+				if files[target] != nil {
+					return target, nil
+				}
+			}
+		}
+	}
+
+	if files["xl/worksheets/sheet1.xml"] != nil {
+		return "xl/worksheets/sheet1.xml", nil
+	}
+
+	return "", errors.New("xlsx: workbook has no worksheets")
+}
+
+func decode(file *zip.File, v interface{}) error {
+
+	reader, err := file.Open()
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	return xml.NewDecoder(reader).Decode(v)
+}
+
+// columnIndex converts the letters of a cell reference ("AB12") to a zero based column.
+func columnIndex(ref string) int {
+
+	var index int
+
+	for _, ch := range ref {
+		if ch < 'A' || ch > 'Z' {
+			break
+		}
+		index = index*26 + int(ch-'A'+1)
+	}
+
+	return index - 1
+}
+
+// formatNumber undoes the exponent notation spreadsheets use for long
+// numbers so barcodes survive the round trip.
+func formatNumber(value string) string {
+
+	if !strings.ContainsAny(value, "eE") {
+		return value
+	}
+
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return value
+	}
+
+	return strconv.FormatFloat(number, 'f', -1, 64)
+}
@@ -0,0 +1,154 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const (
+	contentTypesXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"><Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/><Default Extension="xml" ContentType="application/xml"/><Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/><Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/></Types>`
+
+	rootRelsXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/></Relationships>`
+
+	workbookRelsXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/></Relationships>`
+
+	workbookXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="%s" sheetId="1" r:id="rId1"/></sheets></workbook>`
+)
+
+// Writer streams a single-sheet workbook row by row, so exports of any size
+// need only constant memory. Cells are inline strings unless the column was
+// marked numeric.
+type Writer struct {
+	zw      *zip.Writer
+	sheet   *bufio.Writer
+	row     int
+	numeric map[int]bool
+}
+
+func NewWriter(w io.Writer, sheetName string) (*Writer, error) {
+
+	zw := zip.NewWriter(w)
+
+	var buf strings.Builder
+	xml.EscapeText(&buf, []byte(sheetName))
+
+	parts := []struct {
+		name    string
+		content string
+	}{
+		{"[Content_Types].xml", contentTypesXML},
+		{"_rels/.rels", rootRelsXML},
+		{"xl/_rels/workbook.xml.rels", workbookRelsXML},
+		{"xl/workbook.xml", strings.Replace(workbookXML, "%s", buf.String(), 1)},
+	}
+
+	for _, part := range parts {
+		file, err := zw.Create(part.name)
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = io.WriteString(file, part.content)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	file, err := zw.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, err
+	}
+
+	sheet := bufio.NewWriter(file)
+
+	_, err = sheet.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n" +
+		`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Writer{
+		zw:      zw,
+		sheet:   sheet,
+		numeric: map[int]bool{},
+	}, nil
+}
+
+// SetNumeric marks zero based columns whose values are written as numbers.
+func (w *Writer) SetNumeric(columns ...int) {
+	for _, column := range columns {
+		w.numeric[column] = true
+	}
+}
+
+func (w *Writer) Write(record []string) error {
+
+	w.row++
+
+	fmt.Fprintf(w.sheet, `<row r="%d">`, w.row)
+
+	for i, value := range record {
+		ref := columnName(i) + strconv.Itoa(w.row)
+
+		if w.numeric[i] && w.row > 1 {
+			if _, err := strconv.ParseFloat(value, 64); err == nil {
+				fmt.Fprintf(w.sheet, `<c r="%s"><v>%s</v></c>`, ref, value)
+				continue
+			}
+		}
+
+		fmt.Fprintf(w.sheet, `<c r="%s" t="inlineStr"><is><t xml:space="preserve">`, ref)
+		xml.EscapeText(w.sheet, []byte(value))
+		w.sheet.WriteString(`</t></is></c>`)
+	}
+
+	_, err := w.sheet.WriteString(`</row>`)
+	return err
+}
+
+// Flush pushes buffered rows to the underlying writer.
+func (w *Writer) Flush() error {
+
+	err := w.sheet.Flush()
+	if err != nil {
+		return err
+	}
+
+	return w.zw.Flush()
+}
+
+func (w *Writer) Close() error {
+
+	_, err := w.sheet.WriteString(`</sheetData></worksheet>`)
+	if err != nil {
+		return err
+	}
+
+	err = w.sheet.Flush()
+	if err != nil {
+		return err
+	}
+
+	return w.zw.Close()
+}
+
+// columnName converts a zero based column index to its letters ("A", "AB").
+func columnName(index int) string {
+
+	var name string
+
+	for index++; index > 0; index = (index - 1) / 26 {
+		name = string(rune('A'+(index-1)%26)) + name
+	}
+
+	return name
+}
@@ -0,0 +1,223 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRoundTrip(t *testing.T) {
+
+	var (
+		buf  bytes.Buffer
+		wide = make([]string, 30)
+	)
+
+	for i := range wide {
+		wide[i] = columnName(i)
+	}
+
+	rows := [][]string{
+		{"name", "barcode", "price", "count"},
+		{"Sut <1L> & \"qaymoq\"", "4780001000017", "12500", "2.5"},
+		{"  Qo‘y go‘shti  ", "0047800010", "not a number", "-3"},
+		{"Чай", "", "0", "1e3"},
+		wide,
+	}
+
+	w, err := NewWriter(&buf, "Products & <more>")
+	if err != nil {
+		t.Fatalf("NewWriter() error = %v", err)
+	}
+
+	w.SetNumeric(2, 3)
+
+	for _, row := range rows {
+		err = w.Write(row)
+		if err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+
+	err = w.Close()
+	if err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	got, err := ReadAll(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("ReadAll() error = %v", err)
+	}
+
+	// Numeric cells come back as written, exponents spelled out.
+	want := [][]string{
+		rows[0],
+		rows[1],
+		rows[2],
+		{"Чай", "", "0", "1000"},
+		wide,
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadAll() = %q, want %q", got, want)
+	}
+
+	sheet := readPart(t, buf.Bytes(), "xl/worksheets/sheet1.xml")
+
+	if !strings.Contains(sheet, `<c r="C2"><v>12500</v></c>`) {
+		t.Errorf("numeric cell C2 is not written as a number: %s", sheet)
+	}
+
+	if !strings.Contains(sheet, `<c r="C1" t="inlineStr">`) {
+		t.Errorf("header cell C1 of a numeric column is not a string: %s", sheet)
+	}
+
+	if book := readPart(t, buf.Bytes(), "xl/workbook.xml"); !strings.Contains(book, `name="Products &amp; &lt;more&gt;"`) {
+		t.Errorf("sheet name is not escaped: %s", book)
+	}
+}
+
+func TestReadAllSharedStrings(t *testing.T) {
+
+	data := buildArchive(t, map[string]string{
+		"xl/sharedStrings.xml": `<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+			`<si><t>name</t></si><si><r><t>Sut </t></r><r><t>1L</t></r></si></sst>`,
+		"xl/worksheets/sheet1.xml": `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>` +
+			`<row r="1"><c r="A1" t="s"><v>0</v></c><c r="C1" t="s"><v>0</v></c></row>` +
+			`<row r="2"><c r="B2" t="s"><v>1</v></c><c r="C2"><v>4.780001000017E12</v></c></row>` +
+			`</sheetData></worksheet>`,
+	})
+
+	got, err := ReadAll(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("ReadAll() error = %v", err)
+	}
+
+	want := [][]string{
+		{"name", "", "name"},
+		{"", "Sut 1L", "4780001000017"},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadAll() = %q, want %q", got, want)
+	}
+}
+
+func TestReadAllErrors(t *testing.T) {
+
+	tests := []struct {
+		name  string
+		parts map[string]string
+	}{
+		{
+			name:  "no worksheet",
+			parts: map[string]string{"xl/workbook.xml": `<workbook/>`},
+		},
+		{
+			name: "shared string out of range",
+			parts: map[string]string{
+				"xl/worksheets/sheet1.xml": `<worksheet><sheetData><row><c r="A1" t="s"><v>3</v></c></row></sheetData></worksheet>`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			data := buildArchive(t, tt.parts)
+
+			if _, err := ReadAll(bytes.NewReader(data), int64(len(data))); err == nil {
+				t.Error("ReadAll() error = nil, want an error")
+			}
+		})
+	}
+
+	if _, err := ReadAll(strings.NewReader("name,price\n"), 11); err == nil {
+		t.Error("ReadAll() of a CSV error = nil, want an error")
+	}
+}
+
+func TestColumnName(t *testing.T) {
+
+	tests := []struct {
+		index int
+		name  string
+	}{
+		{0, "A"},
+		{25, "Z"},
+		{26, "AA"},
+		{27, "AB"},
+		{701, "ZZ"},
+		{702, "AAA"},
+	}
+
+	for _, tt := range tests {
+		if got := columnName(tt.index); got != tt.name {
+			t.Errorf("columnName(%d) = %q, want %q", tt.index, got, tt.name)
+		}
+
+		if got := columnIndex(tt.name + "12"); got != tt.index {
+			t.Errorf("columnIndex(%q) = %d, want %d", tt.name+"12", got, tt.index)
+		}
+	}
+}
+
+func buildArchive(t *testing.T, parts map[string]string) []byte {
+
+	var buf bytes.Buffer
+
+	zw := zip.NewWriter(&buf)
+
+	for name, content := range parts {
+		file, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = file.Write([]byte(content))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	err := zw.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+func readPart(t *testing.T, data []byte, name string) string {
+
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, file := range archive.File {
+		if file.Name != name {
+			continue
+		}
+
+		reader, err := file.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer reader.Close()
+
+		var part bytes.Buffer
+
+		_, err = part.ReadFrom(reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return part.String()
+	}
+
+	t.Fatalf("archive has no %s", name)
+	return ""
+}
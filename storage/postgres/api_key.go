@@ -0,0 +1,289 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	uuid "github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+
+	"market/api/models"
+	"market/pkg/helper"
+)
+
+type ApiKeyRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewApiKeyRepo(db *pgxpool.Pool) *ApiKeyRepo {
+	return &ApiKeyRepo{
+		db: db,
+	}
+}
+
+func (r *ApiKeyRepo) Create(ctx context.Context, req *models.CreateApiKey) (string, error) {
+
+	var (
+		id    = uuid.New().String()
+		query string
+	)
+
+	query = `
+		INSERT INTO api_key(id, name, prefix, key_hash, scopes, rate_limit, created_by, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NOW())
+	`
+
+	_, err := r.db.Exec(ctx, query,
+		id,
+		req.Name,
+		req.Prefix,
+		req.KeyHash,
+		req.Scopes,
+		req.RateLimit,
+		helper.NewNullString(req.CreatedBy),
+	)
+
+	if err != nil {
+		return "", mapError(err)
+	}
+
+	return id, nil
+}
+
+func (r *ApiKeyRepo) GetByID(ctx context.Context, req *models.ApiKeyPrimaryKey) (*models.ApiKey, error) {
+	return r.getBy(ctx, "id", req.Id)
+}
+
+// GetByHash looks up a key that was not revoked.
+func (r *ApiKeyRepo) GetByHash(ctx context.Context, req *models.ApiKeyHash) (*models.ApiKey, error) {
+	return r.getBy(ctx, "revoked_at IS NULL AND key_hash", req.KeyHash)
+}
+
+func (r *ApiKeyRepo) getBy(ctx context.Context, column, value string) (*models.ApiKey, error) {
+
+	var (
+		query string
+
+		id         sql.NullString
+		name       sql.NullString
+		prefix     sql.NullString
+		scopes     []string
+		rateLimit  sql.NullInt64
+		createdBy  sql.NullString
+		lastUsedAt sql.NullString
+		revokedAt  sql.NullString
+		createdAt  sql.NullString
+		updatedAt  sql.NullString
+	)
+
+	query = `
+		SELECT
+			id,
+			name,
+			prefix,
+			scopes,
+			rate_limit,
+			created_by,
+			last_used_at,
+			revoked_at,
+			created_at,
+			updated_at
+		FROM api_key
+		WHERE ` + column + ` = $1
+	`
+
+	err := r.db.QueryRow(ctx, query, value).Scan(
+		&id,
+		&name,
+		&prefix,
+		&scopes,
+		&rateLimit,
+		&createdBy,
+		&lastUsedAt,
+		&revokedAt,
+		&createdAt,
+		&updatedAt,
+	)
+
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	return &models.ApiKey{
+		Id:         id.String,
+		Name:       name.String,
+		Prefix:     prefix.String,
+		Scopes:     scopes,
+		RateLimit:  int(rateLimit.Int64),
+		CreatedBy:  createdBy.String,
+		LastUsedAt: lastUsedAt.String,
+		RevokedAt:  revokedAt.String,
+		CreatedAt:  createdAt.String,
+		UpdatedAt:  updatedAt.String,
+	}, nil
+}
+
+func (r *ApiKeyRepo) GetList(ctx context.Context, req *models.ApiKeyGetListRequest) (*models.ApiKeyGetListResponse, error) {
+
+	var (
+		resp   = &models.ApiKeyGetListResponse{}
+		query  string
+		where  = " WHERE revoked_at IS NULL"
+		order  = " ORDER BY created_at, id"
+		offset = " OFFSET 0"
+		limit  = " LIMIT 10"
+		params = map[string]interface{}{}
+	)
+
+	query = `
+		SELECT
+			COUNT(*) OVER(),
+			id,
+			name,
+			prefix,
+			scopes,
+			rate_limit,
+			created_by,
+			last_used_at,
+			revoked_at,
+			created_at,
+			updated_at
+		FROM api_key
+	`
+
+	if req.Offset > 0 {
+		offset = fmt.Sprintf(" OFFSET %d", req.Offset)
+	}
+
+	if req.Limit > 0 {
+		limit = fmt.Sprintf(" LIMIT %d", req.Limit)
+	}
+
+	if req.Revoked {
+		where = " WHERE TRUE"
+	}
+
+	if req.Search != "" {
+		where += ` AND (name ILIKE '%' || :search || '%' OR prefix ILIKE :search || '%')`
+		params["search"] = req.Search
+	}
+
+	query += where + order + offset + limit
+
+	query, args := helper.ReplaceQueryParams(query, params)
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			id         sql.NullString
+			name       sql.NullString
+			prefix     sql.NullString
+			scopes     []string
+			rateLimit  sql.NullInt64
+			createdBy  sql.NullString
+			lastUsedAt sql.NullString
+			revokedAt  sql.NullString
+			createdAt  sql.NullString
+			updatedAt  sql.NullString
+		)
+
+		err := rows.Scan(
+			&resp.Count,
+			&id,
+			&name,
+			&prefix,
+			&scopes,
+			&rateLimit,
+			&createdBy,
+			&lastUsedAt,
+			&revokedAt,
+			&createdAt,
+			&updatedAt,
+		)
+
+		if err != nil {
+			return nil, mapError(err)
+		}
+
+		resp.ApiKeys = append(resp.ApiKeys, &models.ApiKey{
+			Id:         id.String,
+			Name:       name.String,
+			Prefix:     prefix.String,
+			Scopes:     scopes,
+			RateLimit:  int(rateLimit.Int64),
+			CreatedBy:  createdBy.String,
+			LastUsedAt: lastUsedAt.String,
+			RevokedAt:  revokedAt.String,
+			CreatedAt:  createdAt.String,
+			UpdatedAt:  updatedAt.String,
+		})
+	}
+
+	return resp, nil
+}
+
+// Rotate replaces the secret of a key that was not revoked, the old secret
+// stops working at once.
+func (r *ApiKeyRepo) Rotate(ctx context.Context, req *models.RotateApiKey) (int64, error) {
+
+	query := `
+		UPDATE
+			api_key
+		SET
+			prefix = $2,
+			key_hash = $3,
+			updated_at = NOW()
+		WHERE id = $1 AND revoked_at IS NULL
+	`
+
+	result, err := r.db.Exec(ctx, query, req.Id, req.Prefix, req.KeyHash)
+	if err != nil {
+		return 0, mapError(err)
+	}
+
+	if result.RowsAffected() <= 0 {
+		return 0, mapError(pgx.ErrNoRows)
+	}
+
+	return result.RowsAffected(), nil
+}
+
+// Touch records the use of a key, at most once a minute to keep writes off
+// the hot path.
+func (r *ApiKeyRepo) Touch(ctx context.Context, req *models.ApiKeyPrimaryKey) error {
+
+	query := `
+		UPDATE api_key SET last_used_at = NOW()
+		WHERE id = $1 AND (last_used_at IS NULL OR last_used_at < NOW() - INTERVAL '1 minute')
+	`
+
+	_, err := r.db.Exec(ctx, query, req.Id)
+	if err != nil {
+		return mapError(err)
+	}
+
+	return nil
+}
+
+// Revoke keeps the key for its history, it can not be used or rotated
+// afterwards.
+func (r *ApiKeyRepo) Revoke(ctx context.Context, req *models.ApiKeyPrimaryKey) (int64, error) {
+
+	result, err := r.db.Exec(ctx, "UPDATE api_key SET revoked_at = NOW(), updated_at = NOW() WHERE id = $1 AND revoked_at IS NULL", req.Id)
+	if err != nil {
+		return 0, mapError(err)
+	}
+
+	if result.RowsAffected() <= 0 {
+		return 0, mapError(pgx.ErrNoRows)
+	}
+
+	return result.RowsAffected(), nil
+}
@@ -0,0 +1,261 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	uuid "github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+
+	"market/api/models"
+	"market/pkg/audit"
+	"market/pkg/helper"
+	"market/storage"
+)
+
+type AuditLogRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewAuditLogRepo(db *pgxpool.Pool) *AuditLogRepo {
+	return &AuditLogRepo{
+		db: db,
+	}
+}
+
+func (r *AuditLogRepo) GetList(ctx context.Context, req *models.AuditLogGetListRequest) (*models.AuditLogGetListResponse, error) {
+
+	var (
+		resp   = &models.AuditLogGetListResponse{}
+		query  string
+		where  = " WHERE TRUE"
+		order  = " ORDER BY created_at DESC, id"
+		offset = " OFFSET 0"
+		limit  = " LIMIT 10"
+		params = map[string]interface{}{}
+	)
+
+	query = `
+		SELECT
+			COUNT(*) OVER(),
+			id,
+			actor_type,
+			actor_id,
+			entity,
+			entity_id,
+			action,
+			before,
+			after,
+			request_id,
+			created_at
+		FROM audit_log
+	`
+
+	if req.Offset > 0 {
+		offset = fmt.Sprintf(" OFFSET %d", req.Offset)
+	}
+
+	if req.Limit > 0 {
+		limit = fmt.Sprintf(" LIMIT %d", req.Limit)
+	}
+
+	if req.Entity != "" {
+		where += " AND entity = :entity"
+		params["entity"] = req.Entity
+	}
+
+	if req.EntityId != "" {
+		where += " AND entity_id = :object_id"
+		params["object_id"] = req.EntityId
+	}
+
+	if req.ActorId != "" {
+		where += " AND actor_id = :actor_id"
+		params["actor_id"] = req.ActorId
+	}
+
+	if req.Action != "" {
+		where += " AND action = :action"
+		params["action"] = req.Action
+	}
+
+	if req.From != "" {
+		where += " AND created_at >= :from"
+		params["from"] = req.From
+	}
+
+	if req.To != "" {
+		where += " AND created_at <= :to"
+		params["to"] = req.To
+	}
+
+	query += where + order + offset + limit
+
+	query, args := helper.ReplaceQueryParams(query, params)
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			id        sql.NullString
+			actorType sql.NullString
+			actorId   sql.NullString
+			entity    sql.NullString
+			entityId  sql.NullString
+			action    sql.NullString
+			before    []byte
+			after     []byte
+			requestId sql.NullString
+			createdAt sql.NullString
+		)
+
+		err := rows.Scan(
+			&resp.Count,
+			&id,
+			&actorType,
+			&actorId,
+			&entity,
+			&entityId,
+			&action,
+			&before,
+			&after,
+			&requestId,
+			&createdAt,
+		)
+
+		if err != nil {
+			return nil, mapError(err)
+		}
+
+		resp.AuditLogs = append(resp.AuditLogs, &models.AuditLog{
+			Id:        id.String,
+			ActorType: actorType.String,
+			ActorId:   actorId.String,
+			Entity:    entity.String,
+			EntityId:  entityId.String,
+			Action:    action.String,
+			Before:    before,
+			After:     after,
+			RequestId: requestId.String,
+			CreatedAt: createdAt.String,
+		})
+	}
+
+	return resp, rows.Err()
+}
+
+// snapshot returns the row of table with id as a JSON object and locks it
+// for the rest of tx, nil when there is no such row.
+func snapshot(ctx context.Context, tx pgx.Tx, table, id string) ([]byte, error) {
+
+	var row []byte
+
+	err := tx.QueryRow(ctx, "SELECT to_jsonb(t) FROM "+table+" AS t WHERE id = $1 FOR UPDATE", id).Scan(&row)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	return row, nil
+}
+
+// isDeleted reports whether a row snapshot is soft deleted.
+func isDeleted(row []byte) bool {
+
+	var fields struct {
+		DeletedAt *string `json:"deleted_at"`
+	}
+
+	_ = json.Unmarshal(row, &fields)
+
+	return fields.DeletedAt != nil
+}
+
+// checkVersion fails with storage.ErrVersionConflict when version is set and
+// differs from the one of the row snapshot.
+func checkVersion(row []byte, version int) error {
+
+	var fields struct {
+		Version int `json:"version"`
+	}
+
+	if version <= 0 || row == nil {
+		return nil
+	}
+
+	_ = json.Unmarshal(row, &fields)
+
+	if fields.Version != version {
+		return storage.ErrVersionConflict
+	}
+
+	return nil
+}
+
+// recordChange compares the row of table with id to its snapshot taken
+// before the change, in the same tx, and appends what changed to the audit
+// log under entity. Writes that changed nothing are not recorded.
+func recordChange(ctx context.Context, tx pgx.Tx, entity, table, id string, before []byte) error {
+
+	after, err := snapshot(ctx, tx, table, id)
+	if err != nil {
+		return err
+	}
+
+	var action string
+	switch {
+	case before == nil && after == nil:
+		return nil
+	case before == nil:
+		action = audit.ActionCreate
+	case after == nil && isDeleted(before):
+		action = audit.ActionPurge
+	case after == nil:
+		action = audit.ActionDelete
+	case !isDeleted(before) && isDeleted(after):
+		action = audit.ActionDelete
+	case isDeleted(before) && !isDeleted(after):
+		action = audit.ActionRestore
+	default:
+		action = audit.ActionUpdate
+	}
+
+	before, after, err = audit.Diff(before, after)
+	if err != nil {
+		return err
+	}
+
+	if before == nil && after == nil {
+		return nil
+	}
+
+	actor := audit.ActorFrom(ctx)
+
+	query := `
+		INSERT INTO audit_log(id, actor_type, actor_id, entity, entity_id, action, before, after, request_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+
+	_, err = tx.Exec(ctx, query,
+		uuid.New().String(),
+		actor.Type,
+		helper.NewNullString(actor.Id),
+		entity,
+		id,
+		action,
+		before,
+		after,
+		helper.NewNullString(audit.RequestId(ctx)),
+	)
+
+	return err
+}
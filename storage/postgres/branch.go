@@ -6,6 +6,7 @@ import (
 	"fmt"
 
 	uuid "github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
 	"github.com/jackc/pgx/v4/pgxpool"
 
 	"market/api/models"
@@ -29,12 +30,18 @@ func (r *BranchRepo) Create(ctx context.Context, req *models.CreateBranch) (stri
 		query string
 	)
 
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return "", mapError(err)
+	}
+	defer tx.Rollback(ctx)
+
 	query = `
 		INSERT INTO branch(id, name, address, phone_number, updated_at)
 		VALUES ($1, $2, $3, $4,NOW())
 	`
 
-	_, err := r.db.Exec(ctx, query,
+	_, err = tx.Exec(ctx, query,
 		id,
 		req.Name,
 		req.Address,
@@ -42,7 +49,17 @@ func (r *BranchRepo) Create(ctx context.Context, req *models.CreateBranch) (stri
 	)
 
 	if err != nil {
-		return "", err
+		return "", mapError(err)
+	}
+
+	err = recordChange(ctx, tx, "branch", "branch", id, nil)
+	if err != nil {
+		return "", mapError(err)
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return "", mapError(err)
 	}
 
 	return id, nil
@@ -57,8 +74,10 @@ func (r *BranchRepo) GetByID(ctx context.Context, req *models.BranchPrimaryKey)
 		name         sql.NullString
 		address      sql.NullString
 		phone_number sql.NullString
+		version      sql.NullInt32
 		createdAt    sql.NullString
 		updatedAt    sql.NullString
+		deletedAt    sql.NullString
 	)
 
 	query = `
@@ -67,23 +86,27 @@ func (r *BranchRepo) GetByID(ctx context.Context, req *models.BranchPrimaryKey)
 			name,
 			address,
 			phone_number,
+			version,
 			created_at,
-			updated_at
+			updated_at,
+			deleted_at
 		FROM branch
-		WHERE id = $1
+		WHERE id = $1 AND (deleted_at IS NULL OR $2)
 	`
 
-	err := r.db.QueryRow(ctx, query, req.Id).Scan(
+	err := r.db.QueryRow(ctx, query, req.Id, req.IncludeDeleted).Scan(
 		&id,
 		&name,
 		&address,
 		&phone_number,
+		&version,
 		&createdAt,
 		&updatedAt,
+		&deletedAt,
 	)
 
 	if err != nil {
-		return nil, err
+		return nil, mapError(err)
 	}
 
 	return &models.Branch{
@@ -91,8 +114,10 @@ func (r *BranchRepo) GetByID(ctx context.Context, req *models.BranchPrimaryKey)
 		Name:        name.String,
 		Address:     address.String,
 		PhoneNumber: phone_number.String,
+		Version:     int(version.Int32),
 		CreatedAt:   createdAt.String,
 		UpdatedAt:   updatedAt.String,
+		DeletedAt:   deletedAt.String,
 	}, nil
 }
 
@@ -102,8 +127,10 @@ func (r *BranchRepo) GetList(ctx context.Context, req *models.BranchGetListReque
 		resp   = &models.BranchGetListResponse{}
 		query  string
 		where  = " WHERE TRUE"
+		order  = " ORDER BY created_at, id"
 		offset = " OFFSET 0"
 		limit  = " LIMIT 10"
+		params = map[string]interface{}{}
 	)
 
 	query = `
@@ -113,8 +140,10 @@ func (r *BranchRepo) GetList(ctx context.Context, req *models.BranchGetListReque
 			name,
 			address,
 			phone_number,
+			version,
 			created_at,
-			updated_at
+			updated_at,
+			deleted_at
 		FROM branch
 	`
 
@@ -127,15 +156,28 @@ func (r *BranchRepo) GetList(ctx context.Context, req *models.BranchGetListReque
 	}
 
 	if req.Search != "" {
-		where += ` AND title ILIKE '%' || '` + req.Search + `' || '%'`
+		where += ` AND name ILIKE '%' || :search || '%'`
+		params["search"] = req.Search
+	}
+
+	if req.BranchIds != nil {
+		where += " AND id = ANY(:scope::UUID[])"
+		params["scope"] = req.BranchIds
+	}
+
+	if !req.IncludeDeleted {
+		where += " AND deleted_at IS NULL"
 	}
 
-	query += where + offset + limit
+	query += where + order + offset + limit
 
-	rows, err := r.db.Query(ctx, query)
+	query, args := helper.ReplaceQueryParams(query, params)
+
+	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
-		return nil, err
+		return nil, mapError(err)
 	}
+	defer rows.Close()
 
 	for rows.Next() {
 		var (
@@ -143,8 +185,10 @@ func (r *BranchRepo) GetList(ctx context.Context, req *models.BranchGetListReque
 			name         sql.NullString
 			address      sql.NullString
 			phone_number sql.NullString
+			version      sql.NullInt32
 			createdAt    sql.NullString
 			updatedAt    sql.NullString
+			deletedAt    sql.NullString
 		)
 
 		err := rows.Scan(
@@ -153,12 +197,14 @@ func (r *BranchRepo) GetList(ctx context.Context, req *models.BranchGetListReque
 			&name,
 			&address,
 			&phone_number,
+			&version,
 			&createdAt,
 			&updatedAt,
+			&deletedAt,
 		)
 
 		if err != nil {
-			return nil, err
+			return nil, mapError(err)
 		}
 
 		resp.Branches = append(resp.Branches, &models.Branch{
@@ -166,12 +212,14 @@ func (r *BranchRepo) GetList(ctx context.Context, req *models.BranchGetListReque
 			Name:        name.String,
 			Address:     address.String,
 			PhoneNumber: phone_number.String,
+			Version:     int(version.Int32),
 			CreatedAt:   createdAt.String,
 			UpdatedAt:   updatedAt.String,
+			DeletedAt:   deletedAt.String,
 		})
 	}
 
-	return resp, nil
+	return resp, rows.Err()
 }
 
 func (r *BranchRepo) Update(ctx context.Context, req *models.UpdateBranch) (int64, error) {
@@ -188,8 +236,9 @@ func (r *BranchRepo) Update(ctx context.Context, req *models.UpdateBranch) (int6
 			name = :name,
 			address = :address,
 			phone_number = :phone_number,
+			version = version + 1,
 			updated_at = NOW()
-		WHERE id = :id
+		WHERE id = :id AND deleted_at IS NULL
 	`
 
 	params = map[string]interface{}{
@@ -201,9 +250,39 @@ func (r *BranchRepo) Update(ctx context.Context, req *models.UpdateBranch) (int6
 
 	query, args := helper.ReplaceQueryParams(query, params)
 
-	result, err := r.db.Exec(ctx, query, args...)
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, mapError(err)
+	}
+	defer tx.Rollback(ctx)
+
+	before, err := snapshot(ctx, tx, "branch", req.Id)
+	if err != nil {
+		return 0, mapError(err)
+	}
+
+	err = checkVersion(before, req.Version)
+	if err != nil {
+		return 0, mapError(err)
+	}
+
+	result, err := tx.Exec(ctx, query, args...)
 	if err != nil {
-		return 0, err
+		return 0, mapError(err)
+	}
+
+	if result.RowsAffected() <= 0 {
+		return 0, mapError(pgx.ErrNoRows)
+	}
+
+	err = recordChange(ctx, tx, "branch", "branch", req.Id, before)
+	if err != nil {
+		return 0, mapError(err)
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return 0, mapError(err)
 	}
 
 	return result.RowsAffected(), nil
@@ -211,10 +290,42 @@ func (r *BranchRepo) Update(ctx context.Context, req *models.UpdateBranch) (int6
 
 func (r *BranchRepo) Delete(ctx context.Context, req *models.BranchPrimaryKey) error {
 
-	_, err := r.db.Exec(ctx, "DELETE FROM branch WHERE id = $1", req.Id)
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return mapError(err)
+	}
+	defer tx.Rollback(ctx)
+
+	err = softDelete(ctx, tx, "branch", "branch", req.Id)
+	if err != nil {
+		return mapError(err)
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *BranchRepo) Restore(ctx context.Context, req *models.BranchPrimaryKey) (int64, error) {
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, mapError(err)
+	}
+	defer tx.Rollback(ctx)
+
+	rowsAffected, err := restore(ctx, tx, "branch", "branch", req.Id)
+	if err != nil {
+		return 0, mapError(err)
+	}
+
+	err = tx.Commit(ctx)
 	if err != nil {
-		return err
+		return 0, mapError(err)
 	}
 
-	return nil
+	return rowsAffected, nil
+}
+
+// Purge removes branches deleted more than days ago.
+func (r *BranchRepo) Purge(ctx context.Context, days int) (int64, error) {
+	return purgeDeleted(ctx, r.db, "branch", "branch", days)
 }
@@ -6,6 +6,7 @@ import (
 	"fmt"
 
 	uuid "github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
 	"github.com/jackc/pgx/v4/pgxpool"
 
 	"market/api/models"
@@ -29,19 +30,35 @@ func (r *CategoryRepo) Create(ctx context.Context, req *models.CreateCategory) (
 		query string
 	)
 
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return "", mapError(err)
+	}
+	defer tx.Rollback(ctx)
+
 	query = `
 		INSERT INTO category(id, title, parent_id, updated_at)
 		VALUES ($1, $2, $3, NOW())
 	`
 
-	_, err := r.db.Exec(ctx, query,
+	_, err = tx.Exec(ctx, query,
 		id,
 		req.Title,
 		helper.NewNullString(req.ParentID),
 	)
 
 	if err != nil {
-		return "", err
+		return "", mapError(err)
+	}
+
+	err = recordChange(ctx, tx, "category", "category", id, nil)
+	if err != nil {
+		return "", mapError(err)
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return "", mapError(err)
 	}
 
 	return id, nil
@@ -55,8 +72,10 @@ func (r *CategoryRepo) GetByID(ctx context.Context, req *models.CategoryPrimaryK
 		id        sql.NullString
 		title     sql.NullString
 		parentId  sql.NullString
+		version   sql.NullInt32
 		createdAt sql.NullString
 		updatedAt sql.NullString
+		deletedAt sql.NullString
 	)
 
 	query = `
@@ -64,30 +83,36 @@ func (r *CategoryRepo) GetByID(ctx context.Context, req *models.CategoryPrimaryK
 			id,
 			title,
 			parent_id,
+			version,
 			created_at,
-			updated_at
+			updated_at,
+			deleted_at
 		FROM category
-		WHERE id = $1
+		WHERE id = $1 AND (deleted_at IS NULL OR $2)
 	`
 
-	err := r.db.QueryRow(ctx, query, req.Id).Scan(
+	err := r.db.QueryRow(ctx, query, req.Id, req.IncludeDeleted).Scan(
 		&id,
 		&title,
 		&parentId,
+		&version,
 		&createdAt,
 		&updatedAt,
+		&deletedAt,
 	)
 
 	if err != nil {
-		return nil, err
+		return nil, mapError(err)
 	}
 
 	return &models.Category{
 		Id:        id.String,
 		Title:     title.String,
 		ParentID:  parentId.String,
+		Version:   int(version.Int32),
 		CreatedAt: createdAt.String,
 		UpdatedAt: updatedAt.String,
+		DeletedAt: deletedAt.String,
 	}, nil
 }
 
@@ -97,8 +122,10 @@ func (r *CategoryRepo) GetList(ctx context.Context, req *models.CategoryGetListR
 		resp   = &models.CategoryGetListResponse{}
 		query  string
 		where  = " WHERE TRUE"
+		order  = " ORDER BY created_at, id"
 		offset = " OFFSET 0"
 		limit  = " LIMIT 10"
+		params = map[string]interface{}{}
 	)
 
 	query = `
@@ -107,8 +134,10 @@ func (r *CategoryRepo) GetList(ctx context.Context, req *models.CategoryGetListR
 			id,
 			title,
 			parent_id,
+			version,
 			created_at,
-			updated_at
+			updated_at,
+			deleted_at
 		FROM category
 	`
 
@@ -121,23 +150,33 @@ func (r *CategoryRepo) GetList(ctx context.Context, req *models.CategoryGetListR
 	}
 
 	if req.Search != "" {
-		where += ` AND title ILIKE '%' || '` + req.Search + `' || '%'`
+		where += ` AND title ILIKE '%' || :search || '%'`
+		params["search"] = req.Search
 	}
 
-	query += where + offset + limit
+	if !req.IncludeDeleted {
+		where += " AND deleted_at IS NULL"
+	}
+
+	query += where + order + offset + limit
+
+	query, args := helper.ReplaceQueryParams(query, params)
 
-	rows, err := r.db.Query(ctx, query)
+	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
-		return nil, err
+		return nil, mapError(err)
 	}
+	defer rows.Close()
 
 	for rows.Next() {
 		var (
 			id        sql.NullString
 			title     sql.NullString
 			parentId  sql.NullString
+			version   sql.NullInt32
 			createdAt sql.NullString
 			updatedAt sql.NullString
+			deletedAt sql.NullString
 		)
 
 		err := rows.Scan(
@@ -145,24 +184,28 @@ func (r *CategoryRepo) GetList(ctx context.Context, req *models.CategoryGetListR
 			&id,
 			&title,
 			&parentId,
+			&version,
 			&createdAt,
 			&updatedAt,
+			&deletedAt,
 		)
 
 		if err != nil {
-			return nil, err
+			return nil, mapError(err)
 		}
 
 		resp.Categories = append(resp.Categories, &models.Category{
 			Id:        id.String,
 			Title:     title.String,
 			ParentID:  parentId.String,
+			Version:   int(version.Int32),
 			CreatedAt: createdAt.String,
 			UpdatedAt: updatedAt.String,
+			DeletedAt: deletedAt.String,
 		})
 	}
 
-	return resp, nil
+	return resp, rows.Err()
 }
 
 func (r *CategoryRepo) Update(ctx context.Context, req *models.UpdateCategory) (int64, error) {
@@ -178,8 +221,9 @@ func (r *CategoryRepo) Update(ctx context.Context, req *models.UpdateCategory) (
 		SET
 			title = :title,
 			parent_id = :parent_id,
+			version = version + 1,
 			updated_at = NOW()
-		WHERE id = :id
+		WHERE id = :id AND deleted_at IS NULL
 	`
 
 	params = map[string]interface{}{
@@ -190,9 +234,39 @@ func (r *CategoryRepo) Update(ctx context.Context, req *models.UpdateCategory) (
 
 	query, args := helper.ReplaceQueryParams(query, params)
 
-	result, err := r.db.Exec(ctx, query, args...)
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, mapError(err)
+	}
+	defer tx.Rollback(ctx)
+
+	before, err := snapshot(ctx, tx, "category", req.Id)
+	if err != nil {
+		return 0, mapError(err)
+	}
+
+	err = checkVersion(before, req.Version)
+	if err != nil {
+		return 0, mapError(err)
+	}
+
+	result, err := tx.Exec(ctx, query, args...)
 	if err != nil {
-		return 0, err
+		return 0, mapError(err)
+	}
+
+	if result.RowsAffected() <= 0 {
+		return 0, mapError(pgx.ErrNoRows)
+	}
+
+	err = recordChange(ctx, tx, "category", "category", req.Id, before)
+	if err != nil {
+		return 0, mapError(err)
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return 0, mapError(err)
 	}
 
 	return result.RowsAffected(), nil
@@ -200,10 +274,42 @@ func (r *CategoryRepo) Update(ctx context.Context, req *models.UpdateCategory) (
 
 func (r *CategoryRepo) Delete(ctx context.Context, req *models.CategoryPrimaryKey) error {
 
-	_, err := r.db.Exec(ctx, "DELETE FROM category WHERE id = $1", req.Id)
+	tx, err := r.db.Begin(ctx)
 	if err != nil {
-		return err
+		return mapError(err)
 	}
+	defer tx.Rollback(ctx)
+
+	err = softDelete(ctx, tx, "category", "category", req.Id)
+	if err != nil {
+		return mapError(err)
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *CategoryRepo) Restore(ctx context.Context, req *models.CategoryPrimaryKey) (int64, error) {
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, mapError(err)
+	}
+	defer tx.Rollback(ctx)
+
+	rowsAffected, err := restore(ctx, tx, "category", "category", req.Id)
+	if err != nil {
+		return 0, mapError(err)
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return 0, mapError(err)
+	}
+
+	return rowsAffected, nil
+}
 
-	return nil
+// Purge removes categories deleted more than days ago.
+func (r *CategoryRepo) Purge(ctx context.Context, days int) (int64, error) {
+	return purgeDeleted(ctx, r.db, "category", "category", days)
 }
@@ -0,0 +1,76 @@
+package postgres
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+
+	"market/pkg/apperr"
+)
+
+const (
+	sqlStateUniqueViolation     = "23505"
+	sqlStateForeignKeyViolation = "23503"
+	sqlStateInvalidText         = "22P02"
+	sqlStateSequenceLimit       = "2200H"
+)
+
+// keyDetail matches the detail of key violations, like
+// `Key (barcode)=(4780001) already exists.`
+var keyDetail = regexp.MustCompile(`^Key \(([^)]+)\)=\((.*)\) (.*)\.$`)
+
+// mapError turns the errors of pgx the API can tell the client about into
+// typed errors, keeping the original as the cause. Anything else, and
+// errors that are typed already, are returned as they are.
+func mapError(err error) error {
+
+	if err == nil {
+		return nil
+	}
+
+	if _, ok := apperr.As(err); ok {
+		return err
+	}
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return apperr.Wrap(apperr.NotFound("not found"), err)
+	}
+
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+
+	var field, value, reason string
+	if match := keyDetail.FindStringSubmatch(pgErr.Detail); match != nil {
+		field, value, reason = match[1], match[2], match[3]
+	}
+
+	switch pgErr.Code {
+	case sqlStateUniqueViolation:
+		appErr := apperr.New(apperr.KindConflict, "already_exists", pgErr.TableName+" already exists")
+		if field != "" {
+			appErr.Message = pgErr.TableName + " with " + field + " " + value + " already exists"
+			appErr.Fields = []apperr.FieldError{{Field: field, Message: "already exists"}}
+		}
+
+		return apperr.Wrap(appErr, err)
+
+	case sqlStateForeignKeyViolation:
+		// Writing a row that points nowhere is a bad request, removing
+		// one that is still pointed at is a conflict.
+		if strings.HasPrefix(reason, "is not present") {
+			return apperr.Wrap(apperr.Validation("referenced record does not exist", apperr.FieldError{Field: field, Message: value + " does not exist"}), err)
+		}
+
+		return apperr.Wrap(apperr.New(apperr.KindConflict, "still_referenced", pgErr.TableName+" is still in use"), err)
+
+	case sqlStateInvalidText:
+		return apperr.Wrap(apperr.Validation(pgErr.Message), err)
+	}
+
+	return err
+}
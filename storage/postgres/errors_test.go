@@ -0,0 +1,123 @@
+package postgres
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+
+	"market/pkg/apperr"
+)
+
+func TestMapError(t *testing.T) {
+
+	tests := []struct {
+		name        string
+		err         error
+		wantKind    apperr.Kind
+		wantCode    string
+		wantMessage string
+		wantFields  []apperr.FieldError
+		untyped     bool
+	}{
+		{
+			name:        "no rows",
+			err:         pgx.ErrNoRows,
+			wantKind:    apperr.KindNotFound,
+			wantCode:    apperr.CodeNotFound,
+			wantMessage: "not found",
+		},
+		{
+			name:        "unique violation",
+			err:         &pgconn.PgError{Code: sqlStateUniqueViolation, TableName: "product", Detail: "Key (barcode)=(4780001) already exists."},
+			wantKind:    apperr.KindConflict,
+			wantCode:    "already_exists",
+			wantMessage: "product with barcode 4780001 already exists",
+			wantFields:  []apperr.FieldError{{Field: "barcode", Message: "already exists"}},
+		},
+		{
+			name:        "unique violation without detail",
+			err:         &pgconn.PgError{Code: sqlStateUniqueViolation, TableName: "product"},
+			wantKind:    apperr.KindConflict,
+			wantCode:    "already_exists",
+			wantMessage: "product already exists",
+		},
+		{
+			name:        "reference to nothing",
+			err:         &pgconn.PgError{Code: sqlStateForeignKeyViolation, TableName: "product", Detail: `Key (category_id)=(7d3f) is not present in table "category".`},
+			wantKind:    apperr.KindValidation,
+			wantCode:    apperr.CodeValidation,
+			wantMessage: "referenced record does not exist",
+			wantFields:  []apperr.FieldError{{Field: "category_id", Message: "7d3f does not exist"}},
+		},
+		{
+			name:        "still referenced",
+			err:         &pgconn.PgError{Code: sqlStateForeignKeyViolation, TableName: "category", Detail: `Key (id)=(7d3f) is still referenced from table "product".`},
+			wantKind:    apperr.KindConflict,
+			wantCode:    "still_referenced",
+			wantMessage: "category is still in use",
+		},
+		{
+			name:        "invalid text",
+			err:         &pgconn.PgError{Code: sqlStateInvalidText, Message: `invalid input syntax for type uuid: "abc"`},
+			wantKind:    apperr.KindValidation,
+			wantCode:    apperr.CodeValidation,
+			wantMessage: `invalid input syntax for type uuid: "abc"`,
+		},
+		{
+			name:    "other SQLSTATE",
+			err:     &pgconn.PgError{Code: "40001", Message: "could not serialize access"},
+			untyped: true,
+		},
+		{
+			name:    "not a database error",
+			err:     errors.New("connection refused"),
+			untyped: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			err := mapError(tt.err)
+
+			if !errors.Is(err, tt.err) {
+				t.Errorf("mapError() = %v, does not keep the cause %v", err, tt.err)
+			}
+
+			appErr, ok := apperr.As(err)
+			if tt.untyped {
+				if ok {
+					t.Errorf("mapError() = %v, want it untyped", err)
+				}
+				return
+			}
+
+			if !ok {
+				t.Fatalf("mapError() = %v, want a typed error", err)
+			}
+
+			if appErr.Kind != tt.wantKind || appErr.Code != tt.wantCode || appErr.Message != tt.wantMessage {
+				t.Errorf("mapError() = %v %q %q, want %v %q %q", appErr.Kind, appErr.Code, appErr.Message, tt.wantKind, tt.wantCode, tt.wantMessage)
+			}
+
+			if !reflect.DeepEqual(appErr.Fields, tt.wantFields) {
+				t.Errorf("mapError() fields = %+v, want %+v", appErr.Fields, tt.wantFields)
+			}
+		})
+	}
+}
+
+func TestMapErrorPassesThrough(t *testing.T) {
+
+	if err := mapError(nil); err != nil {
+		t.Errorf("mapError(nil) = %v, want nil", err)
+	}
+
+	typed := apperr.Conflict("barcode is already in use")
+	if err := mapError(typed); err != typed {
+		t.Errorf("mapError() = %v, want the typed error as it is", err)
+	}
+}
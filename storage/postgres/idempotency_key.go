@@ -0,0 +1,155 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+
+	"market/api/models"
+)
+
+type IdempotencyKeyRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewIdempotencyKeyRepo(db *pgxpool.Pool) *IdempotencyKeyRepo {
+	return &IdempotencyKeyRepo{
+		db: db,
+	}
+}
+
+// Reserve takes the key for a new request, or over from an expired one, and
+// reports true. When the key is already taken it reports false together
+// with the request that holds it.
+func (r *IdempotencyKeyRepo) Reserve(ctx context.Context, req *models.CreateIdempotencyKey) (*models.IdempotencyKey, bool, error) {
+
+	var query string
+
+	query = `
+		INSERT INTO idempotency_key(key, actor_id, request_hash, expires_at)
+		VALUES ($1, $2, $3, NOW() + make_interval(hours => $4))
+		ON CONFLICT (key, actor_id) DO UPDATE
+		SET
+			request_hash = EXCLUDED.request_hash,
+			response_code = NULL,
+			response_type = NULL,
+			response_body = NULL,
+			created_at = NOW(),
+			expires_at = EXCLUDED.expires_at
+		WHERE idempotency_key.expires_at <= NOW()
+		RETURNING key
+	`
+
+	var key string
+
+	err := r.db.QueryRow(ctx, query, req.Key, req.ActorId, req.RequestHash, req.TTL).Scan(&key)
+	if err == nil {
+		return nil, true, nil
+	} else if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, false, mapError(err)
+	}
+
+	resp, err := r.GetByID(ctx, &models.IdempotencyKeyPrimaryKey{Key: req.Key, ActorId: req.ActorId})
+	if err != nil {
+		return nil, false, mapError(err)
+	}
+
+	return resp, false, nil
+}
+
+func (r *IdempotencyKeyRepo) GetByID(ctx context.Context, req *models.IdempotencyKeyPrimaryKey) (*models.IdempotencyKey, error) {
+
+	var (
+		query string
+
+		key          sql.NullString
+		actorId      sql.NullString
+		requestHash  sql.NullString
+		responseCode sql.NullInt32
+		responseType sql.NullString
+		responseBody []byte
+		createdAt    sql.NullString
+		expiresAt    sql.NullString
+	)
+
+	query = `
+		SELECT
+			key,
+			actor_id,
+			request_hash,
+			response_code,
+			response_type,
+			response_body,
+			created_at,
+			expires_at
+		FROM idempotency_key
+		WHERE key = $1 AND actor_id = $2
+	`
+
+	err := r.db.QueryRow(ctx, query, req.Key, req.ActorId).Scan(
+		&key,
+		&actorId,
+		&requestHash,
+		&responseCode,
+		&responseType,
+		&responseBody,
+		&createdAt,
+		&expiresAt,
+	)
+
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	return &models.IdempotencyKey{
+		Key:          key.String,
+		ActorId:      actorId.String,
+		RequestHash:  requestHash.String,
+		ResponseCode: int(responseCode.Int32),
+		ResponseType: responseType.String,
+		ResponseBody: responseBody,
+		CreatedAt:    createdAt.String,
+		ExpiresAt:    expiresAt.String,
+	}, nil
+}
+
+// Save stores the response of the request holding the key.
+func (r *IdempotencyKeyRepo) Save(ctx context.Context, req *models.SaveIdempotencyKey) error {
+
+	query := `
+		UPDATE
+			idempotency_key
+		SET
+			response_code = $3,
+			response_type = $4,
+			response_body = $5
+		WHERE key = $1 AND actor_id = $2
+	`
+
+	_, err := r.db.Exec(ctx, query, req.Key, req.ActorId, req.ResponseCode, req.ResponseType, req.ResponseBody)
+
+	return mapError(err)
+}
+
+// Release frees a key whose request got no response worth replaying, so it
+// can be retried.
+func (r *IdempotencyKeyRepo) Release(ctx context.Context, req *models.IdempotencyKeyPrimaryKey) error {
+
+	_, err := r.db.Exec(ctx, "DELETE FROM idempotency_key WHERE key = $1 AND actor_id = $2 AND response_code IS NULL", req.Key, req.ActorId)
+
+	return mapError(err)
+}
+
+// Purge removes expired keys.
+func (r *IdempotencyKeyRepo) Purge(ctx context.Context) (int64, error) {
+
+	result, err := r.db.Exec(ctx, "DELETE FROM idempotency_key WHERE expires_at <= NOW()")
+	if err != nil {
+		return 0, mapError(err)
+	}
+
+	return result.RowsAffected(), nil
+}
@@ -15,8 +15,19 @@ type store struct {
 	category               *CategoryRepo
 	branch                 *BranchRepo
 	product                *ProductRepo
+	product_barcode        *ProductBarcodeRepo
+	product_image          *ProductImageRepo
 	storage_coming         *StorageComingRepo
 	storage_coming_product *StorageComingProductRepo
+	remaining              *RemainingRepo
+	stock_level            *StockLevelRepo
+	serial_number          *SerialNumberRepo
+	user                   *UserRepo
+	session                *SessionRepo
+	api_key                *ApiKeyRepo
+	audit_log              *AuditLogRepo
+	idempotency_key        *IdempotencyKeyRepo
+	report                 *ReportRepo
 }
 
 func NewConnectionPostgres(cfg *config.Config) (storage.StorageI, error) {
@@ -76,6 +87,24 @@ func (s *store) Product() storage.ProductRepoI {
 	return s.product
 }
 
+func (s *store) ProductBarcode() storage.ProductBarcodeRepoI {
+
+	if s.product_barcode == nil {
+		s.product_barcode = NewProductBarcodeRepo(s.db)
+	}
+
+	return s.product_barcode
+}
+
+func (s *store) ProductImage() storage.ProductImageRepoI {
+
+	if s.product_image == nil {
+		s.product_image = NewProductImageRepo(s.db)
+	}
+
+	return s.product_image
+}
+
 func (s *store) StorageComing() storage.StorageComingRepoI {
 
 	if s.storage_coming == nil {
@@ -93,3 +122,84 @@ func (s *store) StorageComingProduct() storage.StorageComingProductRepoI {
 
 	return s.storage_coming_product
 }
+
+func (s *store) Remaining() storage.RemainingRepoI {
+
+	if s.remaining == nil {
+		s.remaining = NewRemainingRepo(s.db)
+	}
+
+	return s.remaining
+}
+
+func (s *store) StockLevel() storage.StockLevelRepoI {
+
+	if s.stock_level == nil {
+		s.stock_level = NewStockLevelRepo(s.db)
+	}
+
+	return s.stock_level
+}
+
+func (s *store) SerialNumber() storage.SerialNumberRepoI {
+
+	if s.serial_number == nil {
+		s.serial_number = NewSerialNumberRepo(s.db)
+	}
+
+	return s.serial_number
+}
+
+func (s *store) User() storage.UserRepoI {
+
+	if s.user == nil {
+		s.user = NewUserRepo(s.db)
+	}
+
+	return s.user
+}
+
+func (s *store) Session() storage.SessionRepoI {
+
+	if s.session == nil {
+		s.session = NewSessionRepo(s.db)
+	}
+
+	return s.session
+}
+
+func (s *store) ApiKey() storage.ApiKeyRepoI {
+
+	if s.api_key == nil {
+		s.api_key = NewApiKeyRepo(s.db)
+	}
+
+	return s.api_key
+}
+
+func (s *store) AuditLog() storage.AuditLogRepoI {
+
+	if s.audit_log == nil {
+		s.audit_log = NewAuditLogRepo(s.db)
+	}
+
+	return s.audit_log
+}
+
+func (s *store) IdempotencyKey() storage.IdempotencyKeyRepoI {
+
+	if s.idempotency_key == nil {
+		s.idempotency_key = NewIdempotencyKeyRepo(s.db)
+	}
+
+	return s.idempotency_key
+}
+
+func (s *store) Report() storage.ReportRepoI {
+
+	if s.report == nil {
+		s.report = NewReportRepo(s.db)
+	}
+
+	return s.report
+}
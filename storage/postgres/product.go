@@ -5,11 +5,14 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"sort"
 
 	uuid "github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
 	"github.com/jackc/pgx/v4/pgxpool"
 
 	"market/api/models"
+	"market/pkg/apperr"
 	"market/pkg/helper"
 )
 
@@ -30,21 +33,61 @@ func (r *ProductRepo) Create(ctx context.Context, req *models.CreateProduct) (st
 		query string
 	)
 
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return "", mapError(err)
+	}
+	defer tx.Rollback(ctx)
+
+	err = checkParent(ctx, tx, id, req.ParentId)
+	if err != nil {
+		return "", mapError(err)
+	}
+
 	query = `
-		INSERT INTO product(id, name, barcode, price, category_id, updated_at)
-		VALUES ($1, $2, $3, $4, $5, NOW())
+		INSERT INTO product(id, name, barcode, price, category_id, parent_id, size, color, volume, serialized, updated_at)
+		SELECT $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, NOW()
+		WHERE NOT EXISTS (SELECT 1 FROM product_barcode WHERE barcode = $3 AND deleted_at IS NULL)
 	`
 
-	_, err := r.db.Exec(ctx, query,
+	result, err := tx.Exec(ctx, query,
 		id,
 		req.Name,
 		req.Barcode,
 		req.Price,
 		helper.NewNullString(req.CategoryId),
+		helper.NewNullString(req.ParentId),
+		helper.NewNullString(req.Size),
+		helper.NewNullString(req.Color),
+		helper.NewNullString(req.Volume),
+		req.Serialized,
 	)
 
 	if err != nil {
-		return "", err
+		return "", mapError(err)
+	}
+
+	if result.RowsAffected() <= 0 {
+		return "", apperr.Conflict("barcode " + req.Barcode + " is already in use")
+	}
+
+	for _, barcode := range req.Barcodes {
+		barcode.ProductId = id
+
+		_, err = createProductBarcode(ctx, tx, barcode)
+		if err != nil {
+			return "", mapError(err)
+		}
+	}
+
+	err = recordChange(ctx, tx, "product", "product", id, nil)
+	if err != nil {
+		return "", mapError(err)
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return "", mapError(err)
 	}
 
 	return id, nil
@@ -60,8 +103,15 @@ func (r *ProductRepo) GetByID(ctx context.Context, req *models.ProductPrimaryKey
 		barcode    sql.NullString
 		price      sql.NullInt32
 		categoryId sql.NullString
+		parentId   sql.NullString
+		size       sql.NullString
+		color      sql.NullString
+		volume     sql.NullString
+		serialized sql.NullBool
+		version    sql.NullInt32
 		createdAt  sql.NullString
 		updatedAt  sql.NullString
+		deletedAt  sql.NullString
 	)
 
 	query = `
@@ -71,34 +121,120 @@ func (r *ProductRepo) GetByID(ctx context.Context, req *models.ProductPrimaryKey
 			barcode,
 			price,
 			category_id,
+			parent_id,
+			size,
+			color,
+			volume,
+			serialized,
+			version,
 			created_at,
-			updated_at
+			updated_at,
+			deleted_at
 		FROM product
-		WHERE id = $1
+		WHERE id = $1 AND (deleted_at IS NULL OR $2)
 	`
 
-	err := r.db.QueryRow(ctx, query, req.Id).Scan(
+	err := r.db.QueryRow(ctx, query, req.Id, req.IncludeDeleted).Scan(
 		&id,
 		&name,
 		&barcode,
 		&price,
 		&categoryId,
+		&parentId,
+		&size,
+		&color,
+		&volume,
+		&serialized,
+		&version,
 		&createdAt,
 		&updatedAt,
+		&deletedAt,
 	)
 
 	if err != nil {
-		return nil, err
+		return nil, mapError(err)
 	}
 
-	return &models.Product{
+	product := &models.Product{
 		Id:         id.String,
 		Name:       name.String,
 		Barcode:    barcode.String,
 		Price:      price.Int32,
 		CategoryId: categoryId.String,
+		ParentId:   parentId.String,
+		Size:       size.String,
+		Color:      color.String,
+		Volume:     volume.String,
+		Serialized: serialized.Bool,
+		Version:    int(version.Int32),
 		CreatedAt:  createdAt.String,
 		UpdatedAt:  updatedAt.String,
+		DeletedAt:  deletedAt.String,
+	}
+
+	if !parentId.Valid {
+		variants, err := r.getVariants(ctx, []string{product.Id})
+		if err != nil {
+			return nil, mapError(err)
+		}
+
+		product.Variants = variants[product.Id]
+	}
+
+	barcodes, err := NewProductBarcodeRepo(r.db).GetList(ctx, &models.ProductBarcodeGetListRequest{ProductId: product.Id})
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	product.Barcodes = barcodes.Barcodes
+
+	err = r.attachImages(ctx, append([]*models.Product{product}, product.Variants...))
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	return product, nil
+}
+
+// GetByBarcode resolves a primary or additional barcode to its product together
+// with the pack quantity the scanned code stands for.
+func (r *ProductRepo) GetByBarcode(ctx context.Context, req *models.ProductBarcodeLookup) (*models.ProductBarcodeLookupResponse, error) {
+
+	var (
+		query string
+
+		productId sql.NullString
+		codeType  sql.NullString
+		quantity  sql.NullInt32
+	)
+
+	query = `
+		SELECT id, 'manufacturer', 1 FROM product WHERE barcode = $1 AND deleted_at IS NULL
+		UNION ALL
+		SELECT product_id, type, quantity FROM product_barcode
+		WHERE barcode = $1 AND product_id IN (SELECT id FROM product WHERE deleted_at IS NULL)
+		LIMIT 1
+	`
+
+	err := r.db.QueryRow(ctx, query, req.Barcode).Scan(
+		&productId,
+		&codeType,
+		&quantity,
+	)
+
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	product, err := r.GetByID(ctx, &models.ProductPrimaryKey{Id: productId.String})
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	return &models.ProductBarcodeLookupResponse{
+		Product:  product,
+		Type:     codeType.String,
+		Quantity: quantity.Int32,
 	}, nil
 }
 
@@ -108,8 +244,10 @@ func (r *ProductRepo) GetList(ctx context.Context, req *models.ProductGetListReq
 		resp   = &models.ProductGetListResponse{}
 		query  string
 		where  = " WHERE TRUE"
+		order  = " ORDER BY created_at, id"
 		offset = " OFFSET 0"
 		limit  = " LIMIT 10"
+		params = map[string]interface{}{}
 	)
 
 	query = `
@@ -120,8 +258,15 @@ func (r *ProductRepo) GetList(ctx context.Context, req *models.ProductGetListReq
 			barcode,
 			price,
 			category_id,
+			parent_id,
+			size,
+			color,
+			volume,
+			serialized,
+			version,
 			created_at,
-			updated_at
+			updated_at,
+			deleted_at
 		FROM product
 	`
 
@@ -134,15 +279,35 @@ func (r *ProductRepo) GetList(ctx context.Context, req *models.ProductGetListReq
 	}
 
 	if req.Search != "" {
-		where += ` AND title ILIKE '%' || '` + req.Search + `' || '%'`
+		where += ` AND name ILIKE '%' || :search || '%'`
+		params["search"] = req.Search
+	}
+
+	if req.CategoryId != "" {
+		where += " AND category_id = :category_id"
+		params["category_id"] = req.CategoryId
+	}
+
+	if req.ParentId != "" {
+		where += " AND parent_id = :parent_id"
+		params["parent_id"] = req.ParentId
+	} else if req.GroupVariants {
+		where += " AND parent_id IS NULL"
+	}
+
+	if !req.IncludeDeleted {
+		where += " AND deleted_at IS NULL"
 	}
 
-	query += where + offset + limit
+	query += where + order + offset + limit
+
+	query, args := helper.ReplaceQueryParams(query, params)
 
-	rows, err := r.db.Query(ctx, query)
+	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
-		return nil, err
+		return nil, mapError(err)
 	}
+	defer rows.Close()
 
 	for rows.Next() {
 		var (
@@ -151,8 +316,15 @@ func (r *ProductRepo) GetList(ctx context.Context, req *models.ProductGetListReq
 			barcode    sql.NullString
 			price      sql.NullInt32
 			categoryId sql.NullString
+			parentId   sql.NullString
+			size       sql.NullString
+			color      sql.NullString
+			volume     sql.NullString
+			serialized sql.NullBool
+			version    sql.NullInt32
 			createdAt  sql.NullString
 			updatedAt  sql.NullString
+			deletedAt  sql.NullString
 		)
 
 		err := rows.Scan(
@@ -162,12 +334,19 @@ func (r *ProductRepo) GetList(ctx context.Context, req *models.ProductGetListReq
 			&barcode,
 			&price,
 			&categoryId,
+			&parentId,
+			&size,
+			&color,
+			&volume,
+			&serialized,
+			&version,
 			&createdAt,
 			&updatedAt,
+			&deletedAt,
 		)
 
 		if err != nil {
-			return nil, err
+			return nil, mapError(err)
 		}
 
 		resp.Products = append(resp.Products, &models.Product{
@@ -176,14 +355,166 @@ func (r *ProductRepo) GetList(ctx context.Context, req *models.ProductGetListReq
 			Barcode:    barcode.String,
 			Price:      price.Int32,
 			CategoryId: categoryId.String,
+			ParentId:   parentId.String,
+			Size:       size.String,
+			Color:      color.String,
+			Volume:     volume.String,
+			Serialized: serialized.Bool,
+			Version:    int(version.Int32),
 			CreatedAt:  createdAt.String,
 			UpdatedAt:  updatedAt.String,
+			DeletedAt:  deletedAt.String,
 		})
 	}
 
+	if req.GroupVariants && req.ParentId == "" && len(resp.Products) > 0 {
+		var parentIds = make([]string, 0, len(resp.Products))
+		for _, product := range resp.Products {
+			parentIds = append(parentIds, product.Id)
+		}
+
+		variants, err := r.getVariants(ctx, parentIds)
+		if err != nil {
+			return nil, mapError(err)
+		}
+
+		for _, product := range resp.Products {
+			product.Variants = variants[product.Id]
+		}
+	}
+
+	var products = append([]*models.Product{}, resp.Products...)
+	for _, product := range resp.Products {
+		products = append(products, product.Variants...)
+	}
+
+	err = r.attachImages(ctx, products)
+	if err != nil {
+		return nil, mapError(err)
+	}
+
 	return resp, nil
 }
 
+// attachImages loads the ordered images of all given products in one query.
+func (r *ProductRepo) attachImages(ctx context.Context, products []*models.Product) error {
+
+	if len(products) <= 0 {
+		return nil
+	}
+
+	var (
+		ids    = make([]string, 0, len(products))
+		images = map[string][]*models.ProductImage{}
+	)
+
+	for _, product := range products {
+		ids = append(ids, product.Id)
+	}
+
+	resp, err := NewProductImageRepo(r.db).GetList(ctx, &models.ProductImageGetListRequest{ProductIds: ids})
+	if err != nil {
+		return mapError(err)
+	}
+
+	for _, image := range resp.Images {
+		images[image.ProductId] = append(images[image.ProductId], image)
+	}
+
+	for _, product := range products {
+		product.Images = images[product.Id]
+	}
+
+	return nil
+}
+
+// getVariants returns the variants of the given parent products keyed by parent id.
+func (r *ProductRepo) getVariants(ctx context.Context, parentIds []string) (map[string][]*models.Product, error) {
+
+	var (
+		resp  = map[string][]*models.Product{}
+		query string
+	)
+
+	query = `
+		SELECT
+			id,
+			name,
+			barcode,
+			price,
+			category_id,
+			parent_id,
+			size,
+			color,
+			volume,
+			serialized,
+			created_at,
+			updated_at
+		FROM product
+		WHERE parent_id = ANY($1::UUID[]) AND deleted_at IS NULL
+		ORDER BY created_at
+	`
+
+	rows, err := r.db.Query(ctx, query, parentIds)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			id         sql.NullString
+			name       sql.NullString
+			barcode    sql.NullString
+			price      sql.NullInt32
+			categoryId sql.NullString
+			parentId   sql.NullString
+			size       sql.NullString
+			color      sql.NullString
+			volume     sql.NullString
+			serialized sql.NullBool
+			createdAt  sql.NullString
+			updatedAt  sql.NullString
+		)
+
+		err := rows.Scan(
+			&id,
+			&name,
+			&barcode,
+			&price,
+			&categoryId,
+			&parentId,
+			&size,
+			&color,
+			&volume,
+			&serialized,
+			&createdAt,
+			&updatedAt,
+		)
+
+		if err != nil {
+			return nil, mapError(err)
+		}
+
+		resp[parentId.String] = append(resp[parentId.String], &models.Product{
+			Id:         id.String,
+			Name:       name.String,
+			Barcode:    barcode.String,
+			Price:      price.Int32,
+			CategoryId: categoryId.String,
+			ParentId:   parentId.String,
+			Size:       size.String,
+			Color:      color.String,
+			Volume:     volume.String,
+			Serialized: serialized.Bool,
+			CreatedAt:  createdAt.String,
+			UpdatedAt:  updatedAt.String,
+		})
+	}
+
+	return resp, rows.Err()
+}
+
 func (r *ProductRepo) Update(ctx context.Context, req *models.UpdateProduct) (int64, error) {
 
 	var (
@@ -199,8 +530,14 @@ func (r *ProductRepo) Update(ctx context.Context, req *models.UpdateProduct) (in
 			barcode = :barcode,
 			price = :price,
 			category_id = :category_id,
+			parent_id = :parent_id,
+			size = :size,
+			color = :color,
+			volume = :volume,
+			serialized = :serialized,
+			version = version + 1,
 			updated_at = NOW()
-		WHERE id = :id
+		WHERE id = :id AND deleted_at IS NULL
 	`
 
 	params = map[string]interface{}{
@@ -209,18 +546,89 @@ func (r *ProductRepo) Update(ctx context.Context, req *models.UpdateProduct) (in
 		"barcode":     req.Barcode,
 		"price":       req.Price,
 		"category_id": helper.NewNullString(req.CategoryId),
+		"parent_id":   helper.NewNullString(req.ParentId),
+		"size":        helper.NewNullString(req.Size),
+		"color":       helper.NewNullString(req.Color),
+		"volume":      helper.NewNullString(req.Volume),
+		"serialized":  req.Serialized,
 	}
 
 	query, args := helper.ReplaceQueryParams(query, params)
 
-	result, err := r.db.Exec(ctx, query, args...)
+	return r.update(ctx, req.Id, req.Version, req.Barcode, &req.ParentId, query, args)
+}
+
+// update runs an UPDATE of the product with id and records the change. A
+// version other than zero must match the current one, a barcode other than
+// empty must not belong to another product and a parentId that is set must
+// be a fit parent.
+func (r *ProductRepo) update(ctx context.Context, id string, version int, barcode string, parentId *string, query string, args []interface{}) (int64, error) {
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, mapError(err)
+	}
+	defer tx.Rollback(ctx)
+
+	before, err := snapshot(ctx, tx, "product", id)
+	if err != nil {
+		return 0, mapError(err)
+	}
+
+	err = checkVersion(before, version)
+	if err != nil {
+		return 0, mapError(err)
+	}
+
+	if barcode != "" {
+		err = checkBarcodeFree(ctx, tx, id, barcode)
+		if err != nil {
+			return 0, mapError(err)
+		}
+	}
+
+	if parentId != nil {
+		err = checkParent(ctx, tx, id, *parentId)
+		if err != nil {
+			return 0, mapError(err)
+		}
+	}
+
+	result, err := tx.Exec(ctx, query, args...)
 	if err != nil {
-		return 0, err
+		return 0, mapError(err)
+	}
+
+	if result.RowsAffected() <= 0 {
+		return 0, mapError(pgx.ErrNoRows)
+	}
+
+	err = recordChange(ctx, tx, "product", "product", id, before)
+	if err != nil {
+		return 0, mapError(err)
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return 0, mapError(err)
 	}
 
 	return result.RowsAffected(), nil
 }
 
+// productPatchFields are the columns a patch may set.
+var productPatchFields = map[string]bool{
+	"name":        true,
+	"barcode":     true,
+	"price":       true,
+	"category_id": true,
+	"parent_id":   true,
+	"size":        true,
+	"color":       true,
+	"volume":      true,
+	"serialized":  true,
+}
+
 func (r *ProductRepo) Patch(ctx context.Context, req *models.PatchRequest) (int64, error) {
 
 	var (
@@ -229,39 +637,206 @@ func (r *ProductRepo) Patch(ctx context.Context, req *models.PatchRequest) (int6
 	)
 
 	if len(req.Fields) <= 0 {
-		return 0, errors.New("no fields")
+		return 0, apperr.Validation("no fields to update")
 	}
 
+	var (
+		keys   []string
+		fields []apperr.FieldError
+	)
+
 	for key := range req.Fields {
+		if !productPatchFields[key] {
+			fields = append(fields, apperr.FieldError{Field: key, Message: "cannot be patched"})
+			continue
+		}
+
+		keys = append(keys, key)
+	}
+
+	if len(fields) > 0 {
+		return 0, apperr.Validation("invalid fields", fields...)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
 		set += fmt.Sprintf(" %s = :%s, ", key, key)
 	}
 
 	query = `
 		UPDATE
 			product
-		SET ` + set + ` updated_at = now()
-		WHERE id = :id
+		SET ` + set + ` version = version + 1, updated_at = now()
+		WHERE id = :id AND deleted_at IS NULL
 	`
 
-	req.Fields["id"] = req.ID
+	var (
+		barcode, _ = req.Fields["barcode"].(string)
+		parentId   *string
+	)
+
+	if value, ok := req.Fields["parent_id"]; ok {
+		id, _ := value.(string)
+		parentId = &id
+	}
 
-	fmt.Println(query)
+	req.Fields["id"] = req.ID
 
 	query, args := helper.ReplaceQueryParams(query, req.Fields)
-	result, err := r.db.Exec(ctx, query, args...)
+
+	return r.update(ctx, req.ID, req.Version, barcode, parentId, query, args)
+}
+
+// checkParent fails unless parentId is empty or a live product that is not a
+// variant itself, other than productId. Variants are one level deep, so a
+// product with variants of its own cannot become one either.
+func checkParent(ctx context.Context, tx pgx.Tx, productId, parentId string) error {
+
+	if parentId == "" {
+		return nil
+	}
+
+	if parentId == productId {
+		return apperr.Validation("invalid parent", apperr.FieldError{Field: "parent_id", Message: "cannot be the product itself"})
+	}
+
+	var grandParentId sql.NullString
+
+	err := tx.QueryRow(ctx, "SELECT parent_id FROM product WHERE id = $1 AND deleted_at IS NULL FOR SHARE", parentId).Scan(&grandParentId)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return apperr.Validation("invalid parent", apperr.FieldError{Field: "parent_id", Message: parentId + " does not exist"})
+	} else if err != nil {
+		return err
+	}
+
+	if grandParentId.Valid {
+		return apperr.Validation("invalid parent", apperr.FieldError{Field: "parent_id", Message: "is a variant itself, variants cannot have variants"})
+	}
+
+	var hasVariants bool
+
+	err = tx.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM product WHERE parent_id = $1 AND deleted_at IS NULL)", productId).Scan(&hasVariants)
 	if err != nil {
-		return 0, err
+		return err
 	}
 
-	return result.RowsAffected(), nil
+	if hasVariants {
+		return apperr.Validation("invalid parent", apperr.FieldError{Field: "parent_id", Message: "product has variants and cannot become one"})
+	}
+
+	return nil
 }
 
-func (r *ProductRepo) Delete(ctx context.Context, req *models.ProductPrimaryKey) error {
+// checkBarcodeFree returns a conflict when barcode is the code of a product
+// other than productId or an extra code of any product.
+func checkBarcodeFree(ctx context.Context, tx pgx.Tx, productId, barcode string) error {
 
-	_, err := r.db.Exec(ctx, "DELETE FROM product WHERE id = $1", req.Id)
+	var taken bool
+
+	err := tx.QueryRow(ctx, `
+		SELECT
+			EXISTS (SELECT 1 FROM product WHERE barcode = $2 AND id <> $1 AND deleted_at IS NULL) OR
+			EXISTS (SELECT 1 FROM product_barcode WHERE barcode = $2 AND deleted_at IS NULL)
+	`, productId, barcode).Scan(&taken)
+	if err != nil {
+		return err
+	}
+
+	if taken {
+		return apperr.Conflict("barcode " + barcode + " is already in use")
+	}
+
+	return nil
+}
+
+// checkRestoredBarcodes fails when a barcode of the restored product with id
+// was taken by another product while it was deleted. Two main barcodes or two
+// additional ones clash on their unique indexes, this catches a main barcode
+// against an additional one.
+func checkRestoredBarcodes(ctx context.Context, tx pgx.Tx, id string) error {
+
+	var taken bool
+
+	err := tx.QueryRow(ctx, `
+		SELECT
+			EXISTS (
+				SELECT 1
+				FROM product AS p
+				WHERE p.id <> $1 AND p.deleted_at IS NULL AND
+					p.barcode IN (SELECT barcode FROM product_barcode WHERE product_id = $1)
+			) OR
+			EXISTS (
+				SELECT 1
+				FROM product_barcode AS pb
+				WHERE pb.product_id <> $1 AND pb.deleted_at IS NULL AND
+					pb.barcode = (SELECT barcode FROM product WHERE id = $1)
+			)
+	`, id).Scan(&taken)
 	if err != nil {
 		return err
 	}
 
+	if taken {
+		return apperr.Conflict("a barcode of the product is in use by another product")
+	}
+
 	return nil
 }
+
+func (r *ProductRepo) Delete(ctx context.Context, req *models.ProductPrimaryKey) error {
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return mapError(err)
+	}
+	defer tx.Rollback(ctx)
+
+	err = softDelete(ctx, tx, "product", "product", req.Id)
+	if err != nil {
+		return mapError(err)
+	}
+
+	_, err = tx.Exec(ctx, "UPDATE product_barcode SET deleted_at = NOW() WHERE product_id = $1", req.Id)
+	if err != nil {
+		return mapError(err)
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *ProductRepo) Restore(ctx context.Context, req *models.ProductPrimaryKey) (int64, error) {
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, mapError(err)
+	}
+	defer tx.Rollback(ctx)
+
+	rowsAffected, err := restore(ctx, tx, "product", "product", req.Id)
+	if err != nil {
+		return 0, mapError(err)
+	}
+
+	err = checkRestoredBarcodes(ctx, tx, req.Id)
+	if err != nil {
+		return 0, mapError(err)
+	}
+
+	_, err = tx.Exec(ctx, "UPDATE product_barcode SET deleted_at = NULL WHERE product_id = $1", req.Id)
+	if err != nil {
+		return 0, mapError(err)
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return 0, mapError(err)
+	}
+
+	return rowsAffected, nil
+}
+
+// Purge removes products deleted more than days ago.
+func (r *ProductRepo) Purge(ctx context.Context, days int) (int64, error) {
+	return purgeDeleted(ctx, r.db, "product", "product", days)
+}
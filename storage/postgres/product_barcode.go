@@ -0,0 +1,270 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	uuid "github.com/google/uuid"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4/pgxpool"
+
+	"market/api/models"
+	"market/pkg/apperr"
+	"market/pkg/barcode"
+)
+
+// internalBarcodeItems is the number of item references behind one prefix.
+const internalBarcodeItems = 1000000000
+
+// execer is satisfied by both the pool and a transaction.
+type execer interface {
+	Exec(ctx context.Context, sql string, arguments ...interface{}) (pgconn.CommandTag, error)
+}
+
+type ProductBarcodeRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewProductBarcodeRepo(db *pgxpool.Pool) *ProductBarcodeRepo {
+	return &ProductBarcodeRepo{
+		db: db,
+	}
+}
+
+func (r *ProductBarcodeRepo) Create(ctx context.Context, req *models.CreateProductBarcode) (string, error) {
+	return createProductBarcode(ctx, r.db, req)
+}
+
+// createProductBarcode inserts an additional barcode, refusing codes that are
+// already used as the primary barcode of some product.
+func createProductBarcode(ctx context.Context, db execer, req *models.CreateProductBarcode) (string, error) {
+
+	var (
+		id       = uuid.New().String()
+		query    string
+		codeType = req.Type
+		quantity = req.Quantity
+	)
+
+	if codeType == "" {
+		codeType = models.ProductBarcodeTypeManufacturer
+	}
+
+	if quantity <= 0 {
+		quantity = 1
+	}
+
+	query = `
+		INSERT INTO product_barcode(id, product_id, barcode, type, quantity, updated_at)
+		SELECT $1, $2, $3, $4, $5, NOW()
+		WHERE NOT EXISTS (SELECT 1 FROM product WHERE barcode = $3 AND deleted_at IS NULL)
+	`
+
+	result, err := db.Exec(ctx, query,
+		id,
+		req.ProductId,
+		req.Barcode,
+		codeType,
+		quantity,
+	)
+
+	if err != nil {
+		return "", err
+	}
+
+	if result.RowsAffected() <= 0 {
+		return "", apperr.Conflict("barcode " + req.Barcode + " is already in use")
+	}
+
+	return id, nil
+}
+
+func (r *ProductBarcodeRepo) GetByID(ctx context.Context, req *models.ProductBarcodePrimaryKey) (*models.ProductBarcode, error) {
+
+	var (
+		query string
+
+		id        sql.NullString
+		productId sql.NullString
+		barcode   sql.NullString
+		codeType  sql.NullString
+		quantity  sql.NullInt32
+		createdAt sql.NullString
+		updatedAt sql.NullString
+	)
+
+	query = `
+		SELECT
+			id,
+			product_id,
+			barcode,
+			type,
+			quantity,
+			created_at,
+			updated_at
+		FROM product_barcode
+		WHERE id = $1
+	`
+
+	err := r.db.QueryRow(ctx, query, req.Id).Scan(
+		&id,
+		&productId,
+		&barcode,
+		&codeType,
+		&quantity,
+		&createdAt,
+		&updatedAt,
+	)
+
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	return &models.ProductBarcode{
+		Id:        id.String,
+		ProductId: productId.String,
+		Barcode:   barcode.String,
+		Type:      codeType.String,
+		Quantity:  quantity.Int32,
+		CreatedAt: createdAt.String,
+		UpdatedAt: updatedAt.String,
+	}, nil
+}
+
+func (r *ProductBarcodeRepo) GetList(ctx context.Context, req *models.ProductBarcodeGetListRequest) (*models.ProductBarcodeGetListResponse, error) {
+
+	var (
+		resp  = &models.ProductBarcodeGetListResponse{}
+		query string
+	)
+
+	query = `
+		SELECT
+			id,
+			product_id,
+			barcode,
+			type,
+			quantity,
+			created_at,
+			updated_at
+		FROM product_barcode
+		WHERE product_id = $1
+		ORDER BY created_at
+	`
+
+	rows, err := r.db.Query(ctx, query, req.ProductId)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			id        sql.NullString
+			productId sql.NullString
+			barcode   sql.NullString
+			codeType  sql.NullString
+			quantity  sql.NullInt32
+			createdAt sql.NullString
+			updatedAt sql.NullString
+		)
+
+		err := rows.Scan(
+			&id,
+			&productId,
+			&barcode,
+			&codeType,
+			&quantity,
+			&createdAt,
+			&updatedAt,
+		)
+
+		if err != nil {
+			return nil, mapError(err)
+		}
+
+		resp.Barcodes = append(resp.Barcodes, &models.ProductBarcode{
+			Id:        id.String,
+			ProductId: productId.String,
+			Barcode:   barcode.String,
+			Type:      codeType.String,
+			Quantity:  quantity.Int32,
+			CreatedAt: createdAt.String,
+			UpdatedAt: updatedAt.String,
+		})
+	}
+
+	resp.Count = len(resp.Barcodes)
+
+	return resp, rows.Err()
+}
+
+// Generate issues the next unused internal EAN-13. Regular items take codes
+// from the configured in-store prefix range, weighed items get a scale base
+// code with a zero value part.
+func (r *ProductBarcodeRepo) Generate(ctx context.Context, req *models.GenerateBarcode) (string, error) {
+
+	for {
+		var (
+			next int64
+			code string
+			used bool
+			err  error
+		)
+
+		if req.Weighed {
+			err = r.db.QueryRow(ctx, "SELECT nextval('weighed_barcode_seq')").Scan(&next)
+
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == sqlStateSequenceLimit {
+				return "", apperr.Wrap(apperr.Conflict("weighed barcode range is exhausted"), err)
+			} else if err != nil {
+				return "", mapError(err)
+			}
+
+			code, err = barcode.GenerateWeighed(req.WeightPrefix, next)
+		} else {
+			err = r.db.QueryRow(ctx, "SELECT nextval('internal_barcode_seq')").Scan(&next)
+			if err != nil {
+				return "", mapError(err)
+			}
+
+			prefix := req.PrefixFrom + int(next/internalBarcodeItems)
+			if prefix > req.PrefixTo {
+				return "", apperr.Conflict("internal barcode range is exhausted")
+			}
+
+			code, err = barcode.GenerateEAN13(prefix, next%internalBarcodeItems)
+		}
+
+		if err != nil {
+			return "", mapError(err)
+		}
+
+		query := `
+			SELECT
+				EXISTS (SELECT 1 FROM product WHERE barcode = $1 AND deleted_at IS NULL) OR
+				EXISTS (SELECT 1 FROM product_barcode WHERE barcode = $1 AND deleted_at IS NULL)
+		`
+
+		err = r.db.QueryRow(ctx, query, code).Scan(&used)
+		if err != nil {
+			return "", mapError(err)
+		}
+
+		if !used {
+			return code, nil
+		}
+	}
+}
+
+func (r *ProductBarcodeRepo) Delete(ctx context.Context, req *models.ProductBarcodePrimaryKey) error {
+
+	_, err := r.db.Exec(ctx, "DELETE FROM product_barcode WHERE id = $1", req.Id)
+	if err != nil {
+		return mapError(err)
+	}
+
+	return nil
+}
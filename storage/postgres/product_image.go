@@ -0,0 +1,232 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	uuid "github.com/google/uuid"
+	"github.com/jackc/pgx/v4/pgxpool"
+
+	"market/api/models"
+)
+
+type ProductImageRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewProductImageRepo(db *pgxpool.Pool) *ProductImageRepo {
+	return &ProductImageRepo{
+		db: db,
+	}
+}
+
+func (r *ProductImageRepo) Create(ctx context.Context, req *models.CreateProductImage) (string, error) {
+
+	var (
+		id    = uuid.New().String()
+		query string
+	)
+
+	query = `
+		INSERT INTO product_image(id, product_id, file_key, thumbnail_key, content_type, size, position, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6,
+			(SELECT COALESCE(MAX(position), 0) + 1 FROM product_image WHERE product_id = $2),
+			NOW())
+	`
+
+	_, err := r.db.Exec(ctx, query,
+		id,
+		req.ProductId,
+		req.FileKey,
+		req.ThumbnailKey,
+		req.ContentType,
+		req.Size,
+	)
+
+	if err != nil {
+		return "", mapError(err)
+	}
+
+	return id, nil
+}
+
+func (r *ProductImageRepo) GetByID(ctx context.Context, req *models.ProductImagePrimaryKey) (*models.ProductImage, error) {
+
+	var (
+		query string
+
+		id           sql.NullString
+		productId    sql.NullString
+		fileKey      sql.NullString
+		thumbnailKey sql.NullString
+		contentType  sql.NullString
+		size         sql.NullInt64
+		position     sql.NullInt32
+		createdAt    sql.NullString
+		updatedAt    sql.NullString
+	)
+
+	query = `
+		SELECT
+			id,
+			product_id,
+			file_key,
+			thumbnail_key,
+			content_type,
+			size,
+			position,
+			created_at,
+			updated_at
+		FROM product_image
+		WHERE id = $1
+	`
+
+	err := r.db.QueryRow(ctx, query, req.Id).Scan(
+		&id,
+		&productId,
+		&fileKey,
+		&thumbnailKey,
+		&contentType,
+		&size,
+		&position,
+		&createdAt,
+		&updatedAt,
+	)
+
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	return &models.ProductImage{
+		Id:           id.String,
+		ProductId:    productId.String,
+		FileKey:      fileKey.String,
+		ThumbnailKey: thumbnailKey.String,
+		ContentType:  contentType.String,
+		Size:         size.Int64,
+		Position:     position.Int32,
+		CreatedAt:    createdAt.String,
+		UpdatedAt:    updatedAt.String,
+	}, nil
+}
+
+func (r *ProductImageRepo) GetList(ctx context.Context, req *models.ProductImageGetListRequest) (*models.ProductImageGetListResponse, error) {
+
+	var (
+		resp  = &models.ProductImageGetListResponse{}
+		query string
+	)
+
+	query = `
+		SELECT
+			id,
+			product_id,
+			file_key,
+			thumbnail_key,
+			content_type,
+			size,
+			position,
+			created_at,
+			updated_at
+		FROM product_image
+		WHERE product_id = ANY($1::UUID[])
+		ORDER BY product_id, position
+	`
+
+	rows, err := r.db.Query(ctx, query, req.ProductIds)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			id           sql.NullString
+			productId    sql.NullString
+			fileKey      sql.NullString
+			thumbnailKey sql.NullString
+			contentType  sql.NullString
+			size         sql.NullInt64
+			position     sql.NullInt32
+			createdAt    sql.NullString
+			updatedAt    sql.NullString
+		)
+
+		err := rows.Scan(
+			&id,
+			&productId,
+			&fileKey,
+			&thumbnailKey,
+			&contentType,
+			&size,
+			&position,
+			&createdAt,
+			&updatedAt,
+		)
+
+		if err != nil {
+			return nil, mapError(err)
+		}
+
+		resp.Images = append(resp.Images, &models.ProductImage{
+			Id:           id.String,
+			ProductId:    productId.String,
+			FileKey:      fileKey.String,
+			ThumbnailKey: thumbnailKey.String,
+			ContentType:  contentType.String,
+			Size:         size.Int64,
+			Position:     position.Int32,
+			CreatedAt:    createdAt.String,
+			UpdatedAt:    updatedAt.String,
+		})
+	}
+
+	resp.Count = len(resp.Images)
+
+	return resp, rows.Err()
+}
+
+// Reorder sets the image positions of a product to the order of req.ImageIds.
+func (r *ProductImageRepo) Reorder(ctx context.Context, req *models.ProductImageOrder) (int64, error) {
+
+	var (
+		query = `
+			UPDATE product_image
+			SET position = $1, updated_at = NOW()
+			WHERE id = $2 AND product_id = $3
+		`
+		rowsAffected int64
+	)
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, mapError(err)
+	}
+	defer tx.Rollback(ctx)
+
+	for i, id := range req.ImageIds {
+		result, err := tx.Exec(ctx, query, i+1, id, req.ProductId)
+		if err != nil {
+			return 0, mapError(err)
+		}
+
+		rowsAffected += result.RowsAffected()
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return 0, mapError(err)
+	}
+
+	return rowsAffected, nil
+}
+
+func (r *ProductImageRepo) Delete(ctx context.Context, req *models.ProductImagePrimaryKey) error {
+
+	_, err := r.db.Exec(ctx, "DELETE FROM product_image WHERE id = $1", req.Id)
+	if err != nil {
+		return mapError(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,180 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	uuid "github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+
+	"market/api/models"
+	"market/pkg/helper"
+)
+
+// Import upserts the rows by barcode in a single transaction, creating
+// missing categories along each title path. Every created or updated row is
+// recorded in the audit log like any other write. A dry run does all the work and
+// rolls it back, so the counts it reports are exactly what a real run does.
+func (r *ProductRepo) Import(ctx context.Context, req *models.ProductImport) (*models.ProductImportResponse, error) {
+
+	var (
+		resp = &models.ProductImportResponse{
+			DryRun: req.DryRun,
+			Total:  len(req.Rows),
+		}
+		categories = map[string]string{}
+	)
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	defer tx.Rollback(ctx)
+
+	for _, row := range req.Rows {
+		var categoryId string
+
+		for i := range row.CategoryPath {
+			key := strings.Join(row.CategoryPath[:i+1], "\x00")
+
+			if id, ok := categories[key]; ok {
+				categoryId = id
+				continue
+			}
+
+			id, created, err := importCategory(ctx, tx, row.CategoryPath[i], categoryId)
+			if err != nil {
+				return nil, mapError(err)
+			}
+
+			if created {
+				resp.CreatedCategories++
+			}
+
+			categories[key] = id
+			categoryId = id
+		}
+
+		var taken bool
+
+		err = tx.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM product_barcode WHERE barcode = $1 AND deleted_at IS NULL)", row.Barcode).Scan(&taken)
+		if err != nil {
+			return nil, mapError(err)
+		}
+
+		if taken {
+			resp.Errors = append(resp.Errors, &models.ProductImportRowError{
+				Row:     row.Row,
+				Field:   "barcode",
+				Message: "barcode " + row.Barcode + " is an additional barcode of another product",
+			})
+			continue
+		}
+
+		var (
+			productId string
+			before    []byte
+			inserted  bool
+		)
+
+		err = tx.QueryRow(ctx, "SELECT id FROM product WHERE barcode = $1 AND deleted_at IS NULL", row.Barcode).Scan(&productId)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return nil, mapError(err)
+		}
+
+		if productId != "" {
+			before, err = snapshot(ctx, tx, "product", productId)
+			if err != nil {
+				return nil, mapError(err)
+			}
+		}
+
+		query := `
+			INSERT INTO product(id, name, barcode, price, category_id, updated_at)
+			VALUES ($1, $2, $3, $4, $5, NOW())
+			ON CONFLICT (barcode) WHERE deleted_at IS NULL DO UPDATE
+			SET
+				name = EXCLUDED.name,
+				price = EXCLUDED.price,
+				category_id = COALESCE(EXCLUDED.category_id, product.category_id),
+				version = product.version + 1,
+				updated_at = NOW()
+			RETURNING id, xmax = 0
+		`
+
+		err = tx.QueryRow(ctx, query,
+			uuid.New().String(),
+			row.Name,
+			row.Barcode,
+			row.Price,
+			helper.NewNullString(categoryId),
+		).Scan(&productId, &inserted)
+		if err != nil {
+			return nil, mapError(err)
+		}
+
+		err = recordChange(ctx, tx, "product", "product", productId, before)
+		if err != nil {
+			return nil, mapError(err)
+		}
+
+		if inserted {
+			resp.Created++
+		} else {
+			resp.Updated++
+		}
+	}
+
+	if req.DryRun || len(resp.Errors) > 0 {
+		return resp, nil
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	return resp, nil
+}
+
+// importCategory finds the live category with the given title under parentId
+// or creates it.
+func importCategory(ctx context.Context, tx pgx.Tx, title, parentId string) (string, bool, error) {
+
+	var id string
+
+	query := `
+		SELECT id
+		FROM category
+		WHERE title = $1 AND parent_id IS NOT DISTINCT FROM $2 AND deleted_at IS NULL
+		ORDER BY created_at
+		LIMIT 1
+	`
+
+	err := tx.QueryRow(ctx, query, title, helper.NewNullString(parentId)).Scan(&id)
+	if err == nil {
+		return id, false, nil
+	}
+
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return "", false, err
+	}
+
+	id = uuid.New().String()
+
+	_, err = tx.Exec(ctx,
+		"INSERT INTO category(id, title, parent_id, updated_at) VALUES ($1, $2, $3, NOW())",
+		id, title, helper.NewNullString(parentId),
+	)
+	if err != nil {
+		return "", false, err
+	}
+
+	err = recordChange(ctx, tx, "category", "category", id, nil)
+	if err != nil {
+		return "", false, err
+	}
+
+	return id, true, nil
+}
@@ -0,0 +1,426 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+
+	"market/api/models"
+	"market/pkg/helper"
+	"market/storage"
+)
+
+type RemainingRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewRemainingRepo(db *pgxpool.Pool) *RemainingRepo {
+	return &RemainingRepo{
+		db: db,
+	}
+}
+
+func (r *RemainingRepo) GetByID(ctx context.Context, req *models.RemainingPrimaryKey) (*models.Remaining, error) {
+
+	var (
+		query string
+
+		id         sql.NullString
+		branchId   sql.NullString
+		categoryId sql.NullString
+		productId  sql.NullString
+		name       sql.NullString
+		price      sql.NullInt32
+		barcode    sql.NullString
+		count      sql.NullInt32
+		totalPrice sql.NullInt32
+		createdAt  sql.NullString
+		updatedAt  sql.NullString
+	)
+
+	query = `
+		SELECT
+			id,
+			branch_id,
+			category_id,
+			product_id,
+			name,
+			price,
+			barcode,
+			count,
+			total_price,
+			created_at,
+			updated_at
+		FROM remaining
+		WHERE id = $1
+	`
+
+	err := r.db.QueryRow(ctx, query, req.Id).Scan(
+		&id,
+		&branchId,
+		&categoryId,
+		&productId,
+		&name,
+		&price,
+		&barcode,
+		&count,
+		&totalPrice,
+		&createdAt,
+		&updatedAt,
+	)
+
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	return &models.Remaining{
+		Id:         id.String,
+		BranchId:   branchId.String,
+		CategoryId: categoryId.String,
+		ProductId:  productId.String,
+		Name:       name.String,
+		Price:      price.Int32,
+		Barcode:    barcode.String,
+		Count:      count.Int32,
+		TotalPrice: totalPrice.Int32,
+		CreatedAt:  createdAt.String,
+		UpdatedAt:  updatedAt.String,
+	}, nil
+}
+
+func (r *RemainingRepo) GetList(ctx context.Context, req *models.RemainingGetListRequest) (*models.RemainingGetListResponse, error) {
+
+	var (
+		resp   = &models.RemainingGetListResponse{}
+		query  string
+		where  = " WHERE TRUE"
+		order  = " ORDER BY created_at, id"
+		offset = " OFFSET 0"
+		limit  = " LIMIT 10"
+		params = map[string]interface{}{}
+	)
+
+	query = `
+		SELECT
+			COUNT(*) OVER(),
+			id,
+			branch_id,
+			category_id,
+			product_id,
+			name,
+			price,
+			barcode,
+			count,
+			total_price,
+			created_at,
+			updated_at
+		FROM remaining
+	`
+
+	if req.Offset > 0 {
+		offset = fmt.Sprintf(" OFFSET %d", req.Offset)
+	}
+
+	if req.Limit > 0 {
+		limit = fmt.Sprintf(" LIMIT %d", req.Limit)
+	}
+
+	if req.Search != "" {
+		where += ` AND (name ILIKE '%' || :search || '%' OR barcode = :search)`
+		params["search"] = req.Search
+	}
+
+	if req.BranchId != "" {
+		where += " AND branch_id = :branch_id"
+		params["branch_id"] = req.BranchId
+	}
+
+	if req.BranchIds != nil {
+		where += " AND branch_id = ANY(:scope::UUID[])"
+		params["scope"] = req.BranchIds
+	}
+
+	if req.CategoryId != "" {
+		where += " AND category_id = :category_id"
+		params["category_id"] = req.CategoryId
+	}
+
+	query += where + order + offset + limit
+
+	query, args := helper.ReplaceQueryParams(query, params)
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			id         sql.NullString
+			branchId   sql.NullString
+			categoryId sql.NullString
+			productId  sql.NullString
+			name       sql.NullString
+			price      sql.NullInt32
+			barcode    sql.NullString
+			count      sql.NullInt32
+			totalPrice sql.NullInt32
+			createdAt  sql.NullString
+			updatedAt  sql.NullString
+		)
+
+		err := rows.Scan(
+			&resp.Count,
+			&id,
+			&branchId,
+			&categoryId,
+			&productId,
+			&name,
+			&price,
+			&barcode,
+			&count,
+			&totalPrice,
+			&createdAt,
+			&updatedAt,
+		)
+
+		if err != nil {
+			return nil, mapError(err)
+		}
+
+		resp.Remainings = append(resp.Remainings, &models.Remaining{
+			Id:         id.String,
+			BranchId:   branchId.String,
+			CategoryId: categoryId.String,
+			ProductId:  productId.String,
+			Name:       name.String,
+			Price:      price.Int32,
+			Barcode:    barcode.String,
+			Count:      count.Int32,
+			TotalPrice: totalPrice.Int32,
+			CreatedAt:  createdAt.String,
+			UpdatedAt:  updatedAt.String,
+		})
+	}
+
+	return resp, rows.Err()
+}
+
+// Snapshot records today's count of every remaining row, the history the
+// consumption velocity of reorder suggestions is computed from.
+func (r *RemainingRepo) Snapshot(ctx context.Context) (int64, error) {
+
+	query := `
+		INSERT INTO remaining_snapshot(remaining_id, count, taken_on)
+		SELECT id, count, CURRENT_DATE FROM remaining
+		ON CONFLICT (remaining_id, taken_on) DO UPDATE
+		SET count = EXCLUDED.count
+	`
+
+	result, err := r.db.Exec(ctx, query)
+	if err != nil {
+		return 0, mapError(err)
+	}
+
+	return result.RowsAffected(), nil
+}
+
+func (r *RemainingRepo) GetBatchList(ctx context.Context, req *models.RemainingBatchGetListRequest) (*models.RemainingBatchGetListResponse, error) {
+
+	var (
+		resp  = &models.RemainingBatchGetListResponse{}
+		query string
+	)
+
+	query = `
+		SELECT
+			b.id,
+			b.branch_id,
+			b.product_id,
+			b.income_product_id,
+			b.batch,
+			b.expiry_date::TEXT,
+			b.count,
+			b.created_at,
+			b.updated_at
+		FROM remaining_batch AS b
+		JOIN remaining AS r ON r.branch_id = b.branch_id AND r.product_id = b.product_id
+		WHERE r.id = $1 AND b.count > 0
+		ORDER BY b.expiry_date NULLS LAST, b.created_at
+	`
+
+	rows, err := r.db.Query(ctx, query, req.RemainingId)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			id              sql.NullString
+			branchId        sql.NullString
+			productId       sql.NullString
+			incomeProductId sql.NullString
+			batch           sql.NullString
+			expiryDate      sql.NullString
+			count           sql.NullInt32
+			createdAt       sql.NullString
+			updatedAt       sql.NullString
+		)
+
+		err := rows.Scan(
+			&id,
+			&branchId,
+			&productId,
+			&incomeProductId,
+			&batch,
+			&expiryDate,
+			&count,
+			&createdAt,
+			&updatedAt,
+		)
+
+		if err != nil {
+			return nil, mapError(err)
+		}
+
+		resp.Batches = append(resp.Batches, &models.RemainingBatch{
+			Id:              id.String,
+			BranchId:        branchId.String,
+			ProductId:       productId.String,
+			IncomeProductId: incomeProductId.String,
+			Batch:           batch.String,
+			ExpiryDate:      expiryDate.String,
+			Count:           count.Int32,
+			CreatedAt:       createdAt.String,
+			UpdatedAt:       updatedAt.String,
+		})
+	}
+
+	resp.Count = len(resp.Batches)
+
+	return resp, rows.Err()
+}
+
+// Consume takes stock out first-expired-first-out: batches without an expiry
+// date go last, equal dates in the order they were received.
+func (r *RemainingRepo) Consume(ctx context.Context, req *models.ConsumeRemaining) (*models.ConsumeRemainingResponse, error) {
+
+	var (
+		resp        = &models.ConsumeRemainingResponse{}
+		remainingId string
+		count       float64
+		left        = float64(req.Quantity)
+	)
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	defer tx.Rollback(ctx)
+
+	err = tx.QueryRow(ctx,
+		"SELECT id, count::FLOAT8 FROM remaining WHERE branch_id = $1 AND product_id = $2 FOR UPDATE",
+		req.BranchId, req.ProductId,
+	).Scan(&remainingId, &count)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, storage.ErrNotEnoughStock
+	} else if err != nil {
+		return nil, mapError(err)
+	}
+
+	if count < left {
+		return nil, storage.ErrNotEnoughStock
+	}
+
+	rows, err := tx.Query(ctx, `
+		SELECT
+			id,
+			batch,
+			expiry_date::TEXT,
+			count::FLOAT8
+		FROM remaining_batch
+		WHERE branch_id = $1 AND product_id = $2 AND count > 0
+		ORDER BY expiry_date NULLS LAST, created_at
+		FOR UPDATE
+	`, req.BranchId, req.ProductId)
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	var batches []*models.ConsumedBatch
+
+	for rows.Next() && left > 0 {
+		var (
+			id         sql.NullString
+			batch      sql.NullString
+			expiryDate sql.NullString
+			available  float64
+		)
+
+		err = rows.Scan(&id, &batch, &expiryDate, &available)
+		if err != nil {
+			rows.Close()
+			return nil, mapError(err)
+		}
+
+		take := math.Min(available, left)
+		left -= take
+
+		batches = append(batches, &models.ConsumedBatch{
+			BatchId:    id.String,
+			Batch:      batch.String,
+			ExpiryDate: expiryDate.String,
+			Quantity:   int32(take),
+		})
+	}
+	rows.Close()
+
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+
+	// Stock counted before batches were tracked has no batch rows, it is
+	// taken after all the batches.
+	for _, batch := range batches {
+		_, err = tx.Exec(ctx,
+			"UPDATE remaining_batch SET count = count - $2, updated_at = NOW() WHERE id = $1",
+			batch.BatchId, batch.Quantity,
+		)
+		if err != nil {
+			return nil, mapError(err)
+		}
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE remaining
+		SET
+			count = count - $2,
+			total_price = (count - $2) * price,
+			updated_at = NOW()
+		WHERE id = $1
+	`, remainingId, req.Quantity)
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	resp.Batches = batches
+
+	resp.Remaining, err = r.GetByID(ctx, &models.RemainingPrimaryKey{Id: remainingId})
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,768 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+
+	"market/api/models"
+	"market/pkg/apperr"
+	"market/pkg/helper"
+)
+
+type ReportRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewReportRepo(db *pgxpool.Pool) *ReportRepo {
+	return &ReportRepo{
+		db: db,
+	}
+}
+
+type stockItem struct {
+	branchId      string
+	branchName    string
+	categoryId    string
+	categoryTitle string
+	productId     string
+	barcode       string
+	count         float64
+	price         float64
+
+	// snapshot is the count closing the day snapshotOn, the last snapshot
+	// taken up to a past as_of date.
+	snapshot   sql.NullFloat64
+	snapshotOn string
+}
+
+type receiptLine struct {
+	quantity float64
+	price    float64
+	after    bool
+	day      string
+}
+
+// InventoryValuation values the stock in remaining per branch and category.
+// Quantities as of a past date are the daily snapshot closing that day, plus
+// the receipts finished between an older snapshot and the date when the job
+// missed days. Items without any snapshot that old fall back to taking back
+// the receipts finished since from the current count, which misses what went
+// out since. Unit costs come from the receipt lines posted up to the date
+// according to req.Method.
+func (r *ReportRepo) InventoryValuation(ctx context.Context, req *models.InventoryValuationRequest) (*models.InventoryValuation, error) {
+
+	items, err := r.stockItems(ctx, req)
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	lines, err := r.receiptLines(ctx, req)
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	var (
+		resp       = &models.InventoryValuation{Method: req.Method, AsOf: req.AsOf}
+		branches   = map[string]*models.BranchInventoryValuation{}
+		categories = map[string]*models.CategoryInventoryValuation{}
+	)
+
+	for _, item := range items {
+
+		history := lines[item.branchId+"/"+item.productId]
+		if item.productId == "" || len(history) == 0 {
+			history = lines[item.branchId+"/"+item.barcode]
+		}
+
+		var costing []*receiptLine
+		for _, line := range history {
+			if !line.after {
+				costing = append(costing, line)
+			}
+		}
+
+		count := pastCount(item, history)
+		if count <= 0 {
+			continue
+		}
+
+		value := stockValue(req.Method, count, costing, item.price)
+
+		branch, ok := branches[item.branchId]
+		if !ok {
+			branch = &models.BranchInventoryValuation{
+				BranchId:   item.branchId,
+				BranchName: item.branchName,
+			}
+			branches[item.branchId] = branch
+			resp.Branches = append(resp.Branches, branch)
+		}
+
+		category, ok := categories[item.branchId+"/"+item.categoryId]
+		if !ok {
+			category = &models.CategoryInventoryValuation{
+				CategoryId:    item.categoryId,
+				CategoryTitle: item.categoryTitle,
+			}
+			categories[item.branchId+"/"+item.categoryId] = category
+			branch.Categories = append(branch.Categories, category)
+		}
+
+		units := int64(math.Round(count))
+		cost := int64(math.Round(value))
+
+		category.Count += units
+		category.Value += cost
+		branch.Count += units
+		branch.Value += cost
+		resp.Count += units
+		resp.Value += cost
+	}
+
+	sort.Slice(resp.Branches, func(i, j int) bool {
+		return resp.Branches[i].BranchName < resp.Branches[j].BranchName
+	})
+
+	for _, branch := range resp.Branches {
+		sort.Slice(branch.Categories, func(i, j int) bool {
+			return branch.Categories[i].CategoryTitle < branch.Categories[j].CategoryTitle
+		})
+	}
+
+	return resp, nil
+}
+
+// pastCount is the quantity of item as of the date receiptLines split its
+// lines at.
+func pastCount(item *stockItem, lines []*receiptLine) float64 {
+
+	count := item.count
+
+	if item.snapshot.Valid {
+		count = item.snapshot.Float64
+	}
+
+	for _, line := range lines {
+		switch {
+		case item.snapshot.Valid && !line.after && line.day > item.snapshotOn:
+			count += line.quantity
+		case !item.snapshot.Valid && line.after:
+			count -= line.quantity
+		}
+	}
+
+	return count
+}
+
+// stockValue prices count units. lines are ordered newest first; fallback is
+// used when the item was never received.
+func stockValue(method string, count float64, lines []*receiptLine, fallback float64) float64 {
+
+	if len(lines) == 0 {
+		return count * fallback
+	}
+
+	switch method {
+	case models.CostingAverage:
+		var quantity, total float64
+		for _, line := range lines {
+			quantity += line.quantity
+			total += line.quantity * line.price
+		}
+
+		if quantity <= 0 {
+			return count * lines[0].price
+		}
+
+		return count * total / quantity
+	case models.CostingLastPurchase:
+		return count * lines[0].price
+	default:
+		// FIFO sells the oldest units first, so what is left in stock comes
+		// from the newest receipts.
+		var value float64
+		for _, line := range lines {
+			if count <= 0 {
+				break
+			}
+
+			take := math.Min(count, line.quantity)
+			value += take * line.price
+			count -= take
+		}
+
+		if count > 0 {
+			value += count * lines[len(lines)-1].price
+		}
+
+		return value
+	}
+}
+
+func (r *ReportRepo) stockItems(ctx context.Context, req *models.InventoryValuationRequest) ([]*stockItem, error) {
+
+	var (
+		items  []*stockItem
+		where  = " WHERE TRUE"
+		params = map[string]interface{}{
+			"as_of": req.AsOf,
+		}
+	)
+
+	query := `
+		SELECT
+			r.branch_id,
+			b.name,
+			r.category_id,
+			c.title,
+			r.product_id,
+			r.barcode,
+			r.count::FLOAT8,
+			r.price::FLOAT8,
+			s.count::FLOAT8,
+			s.taken_on::TEXT
+		FROM remaining AS r
+		LEFT JOIN branch AS b ON b.id = r.branch_id
+		LEFT JOIN category AS c ON c.id = r.category_id
+		LEFT JOIN LATERAL (
+			SELECT rs.count, rs.taken_on
+			FROM remaining_snapshot AS rs
+			WHERE rs.remaining_id = r.id AND rs.taken_on <= :as_of::DATE
+			ORDER BY rs.taken_on DESC
+			LIMIT 1
+		) AS s ON :as_of::DATE < CURRENT_DATE
+	`
+
+	if req.BranchId != "" {
+		where += " AND r.branch_id = :branch_id"
+		params["branch_id"] = req.BranchId
+	}
+
+	if req.BranchIds != nil {
+		where += " AND r.branch_id = ANY(:scope::UUID[])"
+		params["scope"] = req.BranchIds
+	}
+
+	if req.CategoryId != "" {
+		where += " AND r.category_id = :category_id"
+		params["category_id"] = req.CategoryId
+	}
+
+	query, args := helper.ReplaceQueryParams(query+where, params)
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			branchId      sql.NullString
+			branchName    sql.NullString
+			categoryId    sql.NullString
+			categoryTitle sql.NullString
+			productId     sql.NullString
+			barcode       sql.NullString
+			count         sql.NullFloat64
+			price         sql.NullFloat64
+			snapshot      sql.NullFloat64
+			snapshotOn    sql.NullString
+		)
+
+		err := rows.Scan(
+			&branchId,
+			&branchName,
+			&categoryId,
+			&categoryTitle,
+			&productId,
+			&barcode,
+			&count,
+			&price,
+			&snapshot,
+			&snapshotOn,
+		)
+		if err != nil {
+			return nil, mapError(err)
+		}
+
+		items = append(items, &stockItem{
+			branchId:      branchId.String,
+			branchName:    branchName.String,
+			categoryId:    categoryId.String,
+			categoryTitle: categoryTitle.String,
+			productId:     productId.String,
+			barcode:       barcode.String,
+			count:         count.Float64,
+			price:         price.Float64,
+			snapshot:      snapshot,
+			snapshotOn:    snapshotOn.String,
+		})
+	}
+
+	return items, rows.Err()
+}
+
+// receiptLines loads the finished receipt lines keyed by branch and by both
+// product id and barcode, newest first.
+func (r *ReportRepo) receiptLines(ctx context.Context, req *models.InventoryValuationRequest) (map[string][]*receiptLine, error) {
+
+	var (
+		lines  = map[string][]*receiptLine{}
+		where  = " WHERE sc.status = :status AND ip.deleted_at IS NULL"
+		params = map[string]interface{}{
+			"status": models.StorageComingStatusFinished,
+			"as_of":  req.AsOf,
+		}
+	)
+
+	query := `
+		SELECT
+			sc.branch_id,
+			ip.product_id,
+			ip.barcode,
+			ip.quantity::FLOAT8,
+			ip.price::FLOAT8,
+			COALESCE(sc.date_time, sc.created_at) > :as_of::TIMESTAMP,
+			COALESCE(sc.date_time, sc.created_at)::DATE::TEXT
+		FROM income_products AS ip
+		JOIN storage_coming AS sc ON sc.id = ip.storage_coming_id
+	`
+
+	if req.BranchId != "" {
+		where += " AND sc.branch_id = :branch_id"
+		params["branch_id"] = req.BranchId
+	}
+
+	if req.BranchIds != nil {
+		where += " AND sc.branch_id = ANY(:scope::UUID[])"
+		params["scope"] = req.BranchIds
+	}
+
+	query += where + " ORDER BY COALESCE(sc.date_time, sc.created_at) DESC, ip.created_at DESC"
+
+	query, args := helper.ReplaceQueryParams(query, params)
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			branchId  sql.NullString
+			productId sql.NullString
+			barcode   sql.NullString
+			quantity  sql.NullFloat64
+			price     sql.NullFloat64
+			after     sql.NullBool
+			day       sql.NullString
+		)
+
+		err := rows.Scan(
+			&branchId,
+			&productId,
+			&barcode,
+			&quantity,
+			&price,
+			&after,
+			&day,
+		)
+		if err != nil {
+			return nil, mapError(err)
+		}
+
+		line := &receiptLine{
+			quantity: quantity.Float64,
+			price:    price.Float64,
+			after:    after.Bool,
+			day:      day.String,
+		}
+
+		if productId.Valid {
+			lines[branchId.String+"/"+productId.String] = append(lines[branchId.String+"/"+productId.String], line)
+		}
+
+		if barcode.String != "" {
+			lines[branchId.String+"/"+barcode.String] = append(lines[branchId.String+"/"+barcode.String], line)
+		}
+	}
+
+	return lines, rows.Err()
+}
+
+// goodsReceiptGroups holds the key, title and order expressions of every
+// goods receipt grouping.
+var goodsReceiptGroups = map[string][3]string{
+	models.GoodsReceiptGroupBranch:   {"sc.branch_id::TEXT", "MAX(b.name)", "3, 2"},
+	models.GoodsReceiptGroupCategory: {"ip.category_id::TEXT", "MAX(c.title)", "3, 2"},
+	models.GoodsReceiptGroupProduct:  {"COALESCE(ip.product_id::TEXT, ip.barcode, ip.name)", "MAX(ip.name)", "3, 2"},
+	models.GoodsReceiptGroupDay:      {"DATE_TRUNC('day', COALESCE(sc.date_time, sc.created_at))::DATE::TEXT", "''", "2"},
+	models.GoodsReceiptGroupWeek:     {"DATE_TRUNC('week', COALESCE(sc.date_time, sc.created_at))::DATE::TEXT", "''", "2"},
+	models.GoodsReceiptGroupMonth:    {"DATE_TRUNC('month', COALESCE(sc.date_time, sc.created_at))::DATE::TEXT", "''", "2"},
+	models.GoodsReceiptGroupReceipt:  {"sc.id::TEXT", "MAX(sc.coming_id)", "MAX(COALESCE(sc.date_time, sc.created_at)), 2"},
+}
+
+// GoodsReceipt sums received quantities and values of income_products
+// grouped by req.GroupBy, with the totals over all groups.
+func (r *ReportRepo) GoodsReceipt(ctx context.Context, req *models.GoodsReceiptReportRequest) (*models.GoodsReceiptReport, error) {
+
+	group, ok := goodsReceiptGroups[req.GroupBy]
+	if !ok {
+		return nil, apperr.Validation("invalid group_by", apperr.FieldError{Field: "group_by", Message: "unknown grouping " + req.GroupBy})
+	}
+
+	var (
+		resp   = &models.GoodsReceiptReport{GroupBy: req.GroupBy}
+		where  = " WHERE ip.deleted_at IS NULL AND sc.deleted_at IS NULL"
+		offset = " OFFSET 0"
+		limit  = " LIMIT 10"
+		params = map[string]interface{}{}
+	)
+
+	from := `
+		FROM income_products AS ip
+		JOIN storage_coming AS sc ON sc.id = ip.storage_coming_id
+		LEFT JOIN branch AS b ON b.id = sc.branch_id
+		LEFT JOIN category AS c ON c.id = ip.category_id
+	`
+
+	if req.From != "" {
+		where += " AND COALESCE(sc.date_time, sc.created_at) >= :from::TIMESTAMP"
+		params["from"] = req.From
+	}
+
+	if req.To != "" {
+		where += " AND COALESCE(sc.date_time, sc.created_at) <= :to::TIMESTAMP"
+		params["to"] = req.To
+	}
+
+	if req.Status != "" {
+		where += " AND sc.status = :status"
+		params["status"] = req.Status
+	}
+
+	if req.BranchId != "" {
+		where += " AND sc.branch_id = :branch_id"
+		params["branch_id"] = req.BranchId
+	}
+
+	if req.BranchIds != nil {
+		where += " AND sc.branch_id = ANY(:scope::UUID[])"
+		params["scope"] = req.BranchIds
+	}
+
+	if req.CategoryId != "" {
+		where += " AND ip.category_id = :category_id"
+		params["category_id"] = req.CategoryId
+	}
+
+	if req.ProductId != "" {
+		where += " AND ip.product_id = :product_id"
+		params["product_id"] = req.ProductId
+	}
+
+	if req.Offset > 0 {
+		offset = fmt.Sprintf(" OFFSET %d", req.Offset)
+	}
+
+	if req.Limit > 0 {
+		limit = fmt.Sprintf(" LIMIT %d", req.Limit)
+	}
+
+	sums := `
+			COUNT(DISTINCT sc.id),
+			COUNT(*),
+			ROUND(COALESCE(SUM(ip.quantity), 0))::BIGINT,
+			ROUND(COALESCE(SUM(ip.total_price), 0))::BIGINT
+	`
+
+	totals, args := helper.ReplaceQueryParams("SELECT"+sums+from+where, params)
+
+	err := r.db.QueryRow(ctx, totals, args...).Scan(
+		&resp.Receipts,
+		&resp.Lines,
+		&resp.Quantity,
+		&resp.Value,
+	)
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	query := `
+		SELECT
+			COUNT(*) OVER(),
+			` + group[0] + `,
+			` + group[1] + `,` + sums + from + where + `
+		GROUP BY 2
+		ORDER BY ` + group[2] + offset + limit
+
+	query, args = helper.ReplaceQueryParams(query, params)
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			key   sql.NullString
+			title sql.NullString
+			row   models.GoodsReceiptReportRow
+		)
+
+		err := rows.Scan(
+			&resp.Count,
+			&key,
+			&title,
+			&row.Receipts,
+			&row.Lines,
+			&row.Quantity,
+			&row.Value,
+		)
+		if err != nil {
+			return nil, mapError(err)
+		}
+
+		row.Key = key.String
+		row.Title = title.String
+
+		resp.Rows = append(resp.Rows, &row)
+	}
+
+	return resp, rows.Err()
+}
+
+// Reorder lists the products whose stock is below their minimum level.
+// Consumption over the window is the oldest snapshot count plus what was
+// received since, minus the current count.
+func (r *ReportRepo) Reorder(ctx context.Context, req *models.ReorderReportRequest) (*models.ReorderReport, error) {
+
+	var (
+		resp   = &models.ReorderReport{}
+		where  = " WHERE TRUE"
+		params = map[string]interface{}{
+			"window": req.WindowDays,
+			"status": models.StorageComingStatusFinished,
+		}
+	)
+
+	query := `
+		SELECT
+			l.branch_id,
+			b.name,
+			l.product_id,
+			p.name,
+			p.barcode,
+			COALESCE(r.count, 0)::FLOAT8,
+			l.min_count::FLOAT8,
+			l.max_count::FLOAT8,
+			s.count::FLOAT8,
+			CURRENT_DATE - s.taken_on,
+			COALESCE((
+				SELECT SUM(ip.quantity)
+				FROM income_products AS ip
+				JOIN storage_coming AS sc ON sc.id = ip.storage_coming_id
+				WHERE sc.branch_id = l.branch_id AND ip.product_id = l.product_id AND sc.status = :status
+					AND COALESCE(sc.date_time, sc.created_at) >= s.taken_on
+			), 0)::FLOAT8
+		FROM stock_level AS l
+		JOIN branch AS b ON b.id = l.branch_id
+		JOIN product AS p ON p.id = l.product_id
+		LEFT JOIN remaining AS r ON r.branch_id = l.branch_id AND r.product_id = l.product_id
+		LEFT JOIN LATERAL (
+			SELECT rs.count, rs.taken_on
+			FROM remaining_snapshot AS rs
+			WHERE rs.remaining_id = r.id AND rs.taken_on >= CURRENT_DATE - :window::INT
+			ORDER BY rs.taken_on
+			LIMIT 1
+		) AS s ON TRUE
+	`
+
+	if !req.All {
+		where += " AND COALESCE(r.count, 0) < l.min_count"
+	}
+
+	if req.BranchId != "" {
+		where += " AND l.branch_id = :branch_id"
+		params["branch_id"] = req.BranchId
+	}
+
+	if req.BranchIds != nil {
+		where += " AND l.branch_id = ANY(:scope::UUID[])"
+		params["scope"] = req.BranchIds
+	}
+
+	query, args := helper.ReplaceQueryParams(query+where+" ORDER BY b.name, p.name", params)
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			branchId   sql.NullString
+			branchName sql.NullString
+			productId  sql.NullString
+			name       sql.NullString
+			barcode    sql.NullString
+			count      sql.NullFloat64
+			minCount   sql.NullFloat64
+			maxCount   sql.NullFloat64
+			baseCount  sql.NullFloat64
+			days       sql.NullInt32
+			received   sql.NullFloat64
+		)
+
+		err := rows.Scan(
+			&branchId,
+			&branchName,
+			&productId,
+			&name,
+			&barcode,
+			&count,
+			&minCount,
+			&maxCount,
+			&baseCount,
+			&days,
+			&received,
+		)
+		if err != nil {
+			return nil, mapError(err)
+		}
+
+		suggestion := &models.ReorderSuggestion{
+			BranchId:   branchId.String,
+			BranchName: branchName.String,
+			ProductId:  productId.String,
+			Name:       name.String,
+			Barcode:    barcode.String,
+			Count:      int32(count.Float64),
+			MinCount:   int32(minCount.Float64),
+			MaxCount:   int32(maxCount.Float64),
+		}
+
+		if baseCount.Valid {
+			consumed := baseCount.Float64 + received.Float64 - count.Float64
+			if consumed > 0 {
+				suggestion.Velocity = math.Round(consumed/math.Max(float64(days.Int32), 1)*100) / 100
+			}
+		}
+
+		if suggestion.Velocity > 0 {
+			suggestion.DaysLeft = math.Round(count.Float64/suggestion.Velocity*10) / 10
+		}
+
+		quantity := maxCount.Float64 - count.Float64 + suggestion.Velocity*float64(req.LeadDays)
+		if quantity > 0 {
+			suggestion.Quantity = int32(math.Ceil(quantity))
+		}
+
+		resp.Suggestions = append(resp.Suggestions, suggestion)
+	}
+
+	resp.Count = len(resp.Suggestions)
+
+	return resp, rows.Err()
+}
+
+// Expiring lists batches in stock that expire within req.Days, expired ones
+// included, soonest first.
+func (r *ReportRepo) Expiring(ctx context.Context, req *models.ExpiringReportRequest) (*models.ExpiringReport, error) {
+
+	var (
+		resp   = &models.ExpiringReport{}
+		where  = " WHERE rb.count > 0 AND rb.expiry_date <= CURRENT_DATE + :days::INT"
+		params = map[string]interface{}{
+			"days": req.Days,
+		}
+	)
+
+	query := `
+		SELECT
+			rb.branch_id,
+			b.name,
+			rb.product_id,
+			p.name,
+			p.barcode,
+			rb.batch,
+			rb.expiry_date::TEXT,
+			rb.expiry_date - CURRENT_DATE,
+			rb.count
+		FROM remaining_batch AS rb
+		JOIN branch AS b ON b.id = rb.branch_id
+		JOIN product AS p ON p.id = rb.product_id
+	`
+
+	if req.BranchId != "" {
+		where += " AND rb.branch_id = :branch_id"
+		params["branch_id"] = req.BranchId
+	}
+
+	if req.BranchIds != nil {
+		where += " AND rb.branch_id = ANY(:scope::UUID[])"
+		params["scope"] = req.BranchIds
+	}
+
+	query, args := helper.ReplaceQueryParams(query+where+" ORDER BY rb.expiry_date, b.name, p.name", params)
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			branchId   sql.NullString
+			branchName sql.NullString
+			productId  sql.NullString
+			name       sql.NullString
+			barcode    sql.NullString
+			batch      sql.NullString
+			expiryDate sql.NullString
+			daysLeft   sql.NullInt32
+			count      sql.NullInt32
+		)
+
+		err := rows.Scan(
+			&branchId,
+			&branchName,
+			&productId,
+			&name,
+			&barcode,
+			&batch,
+			&expiryDate,
+			&daysLeft,
+			&count,
+		)
+		if err != nil {
+			return nil, mapError(err)
+		}
+
+		resp.Batches = append(resp.Batches, &models.ExpiringBatch{
+			BranchId:   branchId.String,
+			BranchName: branchName.String,
+			ProductId:  productId.String,
+			Name:       name.String,
+			Barcode:    barcode.String,
+			Batch:      batch.String,
+			ExpiryDate: expiryDate.String,
+			DaysLeft:   daysLeft.Int32,
+			Count:      count.Int32,
+		})
+	}
+
+	resp.Count = len(resp.Batches)
+
+	return resp, rows.Err()
+}
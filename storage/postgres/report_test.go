@@ -0,0 +1,97 @@
+package postgres
+
+import (
+	"database/sql"
+	"math"
+	"testing"
+
+	"market/api/models"
+)
+
+func TestStockValue(t *testing.T) {
+
+	// Newest first, 35 units for 3500 in all.
+	lines := []*receiptLine{
+		{quantity: 10, price: 120},
+		{quantity: 5, price: 100},
+		{quantity: 20, price: 90},
+	}
+
+	tests := []struct {
+		name     string
+		method   string
+		count    float64
+		lines    []*receiptLine
+		fallback float64
+		want     float64
+	}{
+		{name: "fifo within the newest receipt", method: models.CostingFIFO, count: 8, lines: lines, want: 960},
+		{name: "fifo across receipts", method: models.CostingFIFO, count: 12, lines: lines, want: 1400},
+		{name: "fifo beyond all receipts", method: models.CostingFIFO, count: 40, lines: lines, want: 3950},
+		{name: "fifo nothing left", method: models.CostingFIFO, count: 0, lines: lines, want: 0},
+		{name: "unknown method is fifo", method: "", count: 12, lines: lines, want: 1400},
+		{name: "average", method: models.CostingAverage, count: 12, lines: lines, want: 1200},
+		{name: "average of zero quantities", method: models.CostingAverage, count: 3, lines: []*receiptLine{{quantity: 0, price: 70}}, want: 210},
+		{name: "last purchase", method: models.CostingLastPurchase, count: 12, lines: lines, want: 1440},
+		{name: "never received", method: models.CostingFIFO, count: 12, fallback: 50, want: 600},
+		{name: "never received average", method: models.CostingAverage, count: 12, fallback: 50, want: 600},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := stockValue(tt.method, tt.count, tt.lines, tt.fallback); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("stockValue() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPastCount(t *testing.T) {
+
+	tests := []struct {
+		name  string
+		item  *stockItem
+		lines []*receiptLine
+		want  float64
+	}{
+		{
+			name: "no snapshot takes back later receipts",
+			item: &stockItem{count: 30},
+			lines: []*receiptLine{
+				{quantity: 5, after: true, day: "2026-10-15"},
+				{quantity: 10, day: "2026-10-01"},
+				{quantity: 3, after: true, day: "2026-10-14"},
+			},
+			want: 22,
+		},
+		{
+			name: "snapshot adds receipts after its day",
+			item: &stockItem{count: 30, snapshot: sql.NullFloat64{Float64: 20, Valid: true}, snapshotOn: "2026-10-10"},
+			lines: []*receiptLine{
+				{quantity: 7, after: true, day: "2026-10-15"},
+				{quantity: 5, day: "2026-10-12"},
+				{quantity: 4, day: "2026-10-10"},
+				{quantity: 6, day: "2026-10-02"},
+			},
+			want: 25,
+		},
+		{
+			name: "sold out on the snapshot day",
+			item: &stockItem{count: 30, snapshot: sql.NullFloat64{Float64: 0, Valid: true}, snapshotOn: "2026-10-10"},
+			want: 0,
+		},
+		{
+			name: "no snapshot and no receipts",
+			item: &stockItem{count: 12},
+			want: 12,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pastCount(tt.item, tt.lines); got != tt.want {
+				t.Errorf("pastCount() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,347 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	uuid "github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+
+	"market/api/models"
+	"market/pkg/helper"
+	"market/storage"
+)
+
+type SerialNumberRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewSerialNumberRepo(db *pgxpool.Pool) *SerialNumberRepo {
+	return &SerialNumberRepo{
+		db: db,
+	}
+}
+
+func addSerialNumberEvent(ctx context.Context, db execer, serialNumberId, action, status, branchId, note string) error {
+
+	_, err := db.Exec(ctx, `
+		INSERT INTO serial_number_event(id, serial_number_id, action, status, branch_id, note)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`,
+		uuid.New().String(),
+		serialNumberId,
+		action,
+		status,
+		helper.NewNullString(branchId),
+		helper.NewNullString(note),
+	)
+
+	return err
+}
+
+func (r *SerialNumberRepo) GetByID(ctx context.Context, req *models.SerialNumberPrimaryKey) (*models.SerialNumber, error) {
+
+	var (
+		query string
+
+		id              sql.NullString
+		productId       sql.NullString
+		serial          sql.NullString
+		status          sql.NullString
+		branchId        sql.NullString
+		incomeProductId sql.NullString
+		createdAt       sql.NullString
+		updatedAt       sql.NullString
+	)
+
+	query = `
+		SELECT
+			id,
+			product_id,
+			serial,
+			status,
+			branch_id,
+			income_product_id,
+			created_at,
+			updated_at
+		FROM serial_number
+		WHERE id = $1
+	`
+
+	err := r.db.QueryRow(ctx, query, req.Id).Scan(
+		&id,
+		&productId,
+		&serial,
+		&status,
+		&branchId,
+		&incomeProductId,
+		&createdAt,
+		&updatedAt,
+	)
+
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	history, err := r.getHistory(ctx, id.String)
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	return &models.SerialNumber{
+		Id:              id.String,
+		ProductId:       productId.String,
+		Serial:          serial.String,
+		Status:          status.String,
+		BranchId:        branchId.String,
+		IncomeProductId: incomeProductId.String,
+		History:         history,
+		CreatedAt:       createdAt.String,
+		UpdatedAt:       updatedAt.String,
+	}, nil
+}
+
+func (r *SerialNumberRepo) getHistory(ctx context.Context, serialNumberId string) ([]*models.SerialNumberEvent, error) {
+
+	var history []*models.SerialNumberEvent
+
+	query := `
+		SELECT
+			id,
+			action,
+			status,
+			branch_id,
+			note,
+			created_at
+		FROM serial_number_event
+		WHERE serial_number_id = $1
+		ORDER BY created_at, id
+	`
+
+	rows, err := r.db.Query(ctx, query, serialNumberId)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			id        sql.NullString
+			action    sql.NullString
+			status    sql.NullString
+			branchId  sql.NullString
+			note      sql.NullString
+			createdAt sql.NullString
+		)
+
+		err := rows.Scan(
+			&id,
+			&action,
+			&status,
+			&branchId,
+			&note,
+			&createdAt,
+		)
+
+		if err != nil {
+			return nil, mapError(err)
+		}
+
+		history = append(history, &models.SerialNumberEvent{
+			Id:        id.String,
+			Action:    action.String,
+			Status:    status.String,
+			BranchId:  branchId.String,
+			Note:      note.String,
+			CreatedAt: createdAt.String,
+		})
+	}
+
+	return history, rows.Err()
+}
+
+func (r *SerialNumberRepo) GetList(ctx context.Context, req *models.SerialNumberGetListRequest) (*models.SerialNumberGetListResponse, error) {
+
+	var (
+		resp   = &models.SerialNumberGetListResponse{}
+		query  string
+		where  = " WHERE TRUE"
+		order  = " ORDER BY created_at, id"
+		offset = " OFFSET 0"
+		limit  = " LIMIT 10"
+		params = map[string]interface{}{}
+	)
+
+	query = `
+		SELECT
+			COUNT(*) OVER(),
+			id,
+			product_id,
+			serial,
+			status,
+			branch_id,
+			income_product_id,
+			created_at,
+			updated_at
+		FROM serial_number
+	`
+
+	if req.Offset > 0 {
+		offset = fmt.Sprintf(" OFFSET %d", req.Offset)
+	}
+
+	if req.Limit > 0 {
+		limit = fmt.Sprintf(" LIMIT %d", req.Limit)
+	}
+
+	if req.Serial != "" {
+		where += " AND serial = :serial"
+		params["serial"] = req.Serial
+	}
+
+	if req.ProductId != "" {
+		where += " AND product_id = :product_id"
+		params["product_id"] = req.ProductId
+	}
+
+	if req.BranchId != "" {
+		where += " AND branch_id = :branch_id"
+		params["branch_id"] = req.BranchId
+	}
+
+	if req.BranchIds != nil {
+		where += " AND branch_id = ANY(:scope::UUID[])"
+		params["scope"] = req.BranchIds
+	}
+
+	if req.Status != "" {
+		where += " AND status = :status"
+		params["status"] = req.Status
+	}
+
+	query += where + order + offset + limit
+
+	query, args := helper.ReplaceQueryParams(query, params)
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			id              sql.NullString
+			productId       sql.NullString
+			serial          sql.NullString
+			status          sql.NullString
+			branchId        sql.NullString
+			incomeProductId sql.NullString
+			createdAt       sql.NullString
+			updatedAt       sql.NullString
+		)
+
+		err := rows.Scan(
+			&resp.Count,
+			&id,
+			&productId,
+			&serial,
+			&status,
+			&branchId,
+			&incomeProductId,
+			&createdAt,
+			&updatedAt,
+		)
+
+		if err != nil {
+			return nil, mapError(err)
+		}
+
+		resp.SerialNumbers = append(resp.SerialNumbers, &models.SerialNumber{
+			Id:              id.String,
+			ProductId:       productId.String,
+			Serial:          serial.String,
+			Status:          status.String,
+			BranchId:        branchId.String,
+			IncomeProductId: incomeProductId.String,
+			CreatedAt:       createdAt.String,
+			UpdatedAt:       updatedAt.String,
+		})
+	}
+
+	return resp, nil
+}
+
+// Move applies a lifecycle action to a unit and records it in its history.
+// Units in stock can be transferred or sold, sold units can be returned.
+func (r *SerialNumberRepo) Move(ctx context.Context, req *models.MoveSerialNumber) (int64, error) {
+
+	var (
+		status   sql.NullString
+		branchId sql.NullString
+	)
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, mapError(err)
+	}
+	defer tx.Rollback(ctx)
+
+	err = tx.QueryRow(ctx, "SELECT status, branch_id FROM serial_number WHERE id = $1 FOR UPDATE", req.Id).Scan(&status, &branchId)
+	if err != nil {
+		return 0, mapError(err)
+	}
+
+	var (
+		required  string
+		newStatus string
+		newBranch = branchId.String
+	)
+
+	switch req.Action {
+	case models.SerialNumberActionTransfer:
+		required, newStatus, newBranch = models.SerialNumberStatusInStock, models.SerialNumberStatusInStock, req.BranchId
+		if req.BranchId == "" || req.BranchId == branchId.String {
+			return 0, fmt.Errorf("%w: transfer needs another branch_id", storage.ErrInvalidState)
+		}
+	case models.SerialNumberActionSell:
+		required, newStatus = models.SerialNumberStatusInStock, models.SerialNumberStatusSold
+	case models.SerialNumberActionReturn:
+		required, newStatus = models.SerialNumberStatusSold, models.SerialNumberStatusInStock
+		if req.BranchId != "" {
+			newBranch = req.BranchId
+		}
+	default:
+		return 0, fmt.Errorf("%w: unknown action %q", storage.ErrInvalidState, req.Action)
+	}
+
+	if status.String != required {
+		return 0, fmt.Errorf("%w: serial number is %s, cannot be %s", storage.ErrInvalidState, status.String, req.Action)
+	}
+
+	result, err := tx.Exec(ctx,
+		"UPDATE serial_number SET status = $2, branch_id = $3, updated_at = NOW() WHERE id = $1",
+		req.Id, newStatus, helper.NewNullString(newBranch),
+	)
+	if err != nil {
+		return 0, mapError(err)
+	}
+
+	if result.RowsAffected() <= 0 {
+		return 0, mapError(pgx.ErrNoRows)
+	}
+
+	err = addSerialNumberEvent(ctx, tx, req.Id, req.Action, newStatus, newBranch, req.Note)
+	if err != nil {
+		return 0, mapError(err)
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return 0, mapError(err)
+	}
+
+	return result.RowsAffected(), nil
+}
@@ -0,0 +1,168 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	uuid "github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+
+	"market/api/models"
+	"market/storage"
+)
+
+type SessionRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewSessionRepo(db *pgxpool.Pool) *SessionRepo {
+	return &SessionRepo{
+		db: db,
+	}
+}
+
+func (r *SessionRepo) Create(ctx context.Context, req *models.CreateSession) (string, error) {
+
+	var (
+		id    = uuid.New().String()
+		query string
+	)
+
+	query = `
+		INSERT INTO refresh_token(id, user_id, family_id, token_hash, expires_at)
+		VALUES ($1, $2, $3, $4, NOW() + make_interval(secs => $5))
+	`
+
+	_, err := r.db.Exec(ctx, query,
+		id,
+		req.UserId,
+		req.FamilyId,
+		req.TokenHash,
+		req.TTL,
+	)
+
+	if err != nil {
+		return "", mapError(err)
+	}
+
+	return id, nil
+}
+
+// Rotate revokes the presented refresh token and issues its successor. A
+// token that was already rotated or revoked being presented again means it
+// leaked, so its whole family is revoked.
+func (r *SessionRepo) Rotate(ctx context.Context, req *models.RotateSession) (*models.Session, error) {
+
+	var (
+		id       sql.NullString
+		userId   sql.NullString
+		familyId sql.NullString
+		revoked  sql.NullBool
+		expired  sql.NullBool
+		active   sql.NullBool
+	)
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	defer tx.Rollback(ctx)
+
+	err = tx.QueryRow(ctx, `
+		SELECT
+			rt.id,
+			rt.user_id,
+			rt.family_id,
+			rt.revoked_at IS NOT NULL,
+			rt.expires_at <= NOW(),
+			u.active
+		FROM refresh_token AS rt
+		JOIN users AS u ON u.id = rt.user_id
+		WHERE rt.token_hash = $1
+		FOR UPDATE OF rt
+	`, req.TokenHash).Scan(&id, &userId, &familyId, &revoked, &expired, &active)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, storage.ErrInvalidToken
+	} else if err != nil {
+		return nil, mapError(err)
+	}
+
+	if revoked.Bool {
+		_, err = tx.Exec(ctx,
+			"UPDATE refresh_token SET revoked_at = NOW() WHERE family_id = $1 AND revoked_at IS NULL",
+			familyId.String,
+		)
+		if err != nil {
+			return nil, mapError(err)
+		}
+
+		err = tx.Commit(ctx)
+		if err != nil {
+			return nil, mapError(err)
+		}
+
+		return nil, storage.ErrInvalidToken
+	}
+
+	if expired.Bool || !active.Bool {
+		return nil, storage.ErrInvalidToken
+	}
+
+	_, err = tx.Exec(ctx, "UPDATE refresh_token SET revoked_at = NOW() WHERE id = $1", id.String)
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	session := &models.Session{
+		Id:        uuid.New().String(),
+		UserId:    userId.String,
+		FamilyId:  familyId.String,
+		TokenHash: req.NewTokenHash,
+	}
+
+	err = tx.QueryRow(ctx, `
+		INSERT INTO refresh_token(id, user_id, family_id, token_hash, expires_at)
+		VALUES ($1, $2, $3, $4, NOW() + make_interval(secs => $5))
+		RETURNING expires_at::TEXT
+	`, session.Id, session.UserId, session.FamilyId, session.TokenHash, req.TTL).Scan(&session.ExpiresAt)
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	return session, nil
+}
+
+func (r *SessionRepo) RevokeFamily(ctx context.Context, familyId string) error {
+
+	_, err := r.db.Exec(ctx,
+		"UPDATE refresh_token SET revoked_at = NOW() WHERE family_id = $1 AND revoked_at IS NULL",
+		familyId,
+	)
+
+	return mapError(err)
+}
+
+// Active reports whether a family still has a usable refresh token, which is
+// what keeps access tokens issued with it valid.
+func (r *SessionRepo) Active(ctx context.Context, familyId string) (bool, error) {
+
+	var active bool
+
+	err := r.db.QueryRow(ctx, `
+		SELECT EXISTS (
+			SELECT 1
+			FROM refresh_token AS rt
+			JOIN users AS u ON u.id = rt.user_id
+			WHERE rt.family_id = $1 AND rt.revoked_at IS NULL AND rt.expires_at > NOW() AND u.active
+		)
+	`, familyId).Scan(&active)
+
+	return active, mapError(err)
+}
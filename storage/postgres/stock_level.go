@@ -0,0 +1,218 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	uuid "github.com/google/uuid"
+	"github.com/jackc/pgx/v4/pgxpool"
+
+	"market/api/models"
+	"market/pkg/helper"
+)
+
+type StockLevelRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewStockLevelRepo(db *pgxpool.Pool) *StockLevelRepo {
+	return &StockLevelRepo{
+		db: db,
+	}
+}
+
+// Create sets the levels of a product at a branch, replacing the ones
+// already set.
+func (r *StockLevelRepo) Create(ctx context.Context, req *models.CreateStockLevel) (string, error) {
+
+	var (
+		id    string
+		query string
+	)
+
+	query = `
+		INSERT INTO stock_level(id, branch_id, product_id, min_count, max_count, updated_at)
+		VALUES ($1, $2, $3, $4, $5, NOW())
+		ON CONFLICT (branch_id, product_id) DO UPDATE
+		SET
+			min_count = EXCLUDED.min_count,
+			max_count = EXCLUDED.max_count,
+			updated_at = NOW()
+		RETURNING id
+	`
+
+	err := r.db.QueryRow(ctx, query,
+		uuid.New().String(),
+		req.BranchId,
+		req.ProductId,
+		req.MinCount,
+		req.MaxCount,
+	).Scan(&id)
+
+	if err != nil {
+		return "", mapError(err)
+	}
+
+	return id, nil
+}
+
+func (r *StockLevelRepo) GetByID(ctx context.Context, req *models.StockLevelPrimaryKey) (*models.StockLevel, error) {
+
+	var (
+		query string
+
+		id        sql.NullString
+		branchId  sql.NullString
+		productId sql.NullString
+		minCount  sql.NullInt32
+		maxCount  sql.NullInt32
+		createdAt sql.NullString
+		updatedAt sql.NullString
+	)
+
+	query = `
+		SELECT
+			id,
+			branch_id,
+			product_id,
+			min_count,
+			max_count,
+			created_at,
+			updated_at
+		FROM stock_level
+		WHERE id = $1
+	`
+
+	err := r.db.QueryRow(ctx, query, req.Id).Scan(
+		&id,
+		&branchId,
+		&productId,
+		&minCount,
+		&maxCount,
+		&createdAt,
+		&updatedAt,
+	)
+
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	return &models.StockLevel{
+		Id:        id.String,
+		BranchId:  branchId.String,
+		ProductId: productId.String,
+		MinCount:  minCount.Int32,
+		MaxCount:  maxCount.Int32,
+		CreatedAt: createdAt.String,
+		UpdatedAt: updatedAt.String,
+	}, nil
+}
+
+func (r *StockLevelRepo) GetList(ctx context.Context, req *models.StockLevelGetListRequest) (*models.StockLevelGetListResponse, error) {
+
+	var (
+		resp   = &models.StockLevelGetListResponse{}
+		query  string
+		where  = " WHERE TRUE"
+		order  = " ORDER BY created_at, id"
+		offset = " OFFSET 0"
+		limit  = " LIMIT 10"
+		params = map[string]interface{}{}
+	)
+
+	query = `
+		SELECT
+			COUNT(*) OVER(),
+			id,
+			branch_id,
+			product_id,
+			min_count,
+			max_count,
+			created_at,
+			updated_at
+		FROM stock_level
+	`
+
+	if req.Offset > 0 {
+		offset = fmt.Sprintf(" OFFSET %d", req.Offset)
+	}
+
+	if req.Limit > 0 {
+		limit = fmt.Sprintf(" LIMIT %d", req.Limit)
+	}
+
+	if req.BranchId != "" {
+		where += " AND branch_id = :branch_id"
+		params["branch_id"] = req.BranchId
+	}
+
+	if req.BranchIds != nil {
+		where += " AND branch_id = ANY(:scope::UUID[])"
+		params["scope"] = req.BranchIds
+	}
+
+	if req.ProductId != "" {
+		where += " AND product_id = :product_id"
+		params["product_id"] = req.ProductId
+	}
+
+	query += where + order + offset + limit
+
+	query, args := helper.ReplaceQueryParams(query, params)
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			id        sql.NullString
+			branchId  sql.NullString
+			productId sql.NullString
+			minCount  sql.NullInt32
+			maxCount  sql.NullInt32
+			createdAt sql.NullString
+			updatedAt sql.NullString
+		)
+
+		err := rows.Scan(
+			&resp.Count,
+			&id,
+			&branchId,
+			&productId,
+			&minCount,
+			&maxCount,
+			&createdAt,
+			&updatedAt,
+		)
+
+		if err != nil {
+			return nil, mapError(err)
+		}
+
+		resp.StockLevels = append(resp.StockLevels, &models.StockLevel{
+			Id:        id.String,
+			BranchId:  branchId.String,
+			ProductId: productId.String,
+			MinCount:  minCount.Int32,
+			MaxCount:  maxCount.Int32,
+			CreatedAt: createdAt.String,
+			UpdatedAt: updatedAt.String,
+		})
+	}
+
+	return resp, nil
+}
+
+func (r *StockLevelRepo) Delete(ctx context.Context, req *models.StockLevelPrimaryKey) error {
+
+	_, err := r.db.Exec(ctx, "DELETE FROM stock_level WHERE id = $1", req.Id)
+	if err != nil {
+		return mapError(err)
+	}
+
+	return nil
+}
@@ -5,12 +5,17 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"strings"
 
 	uuid "github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
 	"github.com/jackc/pgx/v4/pgxpool"
 
 	"market/api/models"
+	"market/pkg/apperr"
+	"market/pkg/audit"
 	"market/pkg/helper"
+	"market/storage"
 )
 
 type StorageComingRepo struct {
@@ -30,19 +35,125 @@ func (r *StorageComingRepo) Create(ctx context.Context, req *models.CreateStorag
 		query string
 	)
 
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return "", mapError(err)
+	}
+	defer tx.Rollback(ctx)
+
 	query = `
 		INSERT INTO storage_coming(id, coming_id, branch_id, date_time, updated_at)
 		VALUES ($1, $2, $3, NOW(), NOW())
 	`
 
-	_, err := r.db.Exec(ctx, query,
+	_, err = tx.Exec(ctx, query,
 		id,
 		req.ComingId,
 		helper.NewNullString(req.BranchId),
 	)
 
 	if err != nil {
-		return "", err
+		return "", mapError(err)
+	}
+
+	err = addStorageComingTransition(ctx, tx, id, "", models.StorageComingStatusDraft, "")
+	if err != nil {
+		return "", mapError(err)
+	}
+
+	err = recordChange(ctx, tx, "storage_coming", "storage_coming", id, nil)
+	if err != nil {
+		return "", mapError(err)
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return "", mapError(err)
+	}
+
+	return id, nil
+}
+
+// CreateWithProducts creates a receipt together with all of its lines in one
+// transaction, as needed when a whole supplier invoice is imported.
+func (r *StorageComingRepo) CreateWithProducts(ctx context.Context, req *models.CreateStorageComingWithProducts) (string, error) {
+
+	var (
+		id    = uuid.New().String()
+		query string
+	)
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return "", mapError(err)
+	}
+	defer tx.Rollback(ctx)
+
+	query = `
+		INSERT INTO storage_coming(id, coming_id, branch_id, date_time, updated_at)
+		VALUES ($1, $2, $3, NOW(), NOW())
+	`
+
+	_, err = tx.Exec(ctx, query,
+		id,
+		req.StorageComing.ComingId,
+		helper.NewNullString(req.StorageComing.BranchId),
+	)
+	if err != nil {
+		return "", mapError(err)
+	}
+
+	err = addStorageComingTransition(ctx, tx, id, "", models.StorageComingStatusDraft, "")
+	if err != nil {
+		return "", mapError(err)
+	}
+
+	err = recordChange(ctx, tx, "storage_coming", "storage_coming", id, nil)
+	if err != nil {
+		return "", mapError(err)
+	}
+
+	query = `
+		INSERT INTO income_products(id, name, quantity, price, total_price, category_id, product_id, barcode, batch, expiry_date, storage_coming_id, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, NOW())
+	`
+
+	for _, product := range req.Products {
+		lineId := uuid.New().String()
+
+		_, err = tx.Exec(ctx, query,
+			lineId,
+			product.Name,
+			product.Quantity,
+			product.Price,
+			product.Price*product.Quantity,
+			helper.NewNullString(product.CategoryId),
+			helper.NewNullString(product.ProductId),
+			helper.NewNullString(product.Barcode),
+			helper.NewNullString(product.Batch),
+			helper.NewNullString(product.ExpiryDate),
+			id,
+		)
+		if err != nil {
+			return "", mapError(err)
+		}
+
+		if len(product.Serials) > 0 {
+			err = setIncomeSerials(ctx, tx, lineId, product.ProductId, product.Serials)
+			if err != nil {
+				return "", mapError(err)
+			}
+		}
+
+		err = recordChange(ctx, tx, "storage_coming_product", "income_products", lineId, nil)
+		if err != nil {
+			return "", mapError(err)
+		}
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return "", mapError(err)
 	}
 
 	return id, nil
@@ -58,8 +169,10 @@ func (r *StorageComingRepo) GetByID(ctx context.Context, req *models.StorageComi
 		branchId  sql.NullString
 		status    sql.NullString
 		datetime  sql.NullString
+		version   sql.NullInt32
 		createdAt sql.NullString
 		updatedAt sql.NullString
+		deletedAt sql.NullString
 	)
 
 	query = `
@@ -69,24 +182,33 @@ func (r *StorageComingRepo) GetByID(ctx context.Context, req *models.StorageComi
 			branch_id,
 			status,
 			date_time,
+			version,
 			created_at,
-			updated_at
+			updated_at,
+			deleted_at
 		FROM storage_coming
-		WHERE id = $1
+		WHERE id = $1 AND (deleted_at IS NULL OR $2)
 	`
 
-	err := r.db.QueryRow(ctx, query, req.Id).Scan(
+	err := r.db.QueryRow(ctx, query, req.Id, req.IncludeDeleted).Scan(
 		&id,
 		&comingId,
 		&branchId,
 		&status,
 		&datetime,
+		&version,
 		&createdAt,
 		&updatedAt,
+		&deletedAt,
 	)
 
 	if err != nil {
-		return nil, err
+		return nil, mapError(err)
+	}
+
+	history, err := r.getHistory(ctx, id.String)
+	if err != nil {
+		return nil, mapError(err)
 	}
 
 	return &models.StorageComing{
@@ -95,8 +217,11 @@ func (r *StorageComingRepo) GetByID(ctx context.Context, req *models.StorageComi
 		BranchId:  branchId.String,
 		Status:    status.String,
 		DateTime:  datetime.String,
+		Version:   int(version.Int32),
 		CreatedAt: createdAt.String,
 		UpdatedAt: updatedAt.String,
+		DeletedAt: deletedAt.String,
+		History:   history,
 	}, nil
 }
 
@@ -106,8 +231,10 @@ func (r *StorageComingRepo) GetList(ctx context.Context, req *models.StorageComi
 		resp   = &models.StorageComingGetListResponse{}
 		query  string
 		where  = " WHERE TRUE"
+		order  = " ORDER BY created_at, id"
 		offset = " OFFSET 0"
 		limit  = " LIMIT 10"
+		params = map[string]interface{}{}
 	)
 
 	query = `
@@ -118,8 +245,10 @@ func (r *StorageComingRepo) GetList(ctx context.Context, req *models.StorageComi
 			branch_id,
 			status,
 			date_time,
+			version,
 			created_at,
-			updated_at
+			updated_at,
+			deleted_at
 		FROM storage_coming
 	`
 
@@ -132,15 +261,28 @@ func (r *StorageComingRepo) GetList(ctx context.Context, req *models.StorageComi
 	}
 
 	if req.Search != "" {
-		where += ` AND title ILIKE '%' || '` + req.Search + `' || '%'`
+		where += ` AND coming_id ILIKE '%' || :search || '%'`
+		params["search"] = req.Search
+	}
+
+	if req.BranchIds != nil {
+		where += " AND branch_id = ANY(:scope::UUID[])"
+		params["scope"] = req.BranchIds
+	}
+
+	if !req.IncludeDeleted {
+		where += " AND deleted_at IS NULL"
 	}
 
-	query += where + offset + limit
+	query += where + order + offset + limit
+
+	query, args := helper.ReplaceQueryParams(query, params)
 
-	rows, err := r.db.Query(ctx, query)
+	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
-		return nil, err
+		return nil, mapError(err)
 	}
+	defer rows.Close()
 
 	for rows.Next() {
 		var (
@@ -149,8 +291,10 @@ func (r *StorageComingRepo) GetList(ctx context.Context, req *models.StorageComi
 			branchId  sql.NullString
 			status    sql.NullString
 			datetime  sql.NullString
+			version   sql.NullInt32
 			createdAt sql.NullString
 			updatedAt sql.NullString
+			deletedAt sql.NullString
 		)
 
 		err := rows.Scan(
@@ -160,12 +304,14 @@ func (r *StorageComingRepo) GetList(ctx context.Context, req *models.StorageComi
 			&branchId,
 			&status,
 			&datetime,
+			&version,
 			&createdAt,
 			&updatedAt,
+			&deletedAt,
 		)
 
 		if err != nil {
-			return nil, err
+			return nil, mapError(err)
 		}
 
 		resp.StorageComings = append(resp.StorageComings, &models.StorageComing{
@@ -174,70 +320,730 @@ func (r *StorageComingRepo) GetList(ctx context.Context, req *models.StorageComi
 			BranchId:  branchId.String,
 			Status:    status.String,
 			DateTime:  datetime.String,
+			Version:   int(version.Int32),
 			CreatedAt: createdAt.String,
 			UpdatedAt: updatedAt.String,
+			DeletedAt: deletedAt.String,
 		})
 	}
 
-	return resp, nil
+	return resp, rows.Err()
 }
 
+// Update changes the header of a storage coming that is not finished or
+// cancelled yet. A status sent along is carried out as a transition.
 func (r *StorageComingRepo) Update(ctx context.Context, req *models.UpdateStorageComing) (int64, error) {
 
 	var (
-		query  string
-		params map[string]interface{}
+		query    string
+		params   map[string]interface{}
+		status   sql.NullString
+		comingId sql.NullString
+		branchId sql.NullString
+	)
+
+	query = `
+		UPDATE
+			storage_coming
+		SET
+			coming_id = :coming_id,
+			branch_id = :branch_id,
+			version = version + :bump,
+			updated_at = NOW()
+		WHERE id = :id AND deleted_at IS NULL
+	`
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, mapError(err)
+	}
+	defer tx.Rollback(ctx)
+
+	err = tx.QueryRow(ctx,
+		"SELECT status, coming_id, branch_id FROM storage_coming WHERE id = $1 AND deleted_at IS NULL FOR UPDATE",
+		req.Id,
+	).Scan(&status, &comingId, &branchId)
+	if err != nil {
+		return 0, mapError(err)
+	}
+
+	before, err := snapshot(ctx, tx, "storage_coming", req.Id)
+	if err != nil {
+		return 0, mapError(err)
+	}
+
+	err = checkVersion(before, req.Version)
+	if err != nil {
+		return 0, mapError(err)
+	}
+
+	var (
+		statusChanged = req.Status != "" && req.Status != status.String
+		fieldsChanged = req.ComingId != comingId.String || req.BranchId != branchId.String
+	)
+
+	// A finished or cancelled receipt may still be sent back whole with a
+	// new status, as long as nothing else changes.
+	if !models.StorageComingEditable(status.String) {
+		if fieldsChanged || !statusChanged {
+			return 0, storageComingLocked(status.String)
+		}
+	} else {
+		params = map[string]interface{}{
+			"id":        req.Id,
+			"coming_id": req.ComingId,
+			"branch_id": helper.NewNullString(req.BranchId),
+			"bump":      1,
+		}
+
+		// The transition counts as the one change of the version.
+		if statusChanged {
+			params["bump"] = 0
+		}
+
+		query, args := helper.ReplaceQueryParams(query, params)
+
+		_, err = tx.Exec(ctx, query, args...)
+		if err != nil {
+			return 0, mapError(err)
+		}
+	}
+
+	if statusChanged {
+		err = transitionStorageComing(ctx, tx, req.Id, status.String, req.Status, "")
+		if err != nil {
+			return 0, mapError(err)
+		}
+	}
+
+	err = recordChange(ctx, tx, "storage_coming", "storage_coming", req.Id, before)
+	if err != nil {
+		return 0, mapError(err)
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return 0, mapError(err)
+	}
+
+	return 1, nil
+}
+
+// Transition moves the storage coming to req.Status. Finishing posts its
+// lines to the stock of its branch, cancelling a finished one takes that
+// stock back out.
+func (r *StorageComingRepo) Transition(ctx context.Context, req *models.TransitionStorageComing) (int64, error) {
+
+	var status sql.NullString
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, mapError(err)
+	}
+	defer tx.Rollback(ctx)
+
+	err = tx.QueryRow(ctx, "SELECT status FROM storage_coming WHERE id = $1 AND deleted_at IS NULL FOR UPDATE", req.Id).Scan(&status)
+	if err != nil {
+		return 0, mapError(err)
+	}
+
+	before, err := snapshot(ctx, tx, "storage_coming", req.Id)
+	if err != nil {
+		return 0, mapError(err)
+	}
+
+	err = checkVersion(before, req.Version)
+	if err != nil {
+		return 0, mapError(err)
+	}
+
+	err = transitionStorageComing(ctx, tx, req.Id, status.String, req.Status, req.Note)
+	if err != nil {
+		return 0, mapError(err)
+	}
+
+	err = recordChange(ctx, tx, "storage_coming", "storage_coming", req.Id, before)
+	if err != nil {
+		return 0, mapError(err)
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return 0, mapError(err)
+	}
+
+	return 1, nil
+}
+
+// transitionStorageComing moves the locked storage coming with id from one
+// status to another, guarded by models.StorageComingTransitions, and records
+// the transition.
+func transitionStorageComing(ctx context.Context, tx pgx.Tx, id, from, to, note string) error {
+
+	if _, ok := models.StorageComingTransitions[to]; !ok {
+		return apperr.Validation("invalid status", apperr.FieldError{
+			Field: "status",
+			Message: "expected " + strings.Join([]string{
+				models.StorageComingStatusDraft,
+				models.StorageComingStatusInProcess,
+				models.StorageComingStatusReceived,
+				models.StorageComingStatusFinished,
+			}, ", ") + " or " + models.StorageComingStatusCancelled,
+		})
+	}
+
+	allowed := false
+	for _, status := range models.StorageComingTransitions[from] {
+		allowed = allowed || status == to
+	}
+
+	if !allowed {
+		return apperr.InvalidStateTransition(from, to)
+	}
+
+	query := "UPDATE storage_coming SET status = $2, version = version + 1, updated_at = NOW() WHERE id = $1"
+
+	if to == models.StorageComingStatusFinished {
+		var lines int
+
+		err := tx.QueryRow(ctx, "SELECT COUNT(*) FROM income_products WHERE storage_coming_id = $1 AND deleted_at IS NULL", id).Scan(&lines)
+		if err != nil {
+			return err
+		}
+
+		if lines <= 0 {
+			return apperr.New(apperr.KindInvalidStateTransition, apperr.CodeInvalidStateTransition, "cannot finish a storage coming without lines")
+		}
+
+		query = "UPDATE storage_coming SET status = $2, date_time = NOW(), version = version + 1, updated_at = NOW() WHERE id = $1"
+	}
+
+	_, err := tx.Exec(ctx, query, id, to)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case to == models.StorageComingStatusFinished:
+		err = postStorageComing(ctx, tx, id)
+	case to == models.StorageComingStatusCancelled && from == models.StorageComingStatusFinished:
+		err = reverseStorageComing(ctx, tx, id)
+	}
+	if err != nil {
+		return err
+	}
+
+	return addStorageComingTransition(ctx, tx, id, from, to, note)
+}
+
+func addStorageComingTransition(ctx context.Context, db execer, storageComingId, from, to, note string) error {
+
+	actor := audit.ActorFrom(ctx)
+
+	_, err := db.Exec(ctx, `
+		INSERT INTO storage_coming_transition(id, storage_coming_id, from_status, to_status, actor_type, actor_id, note)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`,
+		uuid.New().String(),
+		storageComingId,
+		helper.NewNullString(from),
+		to,
+		actor.Type,
+		helper.NewNullString(actor.Id),
+		helper.NewNullString(note),
 	)
 
-	if req.Status == "in process" {
-		query = `
-			UPDATE
-				storage_coming
+	return err
+}
+
+// storageComingLocked is the error for changes to a finished or cancelled
+// storage coming, or to its lines.
+func storageComingLocked(status string) error {
+	return apperr.New(apperr.KindInvalidStateTransition, "storage_coming_locked", "storage coming is "+status+" and can no longer be changed")
+}
+
+// checkStorageComingEditable fails when the lines of the storage coming can
+// no longer change. The share lock keeps its status until tx is done.
+func checkStorageComingEditable(ctx context.Context, tx pgx.Tx, storageComingId string) error {
+
+	var status sql.NullString
+
+	if storageComingId == "" {
+		return nil
+	}
+
+	err := tx.QueryRow(ctx, "SELECT status FROM storage_coming WHERE id = $1 AND deleted_at IS NULL FOR SHARE", storageComingId).Scan(&status)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return apperr.NotFound("storage coming " + storageComingId + " not found")
+	} else if err != nil {
+		return err
+	}
+
+	if !models.StorageComingEditable(status.String) {
+		return storageComingLocked(status.String)
+	}
+
+	return nil
+}
+
+// checkLineEditable is checkStorageComingEditable for the storage coming the
+// line with lineId is on.
+func checkLineEditable(ctx context.Context, tx pgx.Tx, lineId string) error {
+
+	var storageComingId sql.NullString
+
+	err := tx.QueryRow(ctx, "SELECT storage_coming_id FROM income_products WHERE id = $1", lineId).Scan(&storageComingId)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return apperr.NotFound("storage coming product " + lineId + " not found")
+	} else if err != nil {
+		return err
+	}
+
+	return checkStorageComingEditable(ctx, tx, storageComingId.String)
+}
+
+func (r *StorageComingRepo) getHistory(ctx context.Context, storageComingId string) ([]*models.StorageComingTransition, error) {
+
+	var history []*models.StorageComingTransition
+
+	query := `
+		SELECT
+			id,
+			from_status,
+			to_status,
+			actor_type,
+			actor_id,
+			note,
+			created_at
+		FROM storage_coming_transition
+		WHERE storage_coming_id = $1
+		ORDER BY created_at, id
+	`
+
+	rows, err := r.db.Query(ctx, query, storageComingId)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			id         sql.NullString
+			fromStatus sql.NullString
+			toStatus   sql.NullString
+			actorType  sql.NullString
+			actorId    sql.NullString
+			note       sql.NullString
+			createdAt  sql.NullString
+		)
+
+		err := rows.Scan(
+			&id,
+			&fromStatus,
+			&toStatus,
+			&actorType,
+			&actorId,
+			&note,
+			&createdAt,
+		)
+
+		if err != nil {
+			return nil, mapError(err)
+		}
+
+		history = append(history, &models.StorageComingTransition{
+			Id:         id.String,
+			FromStatus: fromStatus.String,
+			ToStatus:   toStatus.String,
+			ActorType:  actorType.String,
+			ActorId:    actorId.String,
+			Note:       note.String,
+			CreatedAt:  createdAt.String,
+		})
+	}
+
+	return history, rows.Err()
+}
+
+// postStorageComing adds the lines of a finished receipt to the stock of its
+// branch: the remaining count of every product and one remaining_batch per
+// line, which is what FEFO consumption takes from.
+func postStorageComing(ctx context.Context, tx pgx.Tx, storageComingId string) error {
+
+	type line struct {
+		id         string
+		branchId   string
+		productId  string
+		quantity   float64
+		batch      sql.NullString
+		expiryDate sql.NullString
+	}
+
+	var lines []*line
+
+	rows, err := tx.Query(ctx, `
+		SELECT
+			ip.id,
+			sc.branch_id,
+			ip.product_id,
+			COALESCE(ip.quantity, 0)::FLOAT8,
+			ip.batch,
+			ip.expiry_date::TEXT
+		FROM income_products AS ip
+		JOIN storage_coming AS sc ON sc.id = ip.storage_coming_id
+		WHERE ip.storage_coming_id = $1 AND ip.product_id IS NOT NULL AND sc.branch_id IS NOT NULL AND ip.deleted_at IS NULL
+	`, storageComingId)
+	if err != nil {
+		return err
+	}
+
+	for rows.Next() {
+		var l line
+
+		err = rows.Scan(&l.id, &l.branchId, &l.productId, &l.quantity, &l.batch, &l.expiryDate)
+		if err != nil {
+			rows.Close()
+			return err
+		}
+
+		lines = append(lines, &l)
+	}
+	rows.Close()
+
+	if rows.Err() != nil {
+		return rows.Err()
+	}
+
+	for _, l := range lines {
+		// The pair is unique, so receipts finishing at the same time add up
+		// on one row instead of each inserting its own.
+		_, err = tx.Exec(ctx, `
+			INSERT INTO remaining(id, branch_id, category_id, product_id, name, price, barcode, count, total_price, updated_at)
+			SELECT $1, $2, p.category_id, p.id, p.name, COALESCE(p.price, 0), p.barcode, $4, $4 * COALESCE(p.price, 0), NOW()
+			FROM product AS p
+			WHERE p.id = $3
+			ON CONFLICT (branch_id, product_id) DO UPDATE
 			SET
-				coming_id = :coming_id,
-				branch_id = :branch_id,
-				status = :status,
+				count = remaining.count + EXCLUDED.count,
+				total_price = (remaining.count + EXCLUDED.count) * remaining.price,
 				updated_at = NOW()
-			WHERE id = :id
-		`
-	} else if req.Status == "fineshed" {
-		query = `
-			UPDATE
-				storage_coming
+		`, uuid.New().String(), l.branchId, l.productId, l.quantity)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(ctx, `
+			INSERT INTO remaining_batch(id, branch_id, product_id, income_product_id, batch, expiry_date, count, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, NOW())
+		`, uuid.New().String(), l.branchId, l.productId, l.id, l.batch, l.expiryDate, l.quantity)
+		if err != nil {
+			return err
+		}
+	}
+
+	return receiveSerialNumbers(ctx, tx, storageComingId)
+}
+
+// receiveSerialNumbers puts the pending units of a finished receipt in stock
+// at its branch.
+func receiveSerialNumbers(ctx context.Context, tx pgx.Tx, storageComingId string) error {
+
+	type unit struct {
+		id       string
+		branchId string
+	}
+
+	var units []*unit
+
+	rows, err := tx.Query(ctx, `
+		UPDATE serial_number AS sn
+		SET
+			status = $2,
+			branch_id = sc.branch_id,
+			updated_at = NOW()
+		FROM income_products AS ip
+		JOIN storage_coming AS sc ON sc.id = ip.storage_coming_id
+		WHERE ip.id = sn.income_product_id AND ip.storage_coming_id = $1 AND sn.status = $3 AND ip.deleted_at IS NULL
+		RETURNING sn.id, sn.branch_id
+	`, storageComingId, models.SerialNumberStatusInStock, models.SerialNumberStatusPending)
+	if err != nil {
+		return err
+	}
+
+	for rows.Next() {
+		var (
+			u        unit
+			branchId sql.NullString
+		)
+
+		err = rows.Scan(&u.id, &branchId)
+		if err != nil {
+			rows.Close()
+			return err
+		}
+
+		u.branchId = branchId.String
+		units = append(units, &u)
+	}
+	rows.Close()
+
+	if rows.Err() != nil {
+		return rows.Err()
+	}
+
+	for _, u := range units {
+		err = addSerialNumberEvent(ctx, tx, u.id, models.SerialNumberActionReceived, models.SerialNumberStatusInStock, u.branchId, "")
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// reverseStorageComing takes the stock a finished receipt posted back out of
+// its branch, and its units back to pending. It fails when some of it was
+// consumed, sold or moved since, as that cannot be taken back.
+func reverseStorageComing(ctx context.Context, tx pgx.Tx, storageComingId string) error {
+
+	type line struct {
+		id        string
+		branchId  string
+		productId string
+		quantity  float64
+	}
+
+	var lines []*line
+
+	rows, err := tx.Query(ctx, `
+		SELECT
+			ip.id,
+			sc.branch_id,
+			ip.product_id,
+			COALESCE(ip.quantity, 0)::FLOAT8
+		FROM income_products AS ip
+		JOIN storage_coming AS sc ON sc.id = ip.storage_coming_id
+		WHERE ip.storage_coming_id = $1 AND ip.product_id IS NOT NULL AND sc.branch_id IS NOT NULL AND ip.deleted_at IS NULL
+	`, storageComingId)
+	if err != nil {
+		return err
+	}
+
+	for rows.Next() {
+		var l line
+
+		err = rows.Scan(&l.id, &l.branchId, &l.productId, &l.quantity)
+		if err != nil {
+			rows.Close()
+			return err
+		}
+
+		lines = append(lines, &l)
+	}
+	rows.Close()
+
+	if rows.Err() != nil {
+		return rows.Err()
+	}
+
+	for _, l := range lines {
+		var batchCount sql.NullFloat64
+
+		// Receipts finished before batches were tracked have none.
+		err = tx.QueryRow(ctx, "SELECT count::FLOAT8 FROM remaining_batch WHERE income_product_id = $1 FOR UPDATE", l.id).Scan(&batchCount)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return err
+		}
+
+		if batchCount.Valid {
+			if batchCount.Float64 < l.quantity {
+				return fmt.Errorf("%w: stock of line %s was already consumed", storage.ErrNotEnoughStock, l.id)
+			}
+
+			_, err = tx.Exec(ctx, "UPDATE remaining_batch SET count = count - $2, updated_at = NOW() WHERE income_product_id = $1", l.id, l.quantity)
+			if err != nil {
+				return err
+			}
+		}
+
+		result, err := tx.Exec(ctx, `
+			UPDATE remaining
 			SET
-				coming_id = :coming_id,
-				branch_id = :branch_id,
-				status = :status,
-				date_time = NOW(),
+				count = count - $3,
+				total_price = (count - $3) * price,
 				updated_at = NOW()
-			WHERE id = :id
-	`
-	} else {
-		return 0, errors.New("There is no such status set status in process or finished !")
+			WHERE branch_id = $1 AND product_id = $2 AND count >= $3
+		`, l.branchId, l.productId, l.quantity)
+		if err != nil {
+			return err
+		}
+
+		if result.RowsAffected() == 0 {
+			return fmt.Errorf("%w: stock of line %s was already consumed", storage.ErrNotEnoughStock, l.id)
+		}
 	}
-	params = map[string]interface{}{
-		"id":        req.Id,
-		"coming_id": req.ComingId,
-		"status":    req.Status,
-		"branch_id": helper.NewNullString(req.BranchId),
+
+	var moved int
+
+	err = tx.QueryRow(ctx, `
+		SELECT COUNT(*)
+		FROM serial_number AS sn
+		JOIN income_products AS ip ON ip.id = sn.income_product_id
+		JOIN storage_coming AS sc ON sc.id = ip.storage_coming_id
+		WHERE ip.storage_coming_id = $1 AND ip.deleted_at IS NULL AND (sn.status <> $2 OR sn.branch_id IS DISTINCT FROM sc.branch_id)
+	`, storageComingId, models.SerialNumberStatusInStock).Scan(&moved)
+	if err != nil {
+		return err
 	}
 
-	query, args := helper.ReplaceQueryParams(query, params)
+	if moved > 0 {
+		return fmt.Errorf("%w: %d units of the storage coming were sold or moved", storage.ErrInvalidState, moved)
+	}
+
+	var units []string
 
-	result, err := r.db.Exec(ctx, query, args...)
+	rows, err = tx.Query(ctx, `
+		UPDATE serial_number AS sn
+		SET
+			status = $2,
+			branch_id = NULL,
+			updated_at = NOW()
+		FROM income_products AS ip
+		WHERE ip.id = sn.income_product_id AND ip.storage_coming_id = $1 AND ip.deleted_at IS NULL
+		RETURNING sn.id
+	`, storageComingId, models.SerialNumberStatusPending)
 	if err != nil {
-		return 0, err
+		return err
+	}
+
+	for rows.Next() {
+		var id string
+
+		err = rows.Scan(&id)
+		if err != nil {
+			rows.Close()
+			return err
+		}
+
+		units = append(units, id)
+	}
+	rows.Close()
+
+	if rows.Err() != nil {
+		return rows.Err()
 	}
 
-	return result.RowsAffected(), nil
+	for _, id := range units {
+		err = addSerialNumberEvent(ctx, tx, id, models.SerialNumberActionCancel, models.SerialNumberStatusPending, "", "")
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
+// Delete deletes the storage coming together with its lines. Finished ones
+// are cancelled first.
 func (r *StorageComingRepo) Delete(ctx context.Context, req *models.StorageComingPrimaryKey) error {
 
-	_, err := r.db.Exec(ctx, "DELETE FROM storage_coming WHERE id = $1", req.Id)
+	tx, err := r.db.Begin(ctx)
 	if err != nil {
-		return err
+		return mapError(err)
 	}
+	defer tx.Rollback(ctx)
 
-	return nil
+	var status sql.NullString
+
+	err = tx.QueryRow(ctx, "SELECT status FROM storage_coming WHERE id = $1 AND deleted_at IS NULL FOR UPDATE", req.Id).Scan(&status)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return mapError(err)
+	}
+
+	// Its stock would stay posted with nothing to show where it came from.
+	if status.String == models.StorageComingStatusFinished {
+		return apperr.New(apperr.KindInvalidStateTransition, "storage_coming_locked", "a finished storage coming has to be cancelled before it is deleted")
+	}
+
+	err = softDelete(ctx, tx, "storage_coming", "storage_coming", req.Id)
+	if err != nil {
+		return mapError(err)
+	}
+
+	lineIds, err := storageComingLines(ctx, tx, req.Id, "deleted_at IS NULL")
+	if err != nil {
+		return mapError(err)
+	}
+
+	for _, lineId := range lineIds {
+		err = softDelete(ctx, tx, "storage_coming_product", "income_products", lineId)
+		if err != nil {
+			return mapError(err)
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// Restore restores the storage coming and the lines deleted with it.
+func (r *StorageComingRepo) Restore(ctx context.Context, req *models.StorageComingPrimaryKey) (int64, error) {
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, mapError(err)
+	}
+	defer tx.Rollback(ctx)
+
+	lineIds, err := storageComingLines(ctx, tx, req.Id, "deleted_at = (SELECT deleted_at FROM storage_coming WHERE id = $1)")
+	if err != nil {
+		return 0, mapError(err)
+	}
+
+	rowsAffected, err := restore(ctx, tx, "storage_coming", "storage_coming", req.Id)
+	if err != nil || rowsAffected <= 0 {
+		return 0, mapError(err)
+	}
+
+	for _, lineId := range lineIds {
+		_, err = restore(ctx, tx, "storage_coming_product", "income_products", lineId)
+		if err != nil {
+			return 0, mapError(err)
+		}
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return 0, mapError(err)
+	}
+
+	return rowsAffected, nil
+}
+
+// Purge removes storage comings deleted more than days ago.
+func (r *StorageComingRepo) Purge(ctx context.Context, days int) (int64, error) {
+	return purgeDeleted(ctx, r.db, "storage_coming", "storage_coming", days)
+}
+
+func storageComingLines(ctx context.Context, tx pgx.Tx, storageComingId, condition string) ([]string, error) {
+
+	var ids []string
+
+	rows, err := tx.Query(ctx, "SELECT id FROM income_products WHERE storage_coming_id = $1 AND "+condition, storageComingId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id string
+
+		err = rows.Scan(&id)
+		if err != nil {
+			return nil, err
+		}
+
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
 }
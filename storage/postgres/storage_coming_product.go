@@ -6,6 +6,7 @@ import (
 	"fmt"
 
 	uuid "github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
 	"github.com/jackc/pgx/v4/pgxpool"
 
 	"market/api/models"
@@ -30,28 +31,83 @@ func (r *StorageComingProductRepo) Create(ctx context.Context, req *models.Creat
 		totalprice = req.Price * req.Quantity
 	)
 
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return "", mapError(err)
+	}
+	defer tx.Rollback(ctx)
+
+	err = checkStorageComingEditable(ctx, tx, req.StorageComingId)
+	if err != nil {
+		return "", mapError(err)
+	}
+
 	query = `
-		INSERT INTO income_products(id, name, quantity, price, total_price, category_id, storage_coming_id, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, NOW())
+		INSERT INTO income_products(id, name, quantity, price, total_price, category_id, product_id, barcode, batch, expiry_date, storage_coming_id, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, NOW())
 	`
 
-	_, err := r.db.Exec(ctx, query,
+	_, err = tx.Exec(ctx, query,
 		id,
 		req.Name,
 		req.Quantity,
 		req.Price,
 		totalprice,
 		helper.NewNullString(req.CategoryId),
+		helper.NewNullString(req.ProductId),
+		helper.NewNullString(req.Barcode),
+		helper.NewNullString(req.Batch),
+		helper.NewNullString(req.ExpiryDate),
 		helper.NewNullString(req.StorageComingId),
 	)
 
 	if err != nil {
-		return "", err
+		return "", mapError(err)
+	}
+
+	err = setIncomeSerials(ctx, tx, id, req.ProductId, req.Serials)
+	if err != nil {
+		return "", mapError(err)
+	}
+
+	err = recordChange(ctx, tx, "storage_coming_product", "income_products", id, nil)
+	if err != nil {
+		return "", mapError(err)
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return "", mapError(err)
 	}
 
 	return id, nil
 }
 
+// setIncomeSerials replaces the serial numbers captured on a receipt line.
+// Only pending ones are replaced, units already received keep their history.
+func setIncomeSerials(ctx context.Context, tx pgx.Tx, incomeProductId, productId string, serials []string) error {
+
+	_, err := tx.Exec(ctx,
+		"DELETE FROM serial_number WHERE income_product_id = $1 AND status = $2",
+		incomeProductId, models.SerialNumberStatusPending,
+	)
+	if err != nil {
+		return err
+	}
+
+	for _, serial := range serials {
+		_, err = tx.Exec(ctx, `
+			INSERT INTO serial_number(id, product_id, serial, status, income_product_id, updated_at)
+			VALUES ($1, $2, $3, $4, $5, NOW())
+		`, uuid.New().String(), productId, serial, models.SerialNumberStatusPending, incomeProductId)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (r *StorageComingProductRepo) GetByID(ctx context.Context, req *models.StorageComingProductPrimaryKey) (*models.StorageComingProduct, error) {
 
 	var (
@@ -63,40 +119,63 @@ func (r *StorageComingProductRepo) GetByID(ctx context.Context, req *models.Stor
 		Price           sql.NullInt32
 		TotalPrice      sql.NullInt32
 		CategoryId      sql.NullString
+		ProductId       sql.NullString
+		Barcode         sql.NullString
+		Batch           sql.NullString
+		ExpiryDate      sql.NullString
 		StorageComingId sql.NullString
+		Version         sql.NullInt32
 		CreatedAt       sql.NullString
 		UpdatedAt       sql.NullString
+		DeletedAt       sql.NullString
 	)
 
 	query = `
 		SELECT
 			id,
 			name,
-			status,
-			date_time,
+			quantity,
+			price,
 			total_price,
 			category_id,
+			product_id,
+			barcode,
+			batch,
+			expiry_date::TEXT,
 			storage_coming_id,
+			version,
 			created_at,
-			updated_at
+			updated_at,
+			deleted_at
 		FROM income_products
-		WHERE id = $1
+		WHERE id = $1 AND (deleted_at IS NULL OR $2)
 	`
 
-	err := r.db.QueryRow(ctx, query, req.Id).Scan(
+	err := r.db.QueryRow(ctx, query, req.Id, req.IncludeDeleted).Scan(
 		&Id,
 		&Name,
 		&Quantity,
 		&Price,
 		&TotalPrice,
 		&CategoryId,
+		&ProductId,
+		&Barcode,
+		&Batch,
+		&ExpiryDate,
 		&StorageComingId,
+		&Version,
 		&CreatedAt,
 		&UpdatedAt,
+		&DeletedAt,
 	)
 
 	if err != nil {
-		return nil, err
+		return nil, mapError(err)
+	}
+
+	serials, err := r.getSerials(ctx, Id.String)
+	if err != nil {
+		return nil, mapError(err)
 	}
 
 	return &models.StorageComingProduct{
@@ -106,20 +185,53 @@ func (r *StorageComingProductRepo) GetByID(ctx context.Context, req *models.Stor
 		Price:           Price.Int32,
 		TotalPrice:      TotalPrice.Int32,
 		CategoryId:      CategoryId.String,
+		ProductId:       ProductId.String,
+		Barcode:         Barcode.String,
+		Batch:           Batch.String,
+		ExpiryDate:      ExpiryDate.String,
+		Serials:         serials,
 		StorageComingId: StorageComingId.String,
+		Version:         int(Version.Int32),
 		CreatedAt:       CreatedAt.String,
 		UpdatedAt:       UpdatedAt.String,
+		DeletedAt:       DeletedAt.String,
 	}, nil
 }
 
+func (r *StorageComingProductRepo) getSerials(ctx context.Context, incomeProductId string) ([]string, error) {
+
+	var serials []string
+
+	rows, err := r.db.Query(ctx, "SELECT serial FROM serial_number WHERE income_product_id = $1 ORDER BY serial", incomeProductId)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var serial string
+
+		err := rows.Scan(&serial)
+		if err != nil {
+			return nil, mapError(err)
+		}
+
+		serials = append(serials, serial)
+	}
+
+	return serials, rows.Err()
+}
+
 func (r *StorageComingProductRepo) GetList(ctx context.Context, req *models.StorageComingProductGetListRequest) (*models.StorageComingProductGetListResponse, error) {
 
 	var (
 		resp   = &models.StorageComingProductGetListResponse{}
 		query  string
 		where  = " WHERE TRUE"
+		order  = " ORDER BY created_at, id"
 		offset = " OFFSET 0"
 		limit  = " LIMIT 10"
+		params = map[string]interface{}{}
 	)
 
 	query = `
@@ -127,13 +239,19 @@ func (r *StorageComingProductRepo) GetList(ctx context.Context, req *models.Stor
 			COUNT(*) OVER(),
 			id,
 			name,
-			status,
-			date_time,
+			quantity,
+			price,
 			total_price,
 			category_id,
+			product_id,
+			barcode,
+			batch,
+			expiry_date::TEXT,
 			storage_coming_id,
+			version,
 			created_at,
-			updated_at
+			updated_at,
+			deleted_at
 		FROM income_products
 	`
 
@@ -146,15 +264,33 @@ func (r *StorageComingProductRepo) GetList(ctx context.Context, req *models.Stor
 	}
 
 	if req.Search != "" {
-		where += ` AND title ILIKE '%' || '` + req.Search + `' || '%'`
+		where += ` AND name ILIKE '%' || :search || '%'`
+		params["search"] = req.Search
+	}
+
+	if req.StorageComingId != "" {
+		where += " AND storage_coming_id = :storage_coming_id"
+		params["storage_coming_id"] = req.StorageComingId
 	}
 
-	query += where + offset + limit
+	if req.BranchIds != nil {
+		where += " AND storage_coming_id IN (SELECT id FROM storage_coming WHERE branch_id = ANY(:scope::UUID[]))"
+		params["scope"] = req.BranchIds
+	}
+
+	if !req.IncludeDeleted {
+		where += " AND deleted_at IS NULL"
+	}
+
+	query += where + order + offset + limit
+
+	query, args := helper.ReplaceQueryParams(query, params)
 
-	rows, err := r.db.Query(ctx, query)
+	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
-		return nil, err
+		return nil, mapError(err)
 	}
+	defer rows.Close()
 
 	for rows.Next() {
 		var (
@@ -164,9 +300,15 @@ func (r *StorageComingProductRepo) GetList(ctx context.Context, req *models.Stor
 			Price           sql.NullInt32
 			TotalPrice      sql.NullInt32
 			CategoryId      sql.NullString
+			ProductId       sql.NullString
+			Barcode         sql.NullString
+			Batch           sql.NullString
+			ExpiryDate      sql.NullString
 			StorageComingId sql.NullString
+			Version         sql.NullInt32
 			CreatedAt       sql.NullString
 			UpdatedAt       sql.NullString
+			DeletedAt       sql.NullString
 		)
 
 		err := rows.Scan(
@@ -177,13 +319,19 @@ func (r *StorageComingProductRepo) GetList(ctx context.Context, req *models.Stor
 			&Price,
 			&TotalPrice,
 			&CategoryId,
+			&ProductId,
+			&Barcode,
+			&Batch,
+			&ExpiryDate,
 			&StorageComingId,
+			&Version,
 			&CreatedAt,
 			&UpdatedAt,
+			&DeletedAt,
 		)
 
 		if err != nil {
-			return nil, err
+			return nil, mapError(err)
 		}
 
 		resp.StorageComingProducts = append(resp.StorageComingProducts, &models.StorageComingProduct{
@@ -193,13 +341,19 @@ func (r *StorageComingProductRepo) GetList(ctx context.Context, req *models.Stor
 			Price:           Price.Int32,
 			TotalPrice:      TotalPrice.Int32,
 			CategoryId:      CategoryId.String,
+			ProductId:       ProductId.String,
+			Barcode:         Barcode.String,
+			Batch:           Batch.String,
+			ExpiryDate:      ExpiryDate.String,
 			StorageComingId: StorageComingId.String,
+			Version:         int(Version.Int32),
 			CreatedAt:       CreatedAt.String,
 			UpdatedAt:       UpdatedAt.String,
+			DeletedAt:       DeletedAt.String,
 		})
 	}
 
-	return resp, nil
+	return resp, rows.Err()
 }
 
 func (r *StorageComingProductRepo) Update(ctx context.Context, req *models.UpdateStorageComingProduct) (int64, error) {
@@ -217,11 +371,16 @@ func (r *StorageComingProductRepo) Update(ctx context.Context, req *models.Updat
 			name = :name,
 			quantity = :quantity,
 			price = :price,
-			total_price = :total_price
-			category_id = :category_id
+			total_price = :total_price,
+			category_id = :category_id,
+			product_id = :product_id,
+			barcode = :barcode,
+			batch = :batch,
+			expiry_date = :expiry_date,
 			storage_coming_id = :storage_coming_id,
+			version = version + 1,
 			updated_at = NOW()
-		WHERE id = :id
+		WHERE id = :id AND deleted_at IS NULL
 	`
 
 	params = map[string]interface{}{
@@ -231,14 +390,65 @@ func (r *StorageComingProductRepo) Update(ctx context.Context, req *models.Updat
 		"price":             req.Price,
 		"total_price":       totalprice,
 		"category_id":       helper.NewNullString(req.CategoryId),
+		"product_id":        helper.NewNullString(req.ProductId),
+		"barcode":           helper.NewNullString(req.Barcode),
+		"batch":             helper.NewNullString(req.Batch),
+		"expiry_date":       helper.NewNullString(req.ExpiryDate),
 		"storage_coming_id": helper.NewNullString(req.StorageComingId),
 	}
 
 	query, args := helper.ReplaceQueryParams(query, params)
 
-	result, err := r.db.Exec(ctx, query, args...)
+	tx, err := r.db.Begin(ctx)
 	if err != nil {
-		return 0, err
+		return 0, mapError(err)
+	}
+	defer tx.Rollback(ctx)
+
+	err = checkLineEditable(ctx, tx, req.Id)
+	if err != nil {
+		return 0, mapError(err)
+	}
+
+	err = checkStorageComingEditable(ctx, tx, req.StorageComingId)
+	if err != nil {
+		return 0, mapError(err)
+	}
+
+	before, err := snapshot(ctx, tx, "income_products", req.Id)
+	if err != nil {
+		return 0, mapError(err)
+	}
+
+	err = checkVersion(before, req.Version)
+	if err != nil {
+		return 0, mapError(err)
+	}
+
+	result, err := tx.Exec(ctx, query, args...)
+	if err != nil {
+		return 0, mapError(err)
+	}
+
+	if result.RowsAffected() <= 0 {
+		return 0, mapError(pgx.ErrNoRows)
+	}
+
+	if req.Serials != nil {
+		err = setIncomeSerials(ctx, tx, req.Id, req.ProductId, req.Serials)
+		if err != nil {
+			return 0, mapError(err)
+		}
+	}
+
+	err = recordChange(ctx, tx, "storage_coming_product", "income_products", req.Id, before)
+	if err != nil {
+		return 0, mapError(err)
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return 0, mapError(err)
 	}
 
 	return result.RowsAffected(), nil
@@ -246,10 +456,52 @@ func (r *StorageComingProductRepo) Update(ctx context.Context, req *models.Updat
 
 func (r *StorageComingProductRepo) Delete(ctx context.Context, req *models.StorageComingProductPrimaryKey) error {
 
-	_, err := r.db.Exec(ctx, "DELETE FROM income_products WHERE id = $1", req.Id)
+	tx, err := r.db.Begin(ctx)
 	if err != nil {
-		return err
+		return mapError(err)
 	}
+	defer tx.Rollback(ctx)
 
-	return nil
+	err = checkLineEditable(ctx, tx, req.Id)
+	if err != nil {
+		return mapError(err)
+	}
+
+	err = softDelete(ctx, tx, "storage_coming_product", "income_products", req.Id)
+	if err != nil {
+		return mapError(err)
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *StorageComingProductRepo) Restore(ctx context.Context, req *models.StorageComingProductPrimaryKey) (int64, error) {
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, mapError(err)
+	}
+	defer tx.Rollback(ctx)
+
+	err = checkLineEditable(ctx, tx, req.Id)
+	if err != nil {
+		return 0, mapError(err)
+	}
+
+	rowsAffected, err := restore(ctx, tx, "storage_coming_product", "income_products", req.Id)
+	if err != nil {
+		return 0, mapError(err)
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return 0, mapError(err)
+	}
+
+	return rowsAffected, nil
+}
+
+// Purge removes storage coming lines deleted more than days ago.
+func (r *StorageComingProductRepo) Purge(ctx context.Context, days int) (int64, error) {
+	return purgeDeleted(ctx, r.db, "storage_coming_product", "income_products", days)
 }
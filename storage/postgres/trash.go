@@ -0,0 +1,135 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// softDelete marks the row of table with id deleted, it stays for history
+// and can be restored until it is purged. A row that does not exist or is
+// deleted already is pgx.ErrNoRows.
+func softDelete(ctx context.Context, tx pgx.Tx, entity, table, id string) error {
+
+	before, err := snapshot(ctx, tx, table, id)
+	if err != nil {
+		return err
+	}
+
+	result, err := tx.Exec(ctx, "UPDATE "+table+" SET deleted_at = NOW(), version = version + 1, updated_at = NOW() WHERE id = $1 AND deleted_at IS NULL", id)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() <= 0 {
+		return pgx.ErrNoRows
+	}
+
+	return recordChange(ctx, tx, entity, table, id, before)
+}
+
+// restore brings a deleted row of table with id back. A row that does not
+// exist or is not deleted is pgx.ErrNoRows.
+func restore(ctx context.Context, tx pgx.Tx, entity, table, id string) (int64, error) {
+
+	before, err := snapshot(ctx, tx, table, id)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := tx.Exec(ctx, "UPDATE "+table+" SET deleted_at = NULL, version = version + 1, updated_at = NOW() WHERE id = $1 AND deleted_at IS NOT NULL", id)
+	if err != nil {
+		return 0, err
+	}
+
+	if result.RowsAffected() <= 0 {
+		return 0, pgx.ErrNoRows
+	}
+
+	err = recordChange(ctx, tx, entity, table, id, before)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected(), nil
+}
+
+// purgeDeleted removes the rows of table deleted more than days ago for
+// good, one transaction per row. Rows other records still refer to, like a
+// product with stock history, are kept.
+func purgeDeleted(ctx context.Context, db *pgxpool.Pool, entity, table string, days int) (int64, error) {
+
+	var (
+		ids    []string
+		purged int64
+	)
+
+	rows, err := db.Query(ctx, "SELECT id FROM "+table+" WHERE deleted_at < NOW() - make_interval(days => $1)", days)
+	if err != nil {
+		return 0, err
+	}
+
+	for rows.Next() {
+		var id string
+
+		err = rows.Scan(&id)
+		if err != nil {
+			rows.Close()
+			return 0, err
+		}
+
+		ids = append(ids, id)
+	}
+	rows.Close()
+
+	if err = rows.Err(); err != nil {
+		return 0, err
+	}
+
+	for _, id := range ids {
+		ok, err := purgeRow(ctx, db, entity, table, id)
+		if err != nil {
+			return purged, fmt.Errorf("purge %s %s: %w", entity, id, err)
+		}
+
+		if ok {
+			purged++
+		}
+	}
+
+	return purged, nil
+}
+
+func purgeRow(ctx context.Context, db *pgxpool.Pool, entity, table, id string) (bool, error) {
+
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback(ctx)
+
+	before, err := snapshot(ctx, tx, table, id)
+	if err != nil {
+		return false, err
+	}
+
+	_, err = tx.Exec(ctx, "DELETE FROM "+table+" WHERE id = $1 AND deleted_at IS NOT NULL", id)
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == sqlStateForeignKeyViolation {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	err = recordChange(ctx, tx, entity, table, id, before)
+	if err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit(ctx)
+}
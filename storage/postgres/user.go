@@ -0,0 +1,313 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	uuid "github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+
+	"market/api/models"
+	"market/pkg/helper"
+)
+
+type UserRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewUserRepo(db *pgxpool.Pool) *UserRepo {
+	return &UserRepo{
+		db: db,
+	}
+}
+
+func (r *UserRepo) Create(ctx context.Context, req *models.CreateUser) (string, error) {
+
+	var (
+		id    = uuid.New().String()
+		query string
+	)
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return "", mapError(err)
+	}
+	defer tx.Rollback(ctx)
+
+	query = `
+		INSERT INTO users(id, login, password_hash, full_name, role, updated_at)
+		VALUES ($1, $2, $3, $4, $5, NOW())
+	`
+
+	_, err = tx.Exec(ctx, query,
+		id,
+		req.Login,
+		req.PasswordHash,
+		helper.NewNullString(req.FullName),
+		req.Role,
+	)
+
+	if err != nil {
+		return "", mapError(err)
+	}
+
+	err = setUserBranches(ctx, tx, id, req.BranchIds)
+	if err != nil {
+		return "", mapError(err)
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return "", mapError(err)
+	}
+
+	return id, nil
+}
+
+func setUserBranches(ctx context.Context, tx pgx.Tx, userId string, branchIds []string) error {
+
+	_, err := tx.Exec(ctx, "DELETE FROM user_branch WHERE user_id = $1", userId)
+	if err != nil {
+		return err
+	}
+
+	for _, branchId := range branchIds {
+		_, err = tx.Exec(ctx, "INSERT INTO user_branch(user_id, branch_id) VALUES ($1, $2)", userId, branchId)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *UserRepo) GetByID(ctx context.Context, req *models.UserPrimaryKey) (*models.User, error) {
+	return r.getBy(ctx, "id", req.Id)
+}
+
+func (r *UserRepo) GetByLogin(ctx context.Context, req *models.UserLogin) (*models.User, error) {
+	return r.getBy(ctx, "login", req.Login)
+}
+
+func (r *UserRepo) getBy(ctx context.Context, column, value string) (*models.User, error) {
+
+	var (
+		query string
+
+		id           sql.NullString
+		login        sql.NullString
+		passwordHash sql.NullString
+		fullName     sql.NullString
+		active       sql.NullBool
+		role         sql.NullString
+		branchIds    []string
+		createdAt    sql.NullString
+		updatedAt    sql.NullString
+	)
+
+	query = `
+		SELECT
+			id,
+			login,
+			password_hash,
+			full_name,
+			active,
+			role,
+			ARRAY(SELECT branch_id::TEXT FROM user_branch WHERE user_id = users.id ORDER BY branch_id),
+			created_at,
+			updated_at
+		FROM users
+		WHERE ` + column + ` = $1
+	`
+
+	err := r.db.QueryRow(ctx, query, value).Scan(
+		&id,
+		&login,
+		&passwordHash,
+		&fullName,
+		&active,
+		&role,
+		&branchIds,
+		&createdAt,
+		&updatedAt,
+	)
+
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	return &models.User{
+		Id:           id.String,
+		Login:        login.String,
+		PasswordHash: passwordHash.String,
+		FullName:     fullName.String,
+		Active:       active.Bool,
+		Role:         role.String,
+		BranchIds:    branchIds,
+		CreatedAt:    createdAt.String,
+		UpdatedAt:    updatedAt.String,
+	}, nil
+}
+
+func (r *UserRepo) GetList(ctx context.Context, req *models.UserGetListRequest) (*models.UserGetListResponse, error) {
+
+	var (
+		resp   = &models.UserGetListResponse{}
+		query  string
+		where  = " WHERE TRUE"
+		order  = " ORDER BY created_at, id"
+		offset = " OFFSET 0"
+		limit  = " LIMIT 10"
+		params = map[string]interface{}{}
+	)
+
+	query = `
+		SELECT
+			COUNT(*) OVER(),
+			id,
+			login,
+			full_name,
+			active,
+			role,
+			ARRAY(SELECT branch_id::TEXT FROM user_branch WHERE user_id = users.id ORDER BY branch_id),
+			created_at,
+			updated_at
+		FROM users
+	`
+
+	if req.Offset > 0 {
+		offset = fmt.Sprintf(" OFFSET %d", req.Offset)
+	}
+
+	if req.Limit > 0 {
+		limit = fmt.Sprintf(" LIMIT %d", req.Limit)
+	}
+
+	if req.Search != "" {
+		where += ` AND (login ILIKE '%' || :search || '%' OR full_name ILIKE '%' || :search || '%')`
+		params["search"] = req.Search
+	}
+
+	query += where + order + offset + limit
+
+	query, args := helper.ReplaceQueryParams(query, params)
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			id        sql.NullString
+			login     sql.NullString
+			fullName  sql.NullString
+			active    sql.NullBool
+			role      sql.NullString
+			branchIds []string
+			createdAt sql.NullString
+			updatedAt sql.NullString
+		)
+
+		err := rows.Scan(
+			&resp.Count,
+			&id,
+			&login,
+			&fullName,
+			&active,
+			&role,
+			&branchIds,
+			&createdAt,
+			&updatedAt,
+		)
+
+		if err != nil {
+			return nil, mapError(err)
+		}
+
+		resp.Users = append(resp.Users, &models.User{
+			Id:        id.String,
+			Login:     login.String,
+			FullName:  fullName.String,
+			Active:    active.Bool,
+			Role:      role.String,
+			BranchIds: branchIds,
+			CreatedAt: createdAt.String,
+			UpdatedAt: updatedAt.String,
+		})
+	}
+
+	return resp, nil
+}
+
+func (r *UserRepo) Update(ctx context.Context, req *models.UpdateUser) (int64, error) {
+
+	var (
+		query  string
+		params map[string]interface{}
+	)
+
+	query = `
+		UPDATE
+			users
+		SET
+			login = :login,
+			password_hash = COALESCE(:password_hash, password_hash),
+			full_name = :full_name,
+			active = :active,
+			role = :role,
+			updated_at = NOW()
+		WHERE id = :id
+	`
+
+	params = map[string]interface{}{
+		"id":            req.Id,
+		"login":         req.Login,
+		"password_hash": helper.NewNullString(req.PasswordHash),
+		"full_name":     helper.NewNullString(req.FullName),
+		"active":        req.Active,
+		"role":          req.Role,
+	}
+
+	query, args := helper.ReplaceQueryParams(query, params)
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, mapError(err)
+	}
+	defer tx.Rollback(ctx)
+
+	result, err := tx.Exec(ctx, query, args...)
+	if err != nil {
+		return 0, mapError(err)
+	}
+
+	if result.RowsAffected() <= 0 {
+		return 0, mapError(pgx.ErrNoRows)
+	}
+
+	err = setUserBranches(ctx, tx, req.Id, req.BranchIds)
+	if err != nil {
+		return 0, mapError(err)
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return 0, mapError(err)
+	}
+
+	return result.RowsAffected(), nil
+}
+
+func (r *UserRepo) Delete(ctx context.Context, req *models.UserPrimaryKey) error {
+
+	_, err := r.db.Exec(ctx, "DELETE FROM users WHERE id = $1", req.Id)
+	if err != nil {
+		return mapError(err)
+	}
+
+	return nil
+}
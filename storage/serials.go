@@ -0,0 +1,57 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"market/api/models"
+	"market/pkg/apperr"
+)
+
+// CheckSerials requires one unique serial number per unit on receipt lines of
+// serialized products and none on the others.
+func CheckSerials(ctx context.Context, strg StorageI, productId string, quantity int32, serials []string) error {
+
+	if productId == "" {
+		if len(serials) > 0 {
+			return serialsError("serials need a product_id")
+		}
+		return nil
+	}
+
+	product, err := strg.Product().GetByID(ctx, &models.ProductPrimaryKey{Id: productId})
+	if appErr, ok := apperr.As(err); ok && appErr.Kind == apperr.KindNotFound {
+		return apperr.Validation("invalid request", apperr.FieldError{Field: "product_id", Message: "does not exist"})
+	} else if err != nil {
+		return err
+	}
+
+	if !product.Serialized {
+		if len(serials) > 0 {
+			return serialsError("product " + product.Name + " is not serialized")
+		}
+		return nil
+	}
+
+	if int32(len(serials)) != quantity {
+		return serialsError(fmt.Sprintf("product %s is serialized, expected %d serials, got %d", product.Name, quantity, len(serials)))
+	}
+
+	seen := map[string]bool{}
+	for _, serial := range serials {
+		if serial == "" || len(serial) > 64 {
+			return serialsError("invalid serial: " + serial)
+		}
+
+		if seen[serial] {
+			return serialsError("duplicate serial: " + serial)
+		}
+		seen[serial] = true
+	}
+
+	return nil
+}
+
+func serialsError(message string) error {
+	return apperr.Validation("invalid request", apperr.FieldError{Field: "serials", Message: message})
+}
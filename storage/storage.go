@@ -2,17 +2,45 @@ package storage
 
 import (
 	"context"
+	"errors"
 
 	"market/api/models"
+	"market/pkg/apperr"
 )
 
+// ErrNotEnoughStock is returned when more is taken from a branch stock than
+// it holds.
+var ErrNotEnoughStock = apperr.New(apperr.KindConflict, "not_enough_stock", "not enough stock")
+
+// ErrInvalidToken is returned for unknown, expired or revoked refresh tokens.
+var ErrInvalidToken = errors.New("invalid or expired token")
+
+// ErrInvalidState is returned when an operation is not allowed in the
+// current state of a record.
+var ErrInvalidState = apperr.New(apperr.KindInvalidStateTransition, apperr.CodeInvalidStateTransition, "invalid state")
+
+// ErrVersionConflict is returned when a record was changed by someone else
+// since the version the caller read.
+var ErrVersionConflict = apperr.New(apperr.KindConflict, "version_conflict", "version conflict")
+
 type StorageI interface {
 	Close()
 	Branch() BranchRepoI
 	Category() CategoryRepoI
 	Product() ProductRepoI
+	ProductBarcode() ProductBarcodeRepoI
+	ProductImage() ProductImageRepoI
 	StorageComing() StorageComingRepoI
 	StorageComingProduct() StorageComingProductRepoI
+	Remaining() RemainingRepoI
+	StockLevel() StockLevelRepoI
+	SerialNumber() SerialNumberRepoI
+	User() UserRepoI
+	Session() SessionRepoI
+	ApiKey() ApiKeyRepoI
+	AuditLog() AuditLogRepoI
+	IdempotencyKey() IdempotencyKeyRepoI
+	Report() ReportRepoI
 }
 
 type BranchRepoI interface {
@@ -21,6 +49,8 @@ type BranchRepoI interface {
 	GetList(context.Context, *models.BranchGetListRequest) (*models.BranchGetListResponse, error)
 	Update(context.Context, *models.UpdateBranch) (int64, error)
 	Delete(context.Context, *models.BranchPrimaryKey) error
+	Restore(context.Context, *models.BranchPrimaryKey) (int64, error)
+	Purge(ctx context.Context, days int) (int64, error)
 }
 
 type CategoryRepoI interface {
@@ -29,6 +59,8 @@ type CategoryRepoI interface {
 	GetList(context.Context, *models.CategoryGetListRequest) (*models.CategoryGetListResponse, error)
 	Update(context.Context, *models.UpdateCategory) (int64, error)
 	Delete(context.Context, *models.CategoryPrimaryKey) error
+	Restore(context.Context, *models.CategoryPrimaryKey) (int64, error)
+	Purge(ctx context.Context, days int) (int64, error)
 }
 
 type ProductRepoI interface {
@@ -38,15 +70,112 @@ type ProductRepoI interface {
 	Update(context.Context, *models.UpdateProduct) (int64, error)
 	Patch(context.Context, *models.PatchRequest) (int64, error)
 	Delete(context.Context, *models.ProductPrimaryKey) error
+	Restore(context.Context, *models.ProductPrimaryKey) (int64, error)
+	Purge(ctx context.Context, days int) (int64, error)
+	GetByBarcode(context.Context, *models.ProductBarcodeLookup) (*models.ProductBarcodeLookupResponse, error)
+	Import(context.Context, *models.ProductImport) (*models.ProductImportResponse, error)
+}
+
+type ProductBarcodeRepoI interface {
+	Create(context.Context, *models.CreateProductBarcode) (string, error)
+	GetByID(context.Context, *models.ProductBarcodePrimaryKey) (*models.ProductBarcode, error)
+	GetList(context.Context, *models.ProductBarcodeGetListRequest) (*models.ProductBarcodeGetListResponse, error)
+	Delete(context.Context, *models.ProductBarcodePrimaryKey) error
+	Generate(context.Context, *models.GenerateBarcode) (string, error)
+}
+
+type ProductImageRepoI interface {
+	Create(context.Context, *models.CreateProductImage) (string, error)
+	GetByID(context.Context, *models.ProductImagePrimaryKey) (*models.ProductImage, error)
+	GetList(context.Context, *models.ProductImageGetListRequest) (*models.ProductImageGetListResponse, error)
+	Reorder(context.Context, *models.ProductImageOrder) (int64, error)
+	Delete(context.Context, *models.ProductImagePrimaryKey) error
 }
 
 type StorageComingRepoI interface {
 	Create(context.Context, *models.CreateStorageComing) (string, error)
+	CreateWithProducts(context.Context, *models.CreateStorageComingWithProducts) (string, error)
 	GetByID(context.Context, *models.StorageComingPrimaryKey) (*models.StorageComing, error)
 	GetList(context.Context, *models.StorageComingGetListRequest) (*models.StorageComingGetListResponse, error)
 	Update(context.Context, *models.UpdateStorageComing) (int64, error)
+	Transition(context.Context, *models.TransitionStorageComing) (int64, error)
 	Delete(context.Context, *models.StorageComingPrimaryKey) error
+	Restore(context.Context, *models.StorageComingPrimaryKey) (int64, error)
+	Purge(ctx context.Context, days int) (int64, error)
 }
 
 type StorageComingProductRepoI interface {
+	Create(context.Context, *models.CreateStorageComingProduct) (string, error)
+	GetByID(context.Context, *models.StorageComingProductPrimaryKey) (*models.StorageComingProduct, error)
+	GetList(context.Context, *models.StorageComingProductGetListRequest) (*models.StorageComingProductGetListResponse, error)
+	Update(context.Context, *models.UpdateStorageComingProduct) (int64, error)
+	Delete(context.Context, *models.StorageComingProductPrimaryKey) error
+	Restore(context.Context, *models.StorageComingProductPrimaryKey) (int64, error)
+	Purge(ctx context.Context, days int) (int64, error)
+}
+
+type RemainingRepoI interface {
+	GetByID(context.Context, *models.RemainingPrimaryKey) (*models.Remaining, error)
+	GetList(context.Context, *models.RemainingGetListRequest) (*models.RemainingGetListResponse, error)
+	Snapshot(context.Context) (int64, error)
+	GetBatchList(context.Context, *models.RemainingBatchGetListRequest) (*models.RemainingBatchGetListResponse, error)
+	Consume(context.Context, *models.ConsumeRemaining) (*models.ConsumeRemainingResponse, error)
+}
+
+type StockLevelRepoI interface {
+	Create(context.Context, *models.CreateStockLevel) (string, error)
+	GetByID(context.Context, *models.StockLevelPrimaryKey) (*models.StockLevel, error)
+	GetList(context.Context, *models.StockLevelGetListRequest) (*models.StockLevelGetListResponse, error)
+	Delete(context.Context, *models.StockLevelPrimaryKey) error
+}
+
+type SerialNumberRepoI interface {
+	GetByID(context.Context, *models.SerialNumberPrimaryKey) (*models.SerialNumber, error)
+	GetList(context.Context, *models.SerialNumberGetListRequest) (*models.SerialNumberGetListResponse, error)
+	Move(context.Context, *models.MoveSerialNumber) (int64, error)
+}
+
+type UserRepoI interface {
+	Create(context.Context, *models.CreateUser) (string, error)
+	GetByID(context.Context, *models.UserPrimaryKey) (*models.User, error)
+	GetByLogin(context.Context, *models.UserLogin) (*models.User, error)
+	GetList(context.Context, *models.UserGetListRequest) (*models.UserGetListResponse, error)
+	Update(context.Context, *models.UpdateUser) (int64, error)
+	Delete(context.Context, *models.UserPrimaryKey) error
+}
+
+type SessionRepoI interface {
+	Create(context.Context, *models.CreateSession) (string, error)
+	Rotate(context.Context, *models.RotateSession) (*models.Session, error)
+	RevokeFamily(ctx context.Context, familyId string) error
+	Active(ctx context.Context, familyId string) (bool, error)
+}
+
+type ApiKeyRepoI interface {
+	Create(context.Context, *models.CreateApiKey) (string, error)
+	GetByID(context.Context, *models.ApiKeyPrimaryKey) (*models.ApiKey, error)
+	GetByHash(context.Context, *models.ApiKeyHash) (*models.ApiKey, error)
+	GetList(context.Context, *models.ApiKeyGetListRequest) (*models.ApiKeyGetListResponse, error)
+	Rotate(context.Context, *models.RotateApiKey) (int64, error)
+	Touch(context.Context, *models.ApiKeyPrimaryKey) error
+	Revoke(context.Context, *models.ApiKeyPrimaryKey) (int64, error)
+}
+
+type AuditLogRepoI interface {
+	GetList(context.Context, *models.AuditLogGetListRequest) (*models.AuditLogGetListResponse, error)
+}
+
+type IdempotencyKeyRepoI interface {
+	Reserve(context.Context, *models.CreateIdempotencyKey) (*models.IdempotencyKey, bool, error)
+	GetByID(context.Context, *models.IdempotencyKeyPrimaryKey) (*models.IdempotencyKey, error)
+	Save(context.Context, *models.SaveIdempotencyKey) error
+	Release(context.Context, *models.IdempotencyKeyPrimaryKey) error
+	Purge(context.Context) (int64, error)
+}
+
+type ReportRepoI interface {
+	InventoryValuation(context.Context, *models.InventoryValuationRequest) (*models.InventoryValuation, error)
+	GoodsReceipt(context.Context, *models.GoodsReceiptReportRequest) (*models.GoodsReceiptReport, error)
+	Reorder(context.Context, *models.ReorderReportRequest) (*models.ReorderReport, error)
+	Expiring(context.Context, *models.ExpiringReportRequest) (*models.ExpiringReport, error)
 }